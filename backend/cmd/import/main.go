@@ -0,0 +1,92 @@
+// Command import loads tasks from a Trello board or Jira issues JSON export.
+//
+//	go run ./cmd/import -source trello -file board.json -as admin@example.com
+//
+// Records imported before are skipped, so an import can safely be re-run.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"taskboard/config"
+	"taskboard/internal/importer"
+	"taskboard/internal/repository"
+)
+
+func main() {
+	source := flag.String("source", "", "format of the export: trello or jira")
+	file := flag.String("file", "", "path of the JSON export")
+	as := flag.String("as", "", "email of the user who creates tasks whose creator is unknown")
+	dryRun := flag.Bool("dry-run", false, "report what would be imported without writing anything")
+	flag.Parse()
+
+	if *file == "" || *as == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var parse func(io.Reader) ([]*importer.Item, error)
+	switch *source {
+	case importer.SourceTrello:
+		parse = importer.ParseTrello
+	case importer.SourceJira:
+		parse = importer.ParseJira
+	default:
+		log.Fatalf("Unknown source %q, expected trello or jira", *source)
+	}
+
+	f, err := os.Open(*file)
+	if err != nil {
+		log.Fatalf("Unable to open export: %v", err)
+	}
+	defer f.Close()
+
+	items, err := parse(f)
+	if err != nil {
+		log.Fatalf("Unable to read export: %v", err)
+	}
+
+	ctx := context.Background()
+	cfg := config.LoadConfig()
+
+	dbPool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Unable to connect to database: %v", err)
+	}
+	defer dbPool.Close()
+
+	userRepo := repository.NewUserRepository(dbPool)
+	taskRepo := repository.NewTaskRepository(dbPool)
+	importRepo := repository.NewImportRepository(dbPool)
+
+	email := strings.ToLower(*as)
+	ids, err := userRepo.IDsByEmail(ctx, []string{email})
+	if err != nil {
+		log.Fatalf("Unable to look up %s: %v", *as, err)
+	}
+	creatorID, ok := ids[email]
+	if !ok {
+		log.Fatalf("No user with email %s", *as)
+	}
+
+	imp := importer.NewImporter(userRepo, taskRepo, importRepo, repository.NewTransactor(dbPool))
+	result, err := imp.Run(ctx, *source, items, creatorID, *dryRun)
+	if err != nil {
+		log.Fatalf("Import failed: %v", err)
+	}
+
+	verb := "Imported"
+	if *dryRun {
+		verb = "Would import"
+	}
+	fmt.Printf("%s %d tasks (%d already imported), %d users matched, %d placeholder users\n",
+		verb, result.Created, result.Skipped, result.UsersMatched, result.UsersCreated)
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"log"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
@@ -15,11 +14,21 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/rs/cors"
 
+	"taskboard/config"
 	"taskboard/graph"
+	"taskboard/internal/api"
+	"taskboard/internal/attachments"
 	"taskboard/internal/auth"
+	"taskboard/internal/avatars"
 	"taskboard/internal/cache"
-	"taskboard/internal/config"
+	"taskboard/internal/calendar"
+	"taskboard/internal/jobs"
+	"taskboard/internal/mail"
+	"taskboard/internal/passwordreset"
 	"taskboard/internal/repository"
+	"taskboard/internal/storage"
+	"taskboard/internal/tasks"
+	"taskboard/internal/verification"
 )
 
 func main() {
@@ -39,33 +48,128 @@ func main() {
 	}
 	log.Println("Successfully connected to database")
 
-	// Redis connection
+	// Redis connection. Without it appCache stays nil and nothing is cached.
+	var appCache cache.Cache
 	redisCache, err := cache.NewRedisCache(cfg.RedisAddr, cfg.RedisPassword, cfg.RedisDB)
 	if err != nil {
 		log.Printf("Warning: Redis not available: %v\n", err)
 		log.Println("Continuing without cache...")
-		redisCache = nil
 	} else {
+		appCache = redisCache
 		log.Println("Successfully connected to Redis")
 	}
 
 	// JWT Manager
 	jwtManager := auth.NewJWTManager(cfg.JWTSecret, cfg.JWTRefreshSecret)
-	authMiddleware := auth.NewAuthMiddleware(jwtManager)
 
 	// Repositories
 	userRepo := repository.NewUserRepository(dbPool)
 	taskRepo := repository.NewTaskRepository(dbPool)
+	timeEntryRepo := repository.NewTimeEntryRepository(dbPool)
+	sprintRepo := repository.NewSprintRepository(dbPool)
+	analyticsRepo := repository.NewAnalyticsRepository(dbPool)
+	attachmentRepo := repository.NewAttachmentRepository(dbPool)
+	viewRepo := repository.NewViewRepository(dbPool)
+	watcherRepo := repository.NewWatcherRepository(dbPool)
+	mentionRepo := repository.NewMentionRepository(dbPool)
+	tokenRepo := repository.NewRefreshTokenRepository(dbPool)
+	sessionRepo := repository.NewSessionRepository(dbPool)
+	resetRepo := repository.NewPasswordResetRepository(dbPool)
+	transactor := repository.NewTransactor(dbPool)
+
+	// Attachment storage
+	var blobs storage.Blob
+	switch cfg.StorageBackend {
+	case "s3":
+		blobs, err = storage.NewS3Blob(cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey)
+	default:
+		blobs, err = storage.NewLocalBlob(cfg.StorageLocalDir)
+	}
+	if err != nil {
+		log.Fatalf("Unable to set up attachment storage: %v\n", err)
+	}
+	attachmentService := attachments.NewService(
+		attachmentRepo, blobs,
+		storage.NewURLSigner(cfg.DownloadURLSecret, cfg.DownloadURLTTL),
+		attachments.Limits{MaxBytes: cfg.AttachmentMaxBytes, AllowedTypes: cfg.AttachmentAllowedTypes},
+	)
+
+	avatarService := avatars.NewService(blobs, cfg.AvatarMaxBytes)
+
+	// Account email
+	var mailer mail.Sender
+	switch cfg.MailBackend {
+	case "smtp":
+		mailer = mail.NewSMTPSender(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.MailFrom)
+	default:
+		mailer, err = mail.NewFileSender(cfg.MailDir)
+		if err != nil {
+			log.Fatalf("Unable to set up mail: %v\n", err)
+		}
+	}
+	verificationService := verification.NewService(cfg.EmailVerificationSecret, cfg.EmailVerificationTTL, mailer, cfg.AppURL, cfg.RequireEmailVerification)
+	// Reset requests are counted in Redis so the limits hold across servers;
+	// without it, each server counts its own
+	resetLimits := appCache
+	if resetLimits == nil {
+		resetLimits = cache.NewMemoryCache()
+	}
+	passwordResetService := passwordreset.NewService(resetRepo, mailer, resetLimits, cfg.AppURL, cfg.PasswordResetTTL)
+
+	// Task writes, shared by the GraphQL and REST APIs
+	taskService := tasks.NewService(userRepo, taskRepo, mentionRepo, transactor)
+
+	// Background jobs
+	go jobs.Every(context.Background(), "recurrence", time.Minute, jobs.SpawnRecurringTasks(taskRepo, appCache))
+	go jobs.Every(context.Background(), "trash", time.Hour, jobs.PurgeDeletedTasks(taskRepo, attachmentService, cfg.TrashRetention))
+	go jobs.Every(context.Background(), "refresh-tokens", time.Hour, jobs.PurgeExpiredRefreshTokens(tokenRepo))
+	go jobs.Every(context.Background(), "sessions", time.Hour, jobs.PurgeInactiveSessions(sessionRepo, jwtManager.RefreshDuration()))
+	go jobs.Every(context.Background(), "password-resets", time.Hour, jobs.PurgeExpiredPasswordResets(resetRepo))
+	if cfg.ArchiveAfter > 0 {
+		go jobs.Every(context.Background(), "archive", time.Hour, jobs.ArchiveCompletedTasks(taskRepo, appCache, cfg.ArchiveAfter))
+	}
+
+	// Auth middleware, rejecting access tokens of revoked sessions
+	authMiddleware := auth.NewAuthMiddleware(jwtManager, auth.NewSessionChecker(sessionRepo, appCache), cfg.TrustProxy)
 
 	// GraphQL resolver
-	resolver := graph.NewResolver(userRepo, taskRepo, redisCache, jwtManager)
+	resolver := graph.NewResolver(graph.Dependencies{
+		UserRepo:       userRepo,
+		TaskRepo:       taskRepo,
+		TimeEntryRepo:  timeEntryRepo,
+		SprintRepo:     sprintRepo,
+		AnalyticsRepo:  analyticsRepo,
+		ViewRepo:       viewRepo,
+		WatcherRepo:    watcherRepo,
+		MentionRepo:    mentionRepo,
+		TokenRepo:      tokenRepo,
+		SessionRepo:    sessionRepo,
+		Transactor:     transactor,
+		Tasks:          taskService,
+		Attachments:    attachmentService,
+		Avatars:        avatarService,
+		Verifications:  verificationService,
+		PasswordResets: passwordResetService,
+		Cache:          appCache,
+		JWTManager:     jwtManager,
+	})
 
 	// GraphQL server
 	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolver}))
+	srv.AroundOperations(graph.MemoizeOperations)
 
 	// Add transports
 	srv.AddTransport(transport.POST{})
 	srv.AddTransport(transport.GET{})
+	maxUpload := cfg.AttachmentMaxBytes
+	if cfg.AvatarMaxBytes > maxUpload {
+		maxUpload = cfg.AvatarMaxBytes
+	}
+	srv.AddTransport(transport.MultipartForm{
+		// Leave room for the operations and map fields next to the file
+		MaxUploadSize: maxUpload + 1<<20,
+		MaxMemory:     32 << 20,
+	})
 	srv.AddTransport(transport.Websocket{
 		KeepAlivePingInterval: 10 * time.Second,
 		Upgrader: websocket.Upgrader{
@@ -84,8 +188,9 @@ func main() {
 	// CORS configuration
 	corsHandler := cors.New(cors.Options{
 		AllowedOrigins:   cfg.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Content-Type", "Authorization"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowedHeaders:   []string{"Content-Type", "Authorization", "If-Match", "If-None-Match"},
+		ExposedHeaders:   []string{"ETag", "Location"},
 		AllowCredentials: true,
 		Debug:            cfg.Env == "development",
 	})
@@ -102,6 +207,19 @@ func main() {
 	// GraphQL endpoint with auth middleware
 	mux.Handle("/query", corsHandler.Handler(authMiddleware.Middleware(srv)))
 
+	// REST API with the same auth middleware
+	apiHandler := api.NewHandler(userRepo, taskRepo, taskService, avatarService, appCache, cfg.RequireEmailVerification)
+	mux.Handle(api.BasePath+"/", corsHandler.Handler(authMiddleware.Middleware(apiHandler)))
+
+	// iCalendar feeds authenticate with the token in their URL
+	mux.Handle(calendar.BasePath, calendar.NewHandler(userRepo, taskRepo))
+
+	// Attachment downloads authenticate with a signature in their URL
+	mux.Handle(attachments.DownloadPath, attachments.NewHandler(attachmentService))
+
+	// Avatar thumbnails are public
+	mux.Handle(avatars.BasePath, avatars.NewHandler(avatarService))
+
 	// Health check endpoint
 	mux.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type Config struct {
@@ -23,9 +24,60 @@ type Config struct {
 	Port string
 	Host string
 	Env  string
+	// Whether requests arrive through a reverse proxy that sets
+	// X-Forwarded-For, which is then trusted for client addresses
+	TrustProxy bool
 
 	// CORS
 	AllowedOrigins []string
+
+	// Attachment storage: "local" keeps files under StorageLocalDir, "s3"
+	// uses any S3-compatible object store
+	StorageBackend  string
+	StorageLocalDir string
+	S3Endpoint      string
+	S3Region        string
+	S3Bucket        string
+	S3AccessKey     string
+	S3SecretKey     string
+
+	// Attachment limits
+	AttachmentMaxBytes     int64
+	AttachmentAllowedTypes []string
+
+	// Largest accepted avatar upload
+	AvatarMaxBytes int64
+
+	// Signed download URLs
+	DownloadURLSecret string
+	DownloadURLTTL    time.Duration
+
+	// Account email: "file" writes messages to MailDir, "smtp" sends them
+	MailBackend  string
+	MailDir      string
+	MailFrom     string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	// Where the frontend is served, for links in email
+	AppURL string
+
+	// Email verification links, and whether users must verify their address
+	// before changing tasks
+	EmailVerificationSecret  string
+	EmailVerificationTTL     time.Duration
+	RequireEmailVerification bool
+
+	// How long password reset links work
+	PasswordResetTTL time.Duration
+
+	// How long deleted tasks stay in the trash before they are purged
+	TrashRetention time.Duration
+
+	// How long tasks stay DONE before they are archived; zero disables it
+	ArchiveAfter time.Duration
 }
 
 func LoadConfig() *Config {
@@ -39,7 +91,45 @@ func LoadConfig() *Config {
 		Port:             getEnv("PORT", "8080"),
 		Host:             getEnv("HOST", "0.0.0.0"),
 		Env:              getEnv("ENV", "development"),
+		TrustProxy:       getEnv("TRUST_PROXY", "false") == "true",
 		AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"http://localhost:5173", "http://localhost:3000"}),
+
+		StorageBackend:  getEnv("STORAGE_BACKEND", "local"),
+		StorageLocalDir: getEnv("STORAGE_LOCAL_DIR", "./data/uploads"),
+		S3Endpoint:      getEnv("S3_ENDPOINT", "http://localhost:9000"),
+		S3Region:        getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:        getEnv("S3_BUCKET", "taskboard"),
+		S3AccessKey:     getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:     getEnv("S3_SECRET_KEY", ""),
+
+		AttachmentMaxBytes: int64(getEnvAsInt("ATTACHMENT_MAX_BYTES", 10<<20)),
+		AttachmentAllowedTypes: getEnvAsSlice("ATTACHMENT_ALLOWED_TYPES", []string{
+			"image/png", "image/jpeg", "image/gif", "image/webp",
+			"application/pdf", "text/plain", "application/zip", "application/x-gzip",
+		}),
+		AvatarMaxBytes: int64(getEnvAsInt("AVATAR_MAX_BYTES", 5<<20)),
+
+		DownloadURLSecret: getEnv("DOWNLOAD_URL_SECRET", "your-super-secret-download-key-change-this-in-production"),
+		DownloadURLTTL:    time.Duration(getEnvAsInt("DOWNLOAD_URL_TTL_MINUTES", 15)) * time.Minute,
+
+		MailBackend:  getEnv("MAIL_BACKEND", "file"),
+		MailDir:      getEnv("MAIL_DIR", "./data/mail"),
+		MailFrom:     getEnv("MAIL_FROM", "Taskboard <no-reply@localhost>"),
+		SMTPHost:     getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername: getEnv("SMTP_USERNAME", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+
+		AppURL: getEnv("APP_URL", "http://localhost:5173"),
+
+		EmailVerificationSecret:  getEnv("EMAIL_VERIFICATION_SECRET", "your-super-secret-verification-key-change-this-in-production"),
+		EmailVerificationTTL:     time.Duration(getEnvAsInt("EMAIL_VERIFICATION_TTL_HOURS", 24)) * time.Hour,
+		RequireEmailVerification: getEnv("REQUIRE_EMAIL_VERIFICATION", "false") == "true",
+
+		PasswordResetTTL: time.Duration(getEnvAsInt("PASSWORD_RESET_TTL_MINUTES", 30)) * time.Minute,
+
+		TrashRetention: time.Duration(getEnvAsInt("TRASH_RETENTION_DAYS", 30)) * 24 * time.Hour,
+		ArchiveAfter:   time.Duration(getEnvAsInt("ARCHIVE_DONE_AFTER_DAYS", 14)) * 24 * time.Hour,
 	}
 
 	return cfg
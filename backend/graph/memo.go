@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"context"
+	"sync"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type memoKey struct{}
+
+// memo remembers values loaded during one operation, so sibling fields
+// resolved from the same object share one load
+type memo struct {
+	mu      sync.Mutex
+	entries map[memoEntryKey]*memoEntry
+}
+
+type memoEntryKey struct {
+	// object is a pointer, so objects with equal contents, like a sprint
+	// returned by two mutations, are kept apart
+	object interface{}
+	name   string
+}
+
+type memoEntry struct {
+	once  sync.Once
+	value interface{}
+	err   error
+}
+
+// WithMemo returns a context for one operation in which loads done through
+// memoize happen once
+func WithMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoKey{}, &memo{entries: make(map[memoEntryKey]*memoEntry)})
+}
+
+// MemoizeOperations is an operation middleware giving every operation a memo
+func MemoizeOperations(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	return next(WithMemo(ctx))
+}
+
+// memoize returns what load returned for name of object earlier in the
+// operation, calling it the first time. Without a memo in ctx it calls load
+// every time.
+func memoize(ctx context.Context, object interface{}, name string, load func() (interface{}, error)) (interface{}, error) {
+	m, ok := ctx.Value(memoKey{}).(*memo)
+	if !ok {
+		return load()
+	}
+
+	key := memoEntryKey{object: object, name: name}
+	m.mu.Lock()
+	entry, ok := m.entries[key]
+	if !ok {
+		entry = &memoEntry{}
+		m.entries[key] = entry
+	}
+	m.mu.Unlock()
+
+	entry.once.Do(func() {
+		entry.value, entry.err = load()
+	})
+	return entry.value, entry.err
+}
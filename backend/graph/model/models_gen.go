@@ -0,0 +1,533 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"taskboard/internal/models"
+	"time"
+)
+
+type AddTimeEntryInput struct {
+	TaskID    string    `json:"taskId"`
+	StartedAt time.Time `json:"startedAt"`
+	EndedAt   time.Time `json:"endedAt"`
+	Note      *string   `json:"note,omitempty"`
+}
+
+type AuthPayload struct {
+	Token        string `json:"token"`
+	RefreshToken string `json:"refreshToken"`
+	User         *User  `json:"user"`
+}
+
+type BulkTaskPayload struct {
+	Results   []*BulkTaskResult `json:"results"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+}
+
+type BulkTaskResult struct {
+	ID    string  `json:"id"`
+	Task  *Task   `json:"task,omitempty"`
+	Error *string `json:"error,omitempty"`
+}
+
+type CalendarFeed struct {
+	URL string `json:"url"`
+}
+
+type CreateSprintInput struct {
+	Name      string    `json:"name"`
+	Goal      *string   `json:"goal,omitempty"`
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"`
+}
+
+type CreateTaskInput struct {
+	Title          string      `json:"title"`
+	Description    *string     `json:"description,omitempty"`
+	Status         *TaskStatus `json:"status,omitempty"`
+	Priority       *Priority   `json:"priority,omitempty"`
+	AssignedToID   *string     `json:"assignedToId,omitempty"`
+	DueDate        *time.Time  `json:"dueDate,omitempty"`
+	Recurrence     *string     `json:"recurrence,omitempty"`
+	StoryPoints    *int        `json:"storyPoints,omitempty"`
+	EstimatedHours *float64    `json:"estimatedHours,omitempty"`
+}
+
+type ExportPayload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+type LoginInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
+type Query struct {
+}
+
+type RegisterInput struct {
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+}
+
+type SaveViewInput struct {
+	Name   string           `json:"name"`
+	Filter *TaskFilterInput `json:"filter,omitempty"`
+	Sort   *TaskSortInput   `json:"sort,omitempty"`
+	Shared bool             `json:"shared"`
+	Pinned bool             `json:"pinned"`
+}
+
+type Session struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastSeenAt time.Time `json:"lastSeenAt"`
+	Current    bool      `json:"current"`
+}
+
+type Subscription struct {
+}
+
+type Task struct {
+	ID              string               `json:"id"`
+	Title           string               `json:"title"`
+	Description     *string              `json:"description,omitempty"`
+	DescriptionHTML *string              `json:"descriptionHtml,omitempty"`
+	Status          TaskStatus           `json:"status"`
+	Priority        Priority             `json:"priority"`
+	CreatedBy       *User                `json:"createdBy"`
+	AssignedTo      *User                `json:"assignedTo,omitempty"`
+	CreatedAt       time.Time            `json:"createdAt"`
+	UpdatedAt       time.Time            `json:"updatedAt"`
+	DueDate         *time.Time           `json:"dueDate,omitempty"`
+	Recurrence      *string              `json:"recurrence,omitempty"`
+	TimeEntries     []*models.TimeEntry  `json:"timeEntries"`
+	TotalTimeSpent  int                  `json:"totalTimeSpent"`
+	StoryPoints     *int                 `json:"storyPoints,omitempty"`
+	EstimatedHours  *float64             `json:"estimatedHours,omitempty"`
+	CompletedAt     *time.Time           `json:"completedAt,omitempty"`
+	ArchivedAt      *time.Time           `json:"archivedAt,omitempty"`
+	DeletedAt       *time.Time           `json:"deletedAt,omitempty"`
+	Attachments     []*models.Attachment `json:"attachments"`
+	Watchers        []*User              `json:"watchers"`
+	Mentions        []*models.Mention    `json:"mentions"`
+}
+
+type TaskConnection struct {
+	Edges      []*TaskEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
+}
+
+type TaskEdge struct {
+	Cursor string `json:"cursor"`
+	Node   *Task  `json:"node"`
+}
+
+type TaskFilter struct {
+	Status          *TaskStatus   `json:"status,omitempty"`
+	Priority        *Priority     `json:"priority,omitempty"`
+	AssignedToID    *string       `json:"assignedToId,omitempty"`
+	CreatedByID     *string       `json:"createdById,omitempty"`
+	IncludeArchived bool          `json:"includeArchived"`
+	StatusIn        []TaskStatus  `json:"statusIn,omitempty"`
+	PriorityIn      []Priority    `json:"priorityIn,omitempty"`
+	DueBefore       *time.Time    `json:"dueBefore,omitempty"`
+	DueAfter        *time.Time    `json:"dueAfter,omitempty"`
+	Overdue         *bool         `json:"overdue,omitempty"`
+	Unassigned      *bool         `json:"unassigned,omitempty"`
+	CreatedAfter    *time.Time    `json:"createdAfter,omitempty"`
+	UpdatedSince    *time.Time    `json:"updatedSince,omitempty"`
+	TitleContains   *string       `json:"titleContains,omitempty"`
+	And             []*TaskFilter `json:"and,omitempty"`
+	Or              []*TaskFilter `json:"or,omitempty"`
+	Not             *TaskFilter   `json:"not,omitempty"`
+}
+
+type TaskFilterInput struct {
+	Status          *TaskStatus        `json:"status,omitempty"`
+	Priority        *Priority          `json:"priority,omitempty"`
+	AssignedToID    *string            `json:"assignedToId,omitempty"`
+	CreatedByID     *string            `json:"createdById,omitempty"`
+	IncludeArchived *bool              `json:"includeArchived,omitempty"`
+	StatusIn        []TaskStatus       `json:"statusIn,omitempty"`
+	PriorityIn      []Priority         `json:"priorityIn,omitempty"`
+	DueBefore       *time.Time         `json:"dueBefore,omitempty"`
+	DueAfter        *time.Time         `json:"dueAfter,omitempty"`
+	Overdue         *bool              `json:"overdue,omitempty"`
+	Unassigned      *bool              `json:"unassigned,omitempty"`
+	CreatedAfter    *time.Time         `json:"createdAfter,omitempty"`
+	UpdatedSince    *time.Time         `json:"updatedSince,omitempty"`
+	TitleContains   *string            `json:"titleContains,omitempty"`
+	And             []*TaskFilterInput `json:"and,omitempty"`
+	Or              []*TaskFilterInput `json:"or,omitempty"`
+	Not             *TaskFilterInput   `json:"not,omitempty"`
+}
+
+type TaskSort struct {
+	Field     TaskSortField `json:"field"`
+	Direction SortDirection `json:"direction"`
+}
+
+type TaskSortInput struct {
+	Field     TaskSortField `json:"field"`
+	Direction SortDirection `json:"direction"`
+}
+
+type UpdateSprintInput struct {
+	Name      *string    `json:"name,omitempty"`
+	Goal      *string    `json:"goal,omitempty"`
+	StartDate *time.Time `json:"startDate,omitempty"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+}
+
+type UpdateTaskInput struct {
+	Title          *string     `json:"title,omitempty"`
+	Description    *string     `json:"description,omitempty"`
+	Status         *TaskStatus `json:"status,omitempty"`
+	Priority       *Priority   `json:"priority,omitempty"`
+	AssignedToID   *string     `json:"assignedToId,omitempty"`
+	DueDate        *time.Time  `json:"dueDate,omitempty"`
+	Recurrence     *string     `json:"recurrence,omitempty"`
+	StoryPoints    *int        `json:"storyPoints,omitempty"`
+	EstimatedHours *float64    `json:"estimatedHours,omitempty"`
+}
+
+type UpdateViewInput struct {
+	Name   *string          `json:"name,omitempty"`
+	Filter *TaskFilterInput `json:"filter,omitempty"`
+	Sort   *TaskSortInput   `json:"sort,omitempty"`
+	Shared *bool            `json:"shared,omitempty"`
+	Pinned *bool            `json:"pinned,omitempty"`
+}
+
+type User struct {
+	ID            string    `json:"id"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Name          string    `json:"name"`
+	Avatar        *string   `json:"avatar,omitempty"`
+	AvatarURL     *string   `json:"avatarUrl,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+type ExportFormat string
+
+const (
+	ExportFormatCSV   ExportFormat = "CSV"
+	ExportFormatJSONL ExportFormat = "JSONL"
+)
+
+var AllExportFormat = []ExportFormat{
+	ExportFormatCSV,
+	ExportFormatJSONL,
+}
+
+func (e ExportFormat) IsValid() bool {
+	switch e {
+	case ExportFormatCSV, ExportFormatJSONL:
+		return true
+	}
+	return false
+}
+
+func (e ExportFormat) String() string {
+	return string(e)
+}
+
+func (e *ExportFormat) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ExportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ExportFormat", str)
+	}
+	return nil
+}
+
+func (e ExportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type Priority string
+
+const (
+	PriorityLow    Priority = "LOW"
+	PriorityMedium Priority = "MEDIUM"
+	PriorityHigh   Priority = "HIGH"
+	PriorityUrgent Priority = "URGENT"
+)
+
+var AllPriority = []Priority{
+	PriorityLow,
+	PriorityMedium,
+	PriorityHigh,
+	PriorityUrgent,
+}
+
+func (e Priority) IsValid() bool {
+	switch e {
+	case PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent:
+		return true
+	}
+	return false
+}
+
+func (e Priority) String() string {
+	return string(e)
+}
+
+func (e *Priority) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = Priority(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid Priority", str)
+	}
+	return nil
+}
+
+func (e Priority) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type ScopeChange string
+
+const (
+	ScopeChangeAdded   ScopeChange = "ADDED"
+	ScopeChangeRemoved ScopeChange = "REMOVED"
+)
+
+var AllScopeChange = []ScopeChange{
+	ScopeChangeAdded,
+	ScopeChangeRemoved,
+}
+
+func (e ScopeChange) IsValid() bool {
+	switch e {
+	case ScopeChangeAdded, ScopeChangeRemoved:
+		return true
+	}
+	return false
+}
+
+func (e ScopeChange) String() string {
+	return string(e)
+}
+
+func (e *ScopeChange) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ScopeChange(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ScopeChange", str)
+	}
+	return nil
+}
+
+func (e ScopeChange) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type SortDirection string
+
+const (
+	SortDirectionAsc  SortDirection = "ASC"
+	SortDirectionDesc SortDirection = "DESC"
+)
+
+var AllSortDirection = []SortDirection{
+	SortDirectionAsc,
+	SortDirectionDesc,
+}
+
+func (e SortDirection) IsValid() bool {
+	switch e {
+	case SortDirectionAsc, SortDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e SortDirection) String() string {
+	return string(e)
+}
+
+func (e *SortDirection) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SortDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SortDirection", str)
+	}
+	return nil
+}
+
+func (e SortDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TaskSortField string
+
+const (
+	TaskSortFieldCreatedAt TaskSortField = "CREATED_AT"
+	TaskSortFieldUpdatedAt TaskSortField = "UPDATED_AT"
+	TaskSortFieldDueDate   TaskSortField = "DUE_DATE"
+	TaskSortFieldPriority  TaskSortField = "PRIORITY"
+	TaskSortFieldTitle     TaskSortField = "TITLE"
+)
+
+var AllTaskSortField = []TaskSortField{
+	TaskSortFieldCreatedAt,
+	TaskSortFieldUpdatedAt,
+	TaskSortFieldDueDate,
+	TaskSortFieldPriority,
+	TaskSortFieldTitle,
+}
+
+func (e TaskSortField) IsValid() bool {
+	switch e {
+	case TaskSortFieldCreatedAt, TaskSortFieldUpdatedAt, TaskSortFieldDueDate, TaskSortFieldPriority, TaskSortFieldTitle:
+		return true
+	}
+	return false
+}
+
+func (e TaskSortField) String() string {
+	return string(e)
+}
+
+func (e *TaskSortField) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskSortField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskSortField", str)
+	}
+	return nil
+}
+
+func (e TaskSortField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TaskStatus string
+
+const (
+	TaskStatusTodo       TaskStatus = "TODO"
+	TaskStatusInProgress TaskStatus = "IN_PROGRESS"
+	TaskStatusReview     TaskStatus = "REVIEW"
+	TaskStatusDone       TaskStatus = "DONE"
+)
+
+var AllTaskStatus = []TaskStatus{
+	TaskStatusTodo,
+	TaskStatusInProgress,
+	TaskStatusReview,
+	TaskStatusDone,
+}
+
+func (e TaskStatus) IsValid() bool {
+	switch e {
+	case TaskStatusTodo, TaskStatusInProgress, TaskStatusReview, TaskStatusDone:
+		return true
+	}
+	return false
+}
+
+func (e TaskStatus) String() string {
+	return string(e)
+}
+
+func (e *TaskStatus) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TaskStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TaskStatus", str)
+	}
+	return nil
+}
+
+func (e TaskStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+type TimeReportGrouping string
+
+const (
+	TimeReportGroupingTask TimeReportGrouping = "TASK"
+	TimeReportGroupingUser TimeReportGrouping = "USER"
+	TimeReportGroupingDay  TimeReportGrouping = "DAY"
+)
+
+var AllTimeReportGrouping = []TimeReportGrouping{
+	TimeReportGroupingTask,
+	TimeReportGroupingUser,
+	TimeReportGroupingDay,
+}
+
+func (e TimeReportGrouping) IsValid() bool {
+	switch e {
+	case TimeReportGroupingTask, TimeReportGroupingUser, TimeReportGroupingDay:
+		return true
+	}
+	return false
+}
+
+func (e TimeReportGrouping) String() string {
+	return string(e)
+}
+
+func (e *TimeReportGrouping) UnmarshalGQL(v interface{}) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TimeReportGrouping(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TimeReportGrouping", str)
+	}
+	return nil
+}
+
+func (e TimeReportGrouping) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
@@ -1,9 +1,14 @@
 package graph
 
 import (
+	"taskboard/internal/attachments"
 	"taskboard/internal/auth"
+	"taskboard/internal/avatars"
 	"taskboard/internal/cache"
+	"taskboard/internal/passwordreset"
 	"taskboard/internal/repository"
+	"taskboard/internal/tasks"
+	"taskboard/internal/verification"
 )
 
 // This file will not be regenerated automatically.
@@ -11,22 +16,69 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	userRepo   *repository.UserRepository
-	taskRepo   *repository.TaskRepository
-	cache      *cache.RedisCache
-	jwtManager *auth.JWTManager
+	userRepo       repository.UserStore
+	taskRepo       repository.TaskStore
+	timeEntryRepo  *repository.TimeEntryRepository
+	sprintRepo     *repository.SprintRepository
+	analyticsRepo  *repository.AnalyticsRepository
+	viewRepo       repository.ViewStore
+	watcherRepo    repository.WatcherStore
+	mentionRepo    repository.MentionStore
+	tokenRepo      repository.RefreshTokenStore
+	sessionRepo    repository.SessionStore
+	tx             repository.TxRunner
+	tasks          *tasks.Service
+	attachments    *attachments.Service
+	avatars        *avatars.Service
+	verifications  *verification.Service
+	passwordResets *passwordreset.Service
+	cache          cache.Cache
+	jwtManager     *auth.JWTManager
 }
 
-func NewResolver(
-	userRepo *repository.UserRepository,
-	taskRepo *repository.TaskRepository,
-	cache *cache.RedisCache,
-	jwtManager *auth.JWTManager,
-) *Resolver {
+// Dependencies are the stores and services a Resolver is built from. A nil
+// dependency is unavailable, and the resolvers that need it must not be
+// called.
+type Dependencies struct {
+	UserRepo       repository.UserStore
+	TaskRepo       repository.TaskStore
+	TimeEntryRepo  *repository.TimeEntryRepository
+	SprintRepo     *repository.SprintRepository
+	AnalyticsRepo  *repository.AnalyticsRepository
+	ViewRepo       repository.ViewStore
+	WatcherRepo    repository.WatcherStore
+	MentionRepo    repository.MentionStore
+	TokenRepo      repository.RefreshTokenStore
+	SessionRepo    repository.SessionStore
+	Transactor     repository.TxRunner
+	Tasks          *tasks.Service
+	Attachments    *attachments.Service
+	Avatars        *avatars.Service
+	Verifications  *verification.Service
+	PasswordResets *passwordreset.Service
+	Cache          cache.Cache
+	JWTManager     *auth.JWTManager
+}
+
+func NewResolver(deps Dependencies) *Resolver {
 	return &Resolver{
-		userRepo:   userRepo,
-		taskRepo:   taskRepo,
-		cache:      cache,
-		jwtManager: jwtManager,
+		userRepo:       deps.UserRepo,
+		taskRepo:       deps.TaskRepo,
+		timeEntryRepo:  deps.TimeEntryRepo,
+		sprintRepo:     deps.SprintRepo,
+		analyticsRepo:  deps.AnalyticsRepo,
+		viewRepo:       deps.ViewRepo,
+		watcherRepo:    deps.WatcherRepo,
+		mentionRepo:    deps.MentionRepo,
+		tokenRepo:      deps.TokenRepo,
+		sessionRepo:    deps.SessionRepo,
+		tx:             deps.Transactor,
+		tasks:          deps.Tasks,
+		attachments:    deps.Attachments,
+		avatars:        deps.Avatars,
+		verifications:  deps.Verifications,
+		passwordResets: deps.PasswordResets,
+		cache:          deps.Cache,
+		jwtManager:     deps.JWTManager,
 	}
 }
\ No newline at end of file
@@ -6,15 +6,66 @@ package graph
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"taskboard/graph/model"
+	"taskboard/internal/api"
+	"taskboard/internal/attachments"
 	"taskboard/internal/auth"
+	"taskboard/internal/avatars"
 	"taskboard/internal/cache"
+	"taskboard/internal/calendar"
+	"taskboard/internal/export"
+	"taskboard/internal/markdown"
 	"taskboard/internal/models"
+	"taskboard/internal/repository"
+	"taskboard/internal/tasks"
+	"taskboard/internal/verification"
+	"taskboard/internal/views"
 )
 
+// Uploader is the resolver for the uploader field.
+func (r *attachmentResolver) Uploader(ctx context.Context, obj *models.Attachment) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.UploaderID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// URL is the resolver for the url field.
+func (r *attachmentResolver) URL(ctx context.Context, obj *models.Attachment) (string, error) {
+	return r.attachments.DownloadURL(obj), nil
+}
+
+// User is the resolver for the user field.
+func (r *mentionResolver) User(ctx context.Context, obj *models.Mention) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// MentionedBy is the resolver for the mentionedBy field.
+func (r *mentionResolver) MentionedBy(ctx context.Context, obj *models.Mention) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.MentionedByID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
 // Register is the resolver for the register field.
 func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInput) (*model.AuthPayload, error) {
 	// Check if email already exists
@@ -44,22 +95,12 @@ func (r *mutationResolver) Register(ctx context.Context, input model.RegisterInp
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
-	// Generate tokens
-	accessToken, err := r.jwtManager.GenerateAccessToken(user.ID, user.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
-	}
-
-	refreshToken, err := r.jwtManager.GenerateRefreshToken(user.ID, user.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	// A failed send is not fatal: the user can ask for another link
+	if err := r.verifications.Send(ctx, user); err != nil {
+		log.Printf("failed to send verification email to user %s: %v", user.ID, err)
 	}
 
-	return &model.AuthPayload{
-		Token:        accessToken,
-		RefreshToken: refreshToken,
-		User:         toGraphQLUser(user),
-	}, nil
+	return r.startSession(ctx, user)
 }
 
 // Login is the resolver for the login field.
@@ -75,22 +116,7 @@ func (r *mutationResolver) Login(ctx context.Context, input model.LoginInput) (*
 		return nil, fmt.Errorf("invalid credentials")
 	}
 
-	// Generate tokens
-	accessToken, err := r.jwtManager.GenerateAccessToken(user.ID, user.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
-	}
-
-	refreshToken, err := r.jwtManager.GenerateRefreshToken(user.ID, user.Email)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-	}
-
-	return &model.AuthPayload{
-		Token:        accessToken,
-		RefreshToken: refreshToken,
-		User:         toGraphQLUser(user),
-	}, nil
+	return r.startSession(ctx, user)
 }
 
 // RefreshToken is the resolver for the refreshToken field.
@@ -101,66 +127,214 @@ func (r *mutationResolver) RefreshToken(ctx context.Context, refreshToken string
 		return nil, fmt.Errorf("invalid refresh token")
 	}
 
+	// Exchange it. Use is not run in a transaction: when the token was used
+	// before, the revocation of its family has to stick.
+	stored, err := r.tokenRepo.Use(ctx, auth.HashOpaqueToken(refreshToken))
+	if errors.Is(err, repository.ErrRefreshTokenReused) {
+		log.Printf("refresh token reused for user %s, ending its session", claims.UserID)
+		if reused, err := r.tokenRepo.GetByHash(ctx, auth.HashOpaqueToken(refreshToken)); err == nil {
+			if err := r.endSession(ctx, reused.FamilyID); err != nil {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if errors.Is(err, repository.ErrRefreshTokenInvalid) {
+		return nil, fmt.Errorf("invalid refresh token")
+	}
+	if err != nil {
+		return nil, err
+	}
+
 	// Get user
-	user, err := r.userRepo.GetByID(ctx, claims.UserID)
+	user, err := r.userRepo.GetByID(ctx, stored.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("user not found")
 	}
 
-	// Generate new tokens
-	newAccessToken, err := r.jwtManager.GenerateAccessToken(user.ID, user.Email)
+	// The refresh token family is the session
+	client := auth.ClientFromContext(ctx)
+	if err := r.sessionRepo.Touch(ctx, stored.FamilyID, client.UserAgent, client.IP); err != nil {
+		return nil, err
+	}
+
+	// Generate new tokens for the same session
+	return r.issueTokens(ctx, user, stored.FamilyID)
+}
+
+// Logout is the resolver for the logout field.
+func (r *mutationResolver) Logout(ctx context.Context, refreshToken string) (bool, error) {
+	// The access token may have expired by now, so the refresh token alone
+	// identifies the login to end
+	stored, err := r.tokenRepo.GetByHash(ctx, auth.HashOpaqueToken(refreshToken))
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate access token: %w", err)
+		return false, fmt.Errorf("invalid refresh token")
 	}
 
-	newRefreshToken, err := r.jwtManager.GenerateRefreshToken(user.ID, user.Email)
+	if err := r.endSession(ctx, stored.FamilyID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// LogoutAllDevices is the resolver for the logoutAllDevices field.
+func (r *mutationResolver) LogoutAllDevices(ctx context.Context) (bool, error) {
+	claims, err := auth.RequireAuth(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return false, fmt.Errorf("unauthorized")
 	}
 
-	return &model.AuthPayload{
-		Token:        newAccessToken,
-		RefreshToken: newRefreshToken,
-		User:         toGraphQLUser(user),
-	}, nil
+	if err := r.endAllSessions(ctx, claims.UserID); err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-// CreateTask is the resolver for the createTask field.
-func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTaskInput) (*model.Task, error) {
+// RevokeSession is the resolver for the revokeSession field.
+func (r *mutationResolver) RevokeSession(ctx context.Context, id string) (bool, error) {
 	claims, err := auth.RequireAuth(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+		return false, fmt.Errorf("unauthorized")
 	}
 
-	status := model.TaskStatusTodo
-	if input.Status != nil {
-		status = *input.Status
+	session, err := r.sessionRepo.GetByID(ctx, id)
+	if err != nil || session.UserID != claims.UserID {
+		return false, fmt.Errorf("session not found")
 	}
 
-	priority := model.PriorityMedium
-	if input.Priority != nil {
-		priority = *input.Priority
+	if err := r.endSession(ctx, session.ID); err != nil {
+		return false, err
 	}
 
-	task := &models.Task{
-		Title:       input.Title,
-		Description: input.Description,
-		Status:      string(status),
-		Priority:    string(priority),
-		CreatedByID: claims.UserID,
+	return true, nil
+}
+
+// VerifyEmail is the resolver for the verifyEmail field.
+func (r *mutationResolver) VerifyEmail(ctx context.Context, token string) (bool, error) {
+	userID, email, err := r.verifications.Check(token, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	verified, err := r.userRepo.MarkEmailVerified(ctx, userID, email)
+	if err != nil {
+		return false, err
+	}
+	if !verified {
+		return false, fmt.Errorf("verification link has already been used")
+	}
+
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.UserKey(userID))
+	}
+
+	return true, nil
+}
+
+// ResendVerification is the resolver for the resendVerification field.
+func (r *mutationResolver) ResendVerification(ctx context.Context) (bool, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return false, fmt.Errorf("unauthorized")
+	}
+
+	user, err := r.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return false, fmt.Errorf("user not found")
+	}
+	if user.EmailVerifiedAt != nil {
+		return false, fmt.Errorf("email address is already verified")
+	}
+
+	if err := r.verifications.Send(ctx, user); err != nil {
+		return false, fmt.Errorf("failed to send verification email: %w", err)
+	}
+
+	return true, nil
+}
+
+// RequestPasswordReset is the resolver for the requestPasswordReset field.
+func (r *mutationResolver) RequestPasswordReset(ctx context.Context, email string) (bool, error) {
+	// Whether the address has an account, and whether sending worked, must not
+	// show in the response or in how long it takes
+	if err := r.passwordResets.Allow(ctx, email, auth.ClientFromContext(ctx).IP); err != nil {
+		return false, err
+	}
+
+	user, err := r.userRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return true, nil
+	}
+
+	r.passwordResets.SendLater(user)
+
+	return true, nil
+}
+
+// ResetPassword is the resolver for the resetPassword field.
+func (r *mutationResolver) ResetPassword(ctx context.Context, token string, newPassword string) (bool, error) {
+	// Check the password first so a rejected one does not spend the token
+	if err := auth.ValidatePassword(newPassword); err != nil {
+		return false, err
+	}
+
+	hash, err := auth.HashPassword(newPassword)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash password: %w", err)
 	}
 
-	if input.AssignedToID != nil {
-		task.AssignedToID = input.AssignedToID
+	var userID string
+	var sessionIDs []string
+	err = r.tx.WithTx(ctx, func(ctx context.Context) error {
+		userID, err = r.passwordResets.Redeem(ctx, token)
+		if err != nil {
+			return err
+		}
+
+		if err := r.userRepo.SetPasswordHash(ctx, userID, hash); err != nil {
+			return err
+		}
+
+		// Whoever knew the old password is signed out everywhere
+		sessionIDs, err = r.revokeAllSessions(ctx, userID)
+		return err
+	})
+	if err != nil {
+		return false, err
 	}
 
-	if input.DueDate != nil {
-		task.DueDate = input.DueDate
+	// Only once the revocation is committed, or the middleware could cache
+	// the sessions as active again before it is
+	r.forgetSessions(ctx, sessionIDs)
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.UserKey(userID))
 	}
 
-	task, err = r.taskRepo.Create(ctx, task)
+	return true, nil
+}
+
+// CreateTask is the resolver for the createTask field.
+func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTaskInput) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create task: %w", err)
+		return nil, err
+	}
+
+	task, err := r.tasks.Create(ctx, claims.UserID, tasks.CreateInput{
+		Title:          input.Title,
+		Description:    input.Description,
+		Status:         (*string)(input.Status),
+		Priority:       (*string)(input.Priority),
+		AssignedToID:   input.AssignedToID,
+		DueDate:        input.DueDate,
+		Recurrence:     input.Recurrence,
+		StoryPoints:    input.StoryPoints,
+		EstimatedHours: input.EstimatedHours,
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Invalidate cache
@@ -174,9 +348,9 @@ func (r *mutationResolver) CreateTask(ctx context.Context, input model.CreateTas
 
 // UpdateTask is the resolver for the updateTask field.
 func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input model.UpdateTaskInput) (*model.Task, error) {
-	claims, err := auth.RequireAuth(ctx)
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, err
 	}
 
 	// Get existing task
@@ -186,34 +360,13 @@ func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input mode
 	}
 
 	// Check ownership
-	if existingTask.CreatedByID != claims.UserID {
+	if !auth.CanModifyTask(claims, existingTask) {
 		return nil, fmt.Errorf("unauthorized: you can only update your own tasks")
 	}
 
-	// Build updates map
-	updates := make(map[string]interface{})
-	if input.Title != nil {
-		updates["title"] = *input.Title
-	}
-	if input.Description != nil {
-		updates["description"] = input.Description
-	}
-	if input.Status != nil {
-		updates["status"] = string(*input.Status)
-	}
-	if input.Priority != nil {
-		updates["priority"] = string(*input.Priority)
-	}
-	if input.AssignedToID != nil {
-		updates["assigned_to_id"] = input.AssignedToID
-	}
-	if input.DueDate != nil {
-		updates["due_date"] = input.DueDate
-	}
-
-	task, err := r.taskRepo.Update(ctx, id, updates)
+	task, err := r.tasks.Update(ctx, claims.UserID, existingTask, taskUpdateInput(input))
 	if err != nil {
-		return nil, fmt.Errorf("failed to update task: %w", err)
+		return nil, err
 	}
 
 	// Invalidate cache
@@ -227,9 +380,9 @@ func (r *mutationResolver) UpdateTask(ctx context.Context, id string, input mode
 
 // DeleteTask is the resolver for the deleteTask field.
 func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, error) {
-	claims, err := auth.RequireAuth(ctx)
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return false, fmt.Errorf("unauthorized")
+		return false, err
 	}
 
 	// Get task to check ownership
@@ -238,7 +391,7 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, err
 		return false, fmt.Errorf("task not found")
 	}
 
-	if task.CreatedByID != claims.UserID {
+	if !auth.CanModifyTask(claims, task) {
 		return false, fmt.Errorf("unauthorized: you can only delete your own tasks")
 	}
 
@@ -256,51 +409,67 @@ func (r *mutationResolver) DeleteTask(ctx context.Context, id string) (bool, err
 	return true, nil
 }
 
-// AssignTask is the resolver for the assignTask field.
-func (r *mutationResolver) AssignTask(ctx context.Context, taskID string, userID string) (*model.Task, error) {
-	_, err := auth.RequireAuth(ctx)
+// RestoreTask is the resolver for the restoreTask field.
+func (r *mutationResolver) RestoreTask(ctx context.Context, id string) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, err
 	}
 
-	// Verify user exists
-	_, err = r.userRepo.GetByID(ctx, userID)
+	task, err := r.taskRepo.GetDeleted(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, fmt.Errorf("task not found")
 	}
 
-	updates := map[string]interface{}{
-		"assigned_to_id": &userID,
+	if !auth.CanModifyTask(claims, task) {
+		return nil, fmt.Errorf("unauthorized: you can only restore your own tasks")
 	}
 
-	task, err := r.taskRepo.Update(ctx, taskID, updates)
+	restored, err := r.taskRepo.Restore(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("failed to assign task: %w", err)
+		return nil, fmt.Errorf("failed to restore task: %w", err)
 	}
 
 	// Invalidate cache
 	if r.cache != nil {
-		r.cache.Delete(ctx, cache.TaskKey(taskID))
 		r.cache.DeletePattern(ctx, "tasks:*")
 	}
 
-	return r.getTaskWithRelations(ctx, task.ID)
+	return r.taskToGraphQL(ctx, restored)
+}
+
+// AssignTask is the resolver for the assignTask field.
+func (r *mutationResolver) AssignTask(ctx context.Context, taskID string, userID string) (*model.Task, error) {
+	return r.assignTask(ctx, taskID, &userID)
 }
 
 // UnassignTask is the resolver for the unassignTask field.
 func (r *mutationResolver) UnassignTask(ctx context.Context, taskID string) (*model.Task, error) {
-	_, err := auth.RequireAuth(ctx)
+	return r.assignTask(ctx, taskID, nil)
+}
+
+// StopRecurrence is the resolver for the stopRecurrence field.
+func (r *mutationResolver) StopRecurrence(ctx context.Context, taskID string) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, err
+	}
+
+	task, err := r.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
 	}
 
-	updates := map[string]interface{}{
-		"assigned_to_id": nil,
+	if !auth.CanModifyTask(claims, task) {
+		return nil, fmt.Errorf("unauthorized: you can only stop your own recurring tasks")
 	}
 
-	task, err := r.taskRepo.Update(ctx, taskID, updates)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unassign task: %w", err)
+	if task.SeriesID == nil {
+		return nil, fmt.Errorf("task is not recurring")
+	}
+
+	if err := r.taskRepo.StopRecurrence(ctx, *task.SeriesID); err != nil {
+		return nil, fmt.Errorf("failed to stop recurrence: %w", err)
 	}
 
 	// Invalidate cache
@@ -309,128 +478,1169 @@ func (r *mutationResolver) UnassignTask(ctx context.Context, taskID string) (*mo
 		r.cache.DeletePattern(ctx, "tasks:*")
 	}
 
-	return r.getTaskWithRelations(ctx, task.ID)
+	return r.getTaskWithRelations(ctx, taskID)
 }
 
-// UpdateProfile is the resolver for the updateProfile field.
-func (r *mutationResolver) UpdateProfile(ctx context.Context, name *string, avatar *string) (*model.User, error) {
-	claims, err := auth.RequireAuth(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
-	}
+// ArchiveTask is the resolver for the archiveTask field.
+func (r *mutationResolver) ArchiveTask(ctx context.Context, id string) (*model.Task, error) {
+	return r.setTaskArchived(ctx, id, true)
+}
 
-	updates := make(map[string]interface{})
-	if name != nil {
-		updates["name"] = *name
-	}
-	if avatar != nil {
-		updates["avatar"] = avatar
-	}
+// UnarchiveTask is the resolver for the unarchiveTask field.
+func (r *mutationResolver) UnarchiveTask(ctx context.Context, id string) (*model.Task, error) {
+	return r.setTaskArchived(ctx, id, false)
+}
 
-	user, err := r.userRepo.Update(ctx, claims.UserID, updates)
+// WatchTask is the resolver for the watchTask field.
+func (r *mutationResolver) WatchTask(ctx context.Context, taskID string) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update profile: %w", err)
+		return nil, err
 	}
 
-	// Invalidate cache
-	if r.cache != nil {
-		r.cache.Delete(ctx, cache.UserKey(claims.UserID))
+	if err := r.watcherRepo.Watch(ctx, taskID, claims.UserID); err != nil {
+		return nil, err
 	}
 
-	return toGraphQLUser(user), nil
+	return r.getTaskWithRelations(ctx, taskID)
 }
 
-// Me is the resolver for the me field.
-func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
-	claims, err := auth.RequireAuth(ctx)
+// UnwatchTask is the resolver for the unwatchTask field.
+func (r *mutationResolver) UnwatchTask(ctx context.Context, taskID string) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+		return nil, err
 	}
 
-	user, err := r.userRepo.GetByID(ctx, claims.UserID)
+	task, err := r.getTaskWithRelations(ctx, taskID)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, err
+	}
+	if err := r.watcherRepo.Unwatch(ctx, taskID, claims.UserID); err != nil {
+		return nil, err
 	}
 
-	return toGraphQLUser(user), nil
+	return task, nil
 }
 
-// User is the resolver for the user field.
-func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
-	user, err := r.userRepo.GetByID(ctx, id)
+// BulkUpdateTasks is the resolver for the bulkUpdateTasks field.
+func (r *mutationResolver) BulkUpdateTasks(ctx context.Context, ids []string, input model.UpdateTaskInput, atomic bool) (*model.BulkTaskPayload, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, err
 	}
 
-	return toGraphQLUser(user), nil
+	if len(ids) > repository.MaxBulkTasks {
+		return nil, fmt.Errorf("at most %d tasks can be changed at once", repository.MaxBulkTasks)
+	}
+
+	results, err := r.tasks.BulkUpdate(ctx, claims.UserID, ids, atomic, taskUpdateInput(input), func(task *models.Task) error {
+		if !auth.CanModifyTask(claims, task) {
+			return fmt.Errorf("unauthorized: you can only update your own tasks")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.bulkPayload(ctx, results)
 }
 
-// Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
-	users, err := r.userRepo.List(ctx)
+// BulkDeleteTasks is the resolver for the bulkDeleteTasks field.
+func (r *mutationResolver) BulkDeleteTasks(ctx context.Context, ids []string, atomic bool) (*model.BulkTaskPayload, error) {
+	claims, err := r.requireVerified(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list users: %w", err)
+		return nil, err
 	}
 
-	var result []*model.User
-	for _, user := range users {
-		result = append(result, toGraphQLUser(user))
+	if len(ids) > repository.MaxBulkTasks {
+		return nil, fmt.Errorf("at most %d tasks can be changed at once", repository.MaxBulkTasks)
 	}
 
-	return result, nil
-}
+	results, err := r.taskRepo.BulkDelete(ctx, ids, atomic, func(task *models.Task) error {
+		if !auth.CanModifyTask(claims, task) {
+			return fmt.Errorf("unauthorized: you can only delete your own tasks")
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete tasks: %w", err)
+	}
 
-// Task is the resolver for the task field.
-func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
-	return r.getTaskWithRelations(ctx, id)
+	return r.bulkPayload(ctx, results)
 }
 
-// Tasks is the resolver for the tasks field.
-func (r *queryResolver) Tasks(ctx context.Context, filter *model.TaskFilterInput) ([]*model.Task, error) {
-	filterMap := make(map[string]interface{})
-	
-	if filter != nil {
-		if filter.Status != nil {
-			filterMap["status"] = string(*filter.Status)
-		}
-		if filter.Priority != nil {
-			filterMap["priority"] = string(*filter.Priority)
-		}
-		if filter.AssignedToID != nil {
-			filterMap["assigned_to_id"] = *filter.AssignedToID
-		}
-		if filter.CreatedByID != nil {
-			filterMap["created_by_id"] = *filter.CreatedByID
-		}
+// BulkAssignTasks is the resolver for the bulkAssignTasks field.
+func (r *mutationResolver) BulkAssignTasks(ctx context.Context, ids []string, userID *string, atomic bool) (*model.BulkTaskPayload, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	tasks, err := r.taskRepo.List(ctx, filterMap)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	if len(ids) > repository.MaxBulkTasks {
+		return nil, fmt.Errorf("at most %d tasks can be changed at once", repository.MaxBulkTasks)
 	}
 
-	var result []*model.Task
-	for _, task := range tasks {
-		graphqlTask, err := r.taskToGraphQL(ctx, task)
-		if err != nil {
-			return nil, err
+	// Verify user exists
+	if userID != nil {
+		if _, err := r.userRepo.GetByID(ctx, *userID); err != nil {
+			return nil, fmt.Errorf("user not found")
 		}
-		result = append(result, graphqlTask)
 	}
 
-	return result, nil
+	results, err := r.taskRepo.BulkUpdate(ctx, ids, atomic, func(task *models.Task) (map[string]interface{}, error) {
+		if !auth.CanModifyTask(claims, task) {
+			return nil, fmt.Errorf("unauthorized: you can only assign your own tasks")
+		}
+		return map[string]interface{}{"assigned_to_id": userID}, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign tasks: %w", err)
+	}
+
+	return r.bulkPayload(ctx, results)
 }
 
-// MyTasks is the resolver for the myTasks field.
-func (r *queryResolver) MyTasks(ctx context.Context) ([]*model.Task, error) {
+// ExportTasks is the resolver for the exportTasks field.
+func (r *mutationResolver) ExportTasks(ctx context.Context, filter *model.TaskFilterInput, format model.ExportFormat) (*model.ExportPayload, error) {
 	claims, err := auth.RequireAuth(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("unauthorized")
 	}
 
-	tasks, err := r.taskRepo.GetByUserID(ctx, claims.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	if r.cache == nil {
+		return nil, fmt.Errorf("exports are unavailable")
+	}
+
+	exportFormat, err := export.ParseFormat(string(format))
+	if err != nil {
+		return nil, err
+	}
+
+	storedFilter, err := viewFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	token, expiresAt, err := export.Save(ctx, r.cache, &export.Request{
+		UserID: claims.UserID,
+		Format: exportFormat,
+		Filter: storedFilter,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare export: %w", err)
+	}
+
+	return &model.ExportPayload{
+		URL:       api.BasePath + "/exports/" + token,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// SaveView is the resolver for the saveView field.
+func (r *mutationResolver) SaveView(ctx context.Context, input model.SaveViewInput) (*models.SavedView, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, fmt.Errorf("view name is required")
+	}
+
+	filter, err := viewFilter(input.Filter)
+	if err != nil {
+		return nil, err
+	}
+	query, err := views.Encode(views.Query{Filter: filter, Sort: viewSort(input.Sort)})
+	if err != nil {
+		return nil, err
+	}
+
+	view, err := r.viewRepo.Create(ctx, &models.SavedView{
+		OwnerID: claims.UserID,
+		Name:    name,
+		Query:   query,
+		Shared:  input.Shared,
+		Pinned:  input.Pinned,
+	})
+	if errors.Is(err, repository.ErrViewNameTaken) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save view: %w", err)
+	}
+
+	return view, nil
+}
+
+// UpdateView is the resolver for the updateView field.
+func (r *mutationResolver) UpdateView(ctx context.Context, id string, input model.UpdateViewInput) (*models.SavedView, error) {
+	view, err := r.requireViewOwner(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, fmt.Errorf("view name is required")
+		}
+		updates["name"] = name
+	}
+	if input.Filter != nil || input.Sort != nil {
+		query, err := views.Decode(view.Query)
+		if err != nil {
+			return nil, err
+		}
+		if input.Filter != nil {
+			if query.Filter, err = viewFilter(input.Filter); err != nil {
+				return nil, err
+			}
+		}
+		if input.Sort != nil {
+			query.Sort = viewSort(input.Sort)
+		}
+		if updates["query"], err = views.Encode(query); err != nil {
+			return nil, err
+		}
+	}
+	if input.Shared != nil {
+		updates["shared"] = *input.Shared
+	}
+	if input.Pinned != nil {
+		updates["pinned"] = *input.Pinned
+	}
+
+	view, err = r.viewRepo.Update(ctx, id, updates)
+	if errors.Is(err, repository.ErrViewNameTaken) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update view: %w", err)
+	}
+
+	return view, nil
+}
+
+// DeleteView is the resolver for the deleteView field.
+func (r *mutationResolver) DeleteView(ctx context.Context, id string) (bool, error) {
+	if _, err := r.requireViewOwner(ctx, id); err != nil {
+		return false, err
+	}
+
+	if err := r.viewRepo.Delete(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete view: %w", err)
+	}
+
+	return true, nil
+}
+
+// StartTimer is the resolver for the startTimer field.
+func (r *mutationResolver) StartTimer(ctx context.Context, taskID string) (*models.TimeEntry, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.taskRepo.GetByID(ctx, taskID); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	entry, err := r.timeEntryRepo.Create(ctx, &models.TimeEntry{
+		TaskID:    taskID,
+		UserID:    claims.UserID,
+		StartedAt: time.Now(),
+	})
+	if errors.Is(err, repository.ErrTimerRunning) {
+		return nil, fmt.Errorf("a timer is already running, stop it first")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// StopTimer is the resolver for the stopTimer field.
+func (r *mutationResolver) StopTimer(ctx context.Context) (*models.TimeEntry, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := r.timeEntryRepo.Stop(ctx, claims.UserID, time.Now())
+	if errors.Is(err, repository.ErrNoRunningTimer) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// AddTimeEntry is the resolver for the addTimeEntry field.
+func (r *mutationResolver) AddTimeEntry(ctx context.Context, input model.AddTimeEntryInput) (*models.TimeEntry, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !input.EndedAt.After(input.StartedAt) {
+		return nil, fmt.Errorf("endedAt must be after startedAt")
+	}
+	if input.EndedAt.After(time.Now()) {
+		return nil, fmt.Errorf("time entries cannot end in the future")
+	}
+
+	if _, err := r.taskRepo.GetByID(ctx, input.TaskID); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	entry, err := r.timeEntryRepo.Create(ctx, &models.TimeEntry{
+		TaskID:    input.TaskID,
+		UserID:    claims.UserID,
+		StartedAt: input.StartedAt,
+		EndedAt:   &input.EndedAt,
+		Note:      input.Note,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to add time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// DeleteTimeEntry is the resolver for the deleteTimeEntry field.
+func (r *mutationResolver) DeleteTimeEntry(ctx context.Context, id string) (bool, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	entry, err := r.timeEntryRepo.GetByID(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("time entry not found")
+	}
+
+	if entry.UserID != claims.UserID {
+		return false, fmt.Errorf("unauthorized: you can only delete your own time entries")
+	}
+
+	if err := r.timeEntryRepo.Delete(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	return true, nil
+}
+
+// CreateSprint is the resolver for the createSprint field.
+func (r *mutationResolver) CreateSprint(ctx context.Context, input model.CreateSprintInput) (*models.Sprint, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !input.EndDate.After(input.StartDate) {
+		return nil, fmt.Errorf("endDate must be after startDate")
+	}
+
+	sprint, err := r.sprintRepo.Create(ctx, &models.Sprint{
+		Name:        input.Name,
+		Goal:        input.Goal,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		CreatedByID: claims.UserID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+// UpdateSprint is the resolver for the updateSprint field.
+func (r *mutationResolver) UpdateSprint(ctx context.Context, id string, input model.UpdateSprintInput) (*models.Sprint, error) {
+	sprint, err := r.requireSprintManager(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	startDate, endDate := sprint.StartDate, sprint.EndDate
+	updates := make(map[string]interface{})
+	if input.Name != nil {
+		updates["name"] = *input.Name
+	}
+	if input.Goal != nil {
+		updates["goal"] = input.Goal
+	}
+	if input.StartDate != nil {
+		updates["start_date"] = *input.StartDate
+		startDate = *input.StartDate
+	}
+	if input.EndDate != nil {
+		updates["end_date"] = *input.EndDate
+		endDate = *input.EndDate
+	}
+
+	if !endDate.After(startDate) {
+		return nil, fmt.Errorf("endDate must be after startDate")
+	}
+
+	sprint, err = r.sprintRepo.Update(ctx, id, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+// DeleteSprint is the resolver for the deleteSprint field.
+func (r *mutationResolver) DeleteSprint(ctx context.Context, id string) (bool, error) {
+	if _, err := r.requireSprintManager(ctx, id); err != nil {
+		return false, err
+	}
+
+	if err := r.sprintRepo.Delete(ctx, id); err != nil {
+		return false, fmt.Errorf("failed to delete sprint: %w", err)
+	}
+
+	return true, nil
+}
+
+// AddTaskToSprint is the resolver for the addTaskToSprint field.
+func (r *mutationResolver) AddTaskToSprint(ctx context.Context, sprintID string, taskID string) (*models.Sprint, error) {
+	sprint, err := r.requireSprintManager(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := r.taskRepo.GetByID(ctx, taskID); err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	err = r.sprintRepo.AddTask(ctx, sprintID, taskID)
+	if errors.Is(err, repository.ErrTaskAlreadyInSprint) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to add task to sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+// RemoveTaskFromSprint is the resolver for the removeTaskFromSprint field.
+func (r *mutationResolver) RemoveTaskFromSprint(ctx context.Context, sprintID string, taskID string) (*models.Sprint, error) {
+	sprint, err := r.requireSprintManager(ctx, sprintID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.sprintRepo.RemoveTask(ctx, sprintID, taskID)
+	if errors.Is(err, repository.ErrTaskNotInSprint) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to remove task from sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+// UploadAttachment is the resolver for the uploadAttachment field.
+func (r *mutationResolver) UploadAttachment(ctx context.Context, taskID string, file graphql.Upload) (*models.Attachment, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := r.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	if !auth.CanAttachToTask(claims, task) {
+		return nil, fmt.Errorf("unauthorized: only the task's creator or assignee can attach files")
+	}
+
+	attachment, err := r.attachments.Upload(ctx, task.ID, claims.UserID, file.Filename, file.File, file.Size)
+	switch {
+	case errors.Is(err, attachments.ErrTooLarge):
+		return nil, fmt.Errorf("file is larger than %d bytes", r.attachments.Limits().MaxBytes)
+	case errors.Is(err, attachments.ErrTypeNotAllowed), errors.Is(err, attachments.ErrMissingFilename):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("failed to upload attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+// DeleteAttachment is the resolver for the deleteAttachment field.
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, id string) (bool, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	attachment, err := r.attachments.Get(ctx, id)
+	if err != nil {
+		return false, fmt.Errorf("attachment not found")
+	}
+
+	task, err := r.taskRepo.GetByID(ctx, attachment.TaskID)
+	if err != nil {
+		return false, fmt.Errorf("task not found")
+	}
+
+	if !auth.CanDeleteAttachment(claims, task, attachment) {
+		return false, fmt.Errorf("unauthorized: you can only delete your own attachments")
+	}
+
+	if err := r.attachments.Delete(ctx, attachment); err != nil {
+		return false, fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	return true, nil
+}
+
+// RotateCalendarToken is the resolver for the rotateCalendarToken field.
+func (r *mutationResolver) RotateCalendarToken(ctx context.Context) (*model.CalendarFeed, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.userRepo.SetCalendarTokenHash(ctx, claims.UserID, auth.HashOpaqueToken(token)); err != nil {
+		return nil, fmt.Errorf("failed to rotate calendar token: %w", err)
+	}
+
+	return &model.CalendarFeed{URL: calendar.FeedPath(token)}, nil
+}
+
+// UpdateProfile is the resolver for the updateProfile field.
+func (r *mutationResolver) UpdateProfile(ctx context.Context, name *string, avatar *string) (*model.User, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	current, err := r.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	updates := make(map[string]interface{})
+	if name != nil {
+		updates["name"] = *name
+	}
+	if avatar != nil {
+		// Only thumbnails served by this server are allowed, so profiles
+		// cannot point other users' browsers at arbitrary URLs
+		switch {
+		case *avatar == "":
+			updates["avatar"] = (*string)(nil)
+		case avatars.OwnedBy(*avatar, claims.UserID):
+			updates["avatar"] = avatar
+		default:
+			return nil, fmt.Errorf("avatar must be uploaded with uploadAvatar")
+		}
+	}
+
+	user, err := r.userRepo.Update(ctx, claims.UserID, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if current.Avatar != nil && (user.Avatar == nil || *user.Avatar != *current.Avatar) {
+		r.avatars.Delete(ctx, *current.Avatar)
+	}
+
+	// Invalidate cache
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.UserKey(claims.UserID))
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// UploadAvatar is the resolver for the uploadAvatar field.
+func (r *mutationResolver) UploadAvatar(ctx context.Context, file graphql.Upload) (*model.User, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	current, err := r.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	if file.Size > r.avatars.MaxBytes() {
+		return nil, fmt.Errorf("image is larger than %d bytes", r.avatars.MaxBytes())
+	}
+
+	avatar, err := r.avatars.Upload(ctx, claims.UserID, file.File)
+	switch {
+	case errors.Is(err, avatars.ErrTooLarge), errors.Is(err, avatars.ErrNotImage):
+		return nil, err
+	case err != nil:
+		return nil, fmt.Errorf("failed to upload avatar: %w", err)
+	}
+
+	user, err := r.userRepo.Update(ctx, claims.UserID, map[string]interface{}{"avatar": avatar})
+	if err != nil {
+		r.avatars.Delete(ctx, avatar)
+		return nil, fmt.Errorf("failed to update profile: %w", err)
+	}
+
+	if current.Avatar != nil {
+		r.avatars.Delete(ctx, *current.Avatar)
+	}
+
+	// Invalidate cache
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.UserKey(claims.UserID))
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// Me is the resolver for the me field.
+func (r *queryResolver) Me(ctx context.Context) (*model.User, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	user, err := r.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// MySessions is the resolver for the mySessions field.
+func (r *queryResolver) MySessions(ctx context.Context) ([]*model.Session, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	// Sessions idle for longer than a refresh token lives cannot be resumed
+	sessions, err := r.sessionRepo.ListActive(ctx, claims.UserID, time.Now().Add(-r.jwtManager.RefreshDuration()))
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*model.Session, len(sessions))
+	for i, session := range sessions {
+		result[i] = &model.Session{
+			ID:         session.ID,
+			UserAgent:  session.UserAgent,
+			IPAddress:  session.IPAddress,
+			CreatedAt:  session.CreatedAt,
+			LastSeenAt: session.LastSeenAt,
+			Current:    session.ID == claims.SessionID,
+		}
+	}
+
+	return result, nil
+}
+
+// User is the resolver for the user field.
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// Users is the resolver for the users field.
+func (r *queryResolver) Users(ctx context.Context) ([]*model.User, error) {
+	users, err := r.userRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var result []*model.User
+	for _, user := range users {
+		result = append(result, toGraphQLUser(user))
+	}
+
+	return result, nil
+}
+
+// Task is the resolver for the task field.
+func (r *queryResolver) Task(ctx context.Context, id string) (*model.Task, error) {
+	return r.getTaskWithRelations(ctx, id)
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *queryResolver) Tasks(ctx context.Context, filter *model.TaskFilterInput) ([]*model.Task, error) {
+	taskFilter, err := viewFilter(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := r.taskRepo.List(ctx, taskFilter.Map())
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	var result []*model.Task
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// MyTasks is the resolver for the myTasks field.
+func (r *queryResolver) MyTasks(ctx context.Context) ([]*model.Task, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	tasks, err := r.taskRepo.GetByUserID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	var result []*model.Task
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// AssignedTasks is the resolver for the assignedTasks field.
+func (r *queryResolver) AssignedTasks(ctx context.Context) ([]*model.Task, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	tasks, err := r.taskRepo.GetAssignedToUser(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get assigned tasks: %w", err)
+	}
+
+	var result []*model.Task
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// Trash is the resolver for the trash field.
+func (r *queryResolver) Trash(ctx context.Context) ([]*model.Task, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	tasks, err := r.taskRepo.ListDeleted(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	result := []*model.Task{}
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// MentionedTasks is the resolver for the mentionedTasks field.
+func (r *queryResolver) MentionedTasks(ctx context.Context) ([]*model.Task, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	tasks, err := r.taskRepo.List(ctx, map[string]interface{}{"mentioned": claims.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentioned tasks: %w", err)
+	}
+
+	result := []*model.Task{}
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// WatchedTasks is the resolver for the watchedTasks field.
+func (r *queryResolver) WatchedTasks(ctx context.Context) ([]*model.Task, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	tasks, err := r.taskRepo.List(ctx, map[string]interface{}{"watched_by": claims.UserID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watched tasks: %w", err)
+	}
+
+	result := []*model.Task{}
+	for _, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, graphqlTask)
+	}
+
+	return result, nil
+}
+
+// RenderMarkdown is the resolver for the renderMarkdown field.
+func (r *queryResolver) RenderMarkdown(ctx context.Context, text string) (string, error) {
+	if _, err := auth.RequireAuth(ctx); err != nil {
+		return "", fmt.Errorf("unauthorized")
+	}
+
+	if len(text) > markdown.MaxLength {
+		return "", fmt.Errorf("text must be at most %d bytes", markdown.MaxLength)
+	}
+
+	return r.renderMarkdown(ctx, text), nil
+}
+
+// Views is the resolver for the views field.
+func (r *queryResolver) Views(ctx context.Context) ([]*models.SavedView, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	savedViews, err := r.viewRepo.ListVisible(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get views: %w", err)
+	}
+
+	return savedViews, nil
+}
+
+// TasksForView is the resolver for the tasksForView field.
+func (r *queryResolver) TasksForView(ctx context.Context, viewID string, first *int, after *string) (*model.TaskConnection, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	view, err := r.viewRepo.GetByID(ctx, viewID)
+	if err != nil || !auth.CanSeeView(claims, view) {
+		return nil, fmt.Errorf("view not found")
+	}
+
+	query, err := views.Decode(view.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	limit := defaultViewPageSize
+	if first != nil {
+		limit = *first
+	}
+	if limit < 0 || limit > maxViewPageSize {
+		return nil, fmt.Errorf("first must be between 0 and %d", maxViewPageSize)
+	}
+
+	offset := 0
+	if after != nil {
+		position, err := decodeCursor(*after)
+		if err != nil {
+			return nil, err
+		}
+		offset = position + 1
+	}
+
+	filter := query.TaskFilter()
+	total, err := r.taskRepo.Count(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	// Offsets keep cursors valid for any sort order; tasks changing between
+	// requests may shift a page by a few rows
+	var tasks []*models.Task
+	if limit > 0 {
+		filter["limit"] = limit
+		filter["offset"] = offset
+		tasks, err = r.taskRepo.List(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tasks: %w", err)
+		}
+	}
+
+	connection := &model.TaskConnection{
+		Edges:      []*model.TaskEdge{},
+		PageInfo:   &model.PageInfo{HasNextPage: offset+len(tasks) < total},
+		TotalCount: total,
+	}
+	for i, task := range tasks {
+		graphqlTask, err := r.taskToGraphQL(ctx, task)
+		if err != nil {
+			return nil, err
+		}
+		cursor := encodeCursor(offset + i)
+		connection.Edges = append(connection.Edges, &model.TaskEdge{Cursor: cursor, Node: graphqlTask})
+		connection.PageInfo.EndCursor = &cursor
+	}
+
+	return connection, nil
+}
+
+// RunningTimer is the resolver for the runningTimer field.
+func (r *queryResolver) RunningTimer(ctx context.Context) (*models.TimeEntry, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	entry, err := r.timeEntryRepo.GetRunning(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// TimeReport is the resolver for the timeReport field.
+func (r *queryResolver) TimeReport(ctx context.Context, userID *string, from time.Time, to time.Time, groupBy model.TimeReportGrouping) ([]*models.TimeReportRow, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from")
+	}
+
+	report, err := r.timeEntryRepo.Report(ctx, claims.UserID, userID, from, to, string(groupBy))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build time report: %w", err)
+	}
+
+	return report, nil
+}
+
+// Sprint is the resolver for the sprint field.
+func (r *queryResolver) Sprint(ctx context.Context, id string) (*models.Sprint, error) {
+	sprint, err := r.sprintRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found")
+	}
+
+	return sprint, nil
+}
+
+// Sprints is the resolver for the sprints field.
+func (r *queryResolver) Sprints(ctx context.Context) ([]*models.Sprint, error) {
+	sprints, err := r.sprintRepo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sprints: %w", err)
+	}
+
+	return sprints, nil
+}
+
+// Burndown is the resolver for the burndown field.
+func (r *queryResolver) Burndown(ctx context.Context, sprintID string) ([]*models.BurndownPoint, error) {
+	if _, err := r.sprintRepo.GetByID(ctx, sprintID); err != nil {
+		return nil, fmt.Errorf("sprint not found")
+	}
+
+	points, err := r.sprintRepo.Burndown(ctx, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute burndown: %w", err)
+	}
+
+	return points, nil
+}
+
+// TaskStats is the resolver for the taskStats field.
+func (r *queryResolver) TaskStats(ctx context.Context) (*models.TaskStats, error) {
+	if _, err := auth.RequireAuth(ctx); err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	key := cache.StatsKey("summary")
+	if r.cache != nil {
+		var cached models.TaskStats
+		if err := r.cache.Get(ctx, key, &cached); err == nil {
+			return &cached, nil
+		}
+	}
+
+	stats, err := r.analyticsRepo.Stats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute task stats: %w", err)
+	}
+
+	if r.cache != nil {
+		r.cache.Set(ctx, key, stats, cache.StatsTTL)
+	}
+
+	return stats, nil
+}
+
+// TaskThroughput is the resolver for the taskThroughput field.
+func (r *queryResolver) TaskThroughput(ctx context.Context, from time.Time, to time.Time) ([]*models.DailyThroughput, error) {
+	if _, err := auth.RequireAuth(ctx); err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from")
+	}
+
+	key := cache.StatsKey("throughput", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if r.cache != nil {
+		var cached []*models.DailyThroughput
+		if err := r.cache.Get(ctx, key, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	days, err := r.analyticsRepo.Throughput(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute throughput: %w", err)
+	}
+
+	if r.cache != nil {
+		r.cache.Set(ctx, key, days, cache.StatsTTL)
+	}
+
+	return days, nil
+}
+
+// CycleTimes is the resolver for the cycleTimes field.
+func (r *queryResolver) CycleTimes(ctx context.Context, from time.Time, to time.Time) ([]*models.UserCycleTime, error) {
+	if _, err := auth.RequireAuth(ctx); err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	if !to.After(from) {
+		return nil, fmt.Errorf("to must be after from")
+	}
+
+	key := cache.StatsKey("cycle", from.UTC().Format(time.RFC3339), to.UTC().Format(time.RFC3339))
+	if r.cache != nil {
+		var cached []*models.UserCycleTime
+		if err := r.cache.Get(ctx, key, &cached); err == nil {
+			return cached, nil
+		}
+	}
+
+	cycleTimes, err := r.analyticsRepo.CycleTimes(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute cycle times: %w", err)
+	}
+
+	if r.cache != nil {
+		r.cache.Set(ctx, key, cycleTimes, cache.StatsTTL)
+	}
+
+	return cycleTimes, nil
+}
+
+// Owner is the resolver for the owner field.
+func (r *savedViewResolver) Owner(ctx context.Context, obj *models.SavedView) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.OwnerID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// Filter is the resolver for the filter field.
+func (r *savedViewResolver) Filter(ctx context.Context, obj *models.SavedView) (*model.TaskFilter, error) {
+	query, err := views.Decode(obj.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	return toGraphQLTaskFilter(query.Filter), nil
+}
+
+// Sort is the resolver for the sort field.
+func (r *savedViewResolver) Sort(ctx context.Context, obj *models.SavedView) (*model.TaskSort, error) {
+	query, err := views.Decode(obj.Query)
+	if err != nil {
+		return nil, err
+	}
+	if query.Sort == nil {
+		return nil, nil
+	}
+
+	direction := model.SortDirectionAsc
+	if query.Sort.Descending {
+		direction = model.SortDirectionDesc
+	}
+
+	return &model.TaskSort{
+		Field:     model.TaskSortField(strings.ToUpper(query.Sort.Field)),
+		Direction: direction,
+	}, nil
+}
+
+// CreatedBy is the resolver for the createdBy field.
+func (r *sprintResolver) CreatedBy(ctx context.Context, obj *models.Sprint) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.CreatedByID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get creator: %w", err)
+	}
+
+	return toGraphQLUser(user), nil
+}
+
+// Tasks is the resolver for the tasks field.
+func (r *sprintResolver) Tasks(ctx context.Context, obj *models.Sprint) ([]*model.Task, error) {
+	tasks, err := r.sprintRepo.ListTasks(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint tasks: %w", err)
 	}
 
 	var result []*model.Task
@@ -442,53 +1652,401 @@ func (r *queryResolver) MyTasks(ctx context.Context) ([]*model.Task, error) {
 		result = append(result, graphqlTask)
 	}
 
-	return result, nil
+	return result, nil
+}
+
+// ScopePoints is the resolver for the scopePoints field.
+func (r *sprintResolver) ScopePoints(ctx context.Context, obj *models.Sprint) (int, error) {
+	totals, err := r.sprintTotals(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return totals.ScopePoints, nil
+}
+
+// CompletedPoints is the resolver for the completedPoints field.
+func (r *sprintResolver) CompletedPoints(ctx context.Context, obj *models.Sprint) (int, error) {
+	totals, err := r.sprintTotals(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return totals.CompletedPoints, nil
+}
+
+// ScopeHours is the resolver for the scopeHours field.
+func (r *sprintResolver) ScopeHours(ctx context.Context, obj *models.Sprint) (float64, error) {
+	totals, err := r.sprintTotals(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return totals.ScopeHours, nil
+}
+
+// CompletedHours is the resolver for the completedHours field.
+func (r *sprintResolver) CompletedHours(ctx context.Context, obj *models.Sprint) (float64, error) {
+	totals, err := r.sprintTotals(ctx, obj)
+	if err != nil {
+		return 0, err
+	}
+
+	return totals.CompletedHours, nil
+}
+
+// ScopeChanges is the resolver for the scopeChanges field.
+func (r *sprintResolver) ScopeChanges(ctx context.Context, obj *models.Sprint) ([]*models.SprintScopeChange, error) {
+	changes, err := r.sprintRepo.ScopeChanges(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scope changes: %w", err)
+	}
+
+	return changes, nil
+}
+
+// Task is the resolver for the task field.
+func (r *sprintScopeChangeResolver) Task(ctx context.Context, obj *models.SprintScopeChange) (*model.Task, error) {
+	return r.getTaskWithRelations(ctx, obj.TaskID)
+}
+
+// Change is the resolver for the change field.
+func (r *sprintScopeChangeResolver) Change(ctx context.Context, obj *models.SprintScopeChange) (model.ScopeChange, error) {
+	return model.ScopeChange(obj.Change), nil
+}
+
+// TaskCreated is the resolver for the taskCreated field.
+func (r *subscriptionResolver) TaskCreated(ctx context.Context) (<-chan *model.Task, error) {
+	panic(fmt.Errorf("not implemented: TaskCreated - taskCreated"))
+}
+
+// TaskUpdated is the resolver for the taskUpdated field.
+func (r *subscriptionResolver) TaskUpdated(ctx context.Context, taskID *string) (<-chan *model.Task, error) {
+	panic(fmt.Errorf("not implemented: TaskUpdated - taskUpdated"))
+}
+
+// TaskDeleted is the resolver for the taskDeleted field.
+func (r *subscriptionResolver) TaskDeleted(ctx context.Context) (<-chan string, error) {
+	panic(fmt.Errorf("not implemented: TaskDeleted - taskDeleted"))
+}
+
+// DescriptionHTML is the resolver for the descriptionHtml field.
+func (r *taskResolver) DescriptionHTML(ctx context.Context, obj *model.Task) (*string, error) {
+	if obj.Description == nil {
+		return nil, nil
+	}
+
+	html := r.renderMarkdown(ctx, *obj.Description)
+	return &html, nil
+}
+
+// TimeEntries is the resolver for the timeEntries field.
+func (r *taskResolver) TimeEntries(ctx context.Context, obj *model.Task) ([]*models.TimeEntry, error) {
+	// Entries are shown to the users who tracked them and the task's creator
+	claims, ok := auth.GetUserFromContext(ctx)
+	if !ok {
+		return []*models.TimeEntry{}, nil
+	}
+
+	entries, err := r.timeEntryRepo.ListByTask(ctx, claims.UserID, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entries: %w", err)
+	}
+
+	return entries, nil
+}
+
+// TotalTimeSpent is the resolver for the totalTimeSpent field.
+func (r *taskResolver) TotalTimeSpent(ctx context.Context, obj *model.Task) (int, error) {
+	claims, ok := auth.GetUserFromContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+
+	total, err := r.timeEntryRepo.TotalForTask(ctx, claims.UserID, obj.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to total time entries: %w", err)
+	}
+
+	return int(total), nil
+}
+
+// Attachments is the resolver for the attachments field.
+func (r *taskResolver) Attachments(ctx context.Context, obj *model.Task) ([]*models.Attachment, error) {
+	list, err := r.attachments.List(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	return list, nil
+}
+
+// Mentions is the resolver for the mentions field.
+func (r *taskResolver) Mentions(ctx context.Context, obj *model.Task) ([]*models.Mention, error) {
+	list, err := r.mentionRepo.ListForTask(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get mentions: %w", err)
+	}
+
+	return list, nil
+}
+
+// Watchers is the resolver for the watchers field.
+func (r *taskResolver) Watchers(ctx context.Context, obj *model.Task) ([]*model.User, error) {
+	users, err := r.watcherRepo.ListWatchers(ctx, obj.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get watchers: %w", err)
+	}
+
+	result := []*model.User{}
+	for _, user := range users {
+		result = append(result, toGraphQLUser(user))
+	}
+
+	return result, nil
+}
+
+// Task is the resolver for the task field.
+func (r *timeEntryResolver) Task(ctx context.Context, obj *models.TimeEntry) (*model.Task, error) {
+	return r.getTaskWithRelations(ctx, obj.TaskID)
+}
+
+// User is the resolver for the user field.
+func (r *timeEntryResolver) User(ctx context.Context, obj *models.TimeEntry) (*model.User, error) {
+	user, err := r.userRepo.GetByID(ctx, obj.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
 }
 
-// AssignedTasks is the resolver for the assignedTasks field.
-func (r *queryResolver) AssignedTasks(ctx context.Context) ([]*model.Task, error) {
-	claims, err := auth.RequireAuth(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("unauthorized")
+// AvatarURL is the resolver for the avatarUrl field.
+func (r *userResolver) AvatarURL(ctx context.Context, obj *model.User, size int) (*string, error) {
+	if obj.Avatar == nil {
+		return nil, nil
 	}
 
-	tasks, err := r.taskRepo.GetAssignedToUser(ctx, claims.UserID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get assigned tasks: %w", err)
+	url, ok := avatars.URL(*obj.Avatar, size)
+	if !ok {
+		return nil, nil
 	}
 
-	var result []*model.Task
-	for _, task := range tasks {
-		graphqlTask, err := r.taskToGraphQL(ctx, task)
-		if err != nil {
-			return nil, err
-		}
-		result = append(result, graphqlTask)
+	return &url, nil
+}
+
+// User is the resolver for the user field.
+func (r *userCycleTimeResolver) User(ctx context.Context, obj *models.UserCycleTime) (*model.User, error) {
+	if obj.UserID == nil {
+		return nil, nil
 	}
 
-	return result, nil
+	user, err := r.userRepo.GetByID(ctx, *obj.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return toGraphQLUser(user), nil
 }
 
+// Attachment returns AttachmentResolver implementation.
+func (r *Resolver) Attachment() AttachmentResolver { return &attachmentResolver{r} }
+
+// Mention returns MentionResolver implementation.
+func (r *Resolver) Mention() MentionResolver { return &mentionResolver{r} }
+
 // Mutation returns MutationResolver implementation.
 func (r *Resolver) Mutation() MutationResolver { return &mutationResolver{r} }
 
 // Query returns QueryResolver implementation.
 func (r *Resolver) Query() QueryResolver { return &queryResolver{r} }
 
+// SavedView returns SavedViewResolver implementation.
+func (r *Resolver) SavedView() SavedViewResolver { return &savedViewResolver{r} }
+
+// Sprint returns SprintResolver implementation.
+func (r *Resolver) Sprint() SprintResolver { return &sprintResolver{r} }
+
+// SprintScopeChange returns SprintScopeChangeResolver implementation.
+func (r *Resolver) SprintScopeChange() SprintScopeChangeResolver {
+	return &sprintScopeChangeResolver{r}
+}
+
+// Subscription returns SubscriptionResolver implementation.
+func (r *Resolver) Subscription() SubscriptionResolver { return &subscriptionResolver{r} }
+
+// Task returns TaskResolver implementation.
+func (r *Resolver) Task() TaskResolver { return &taskResolver{r} }
+
+// TimeEntry returns TimeEntryResolver implementation.
+func (r *Resolver) TimeEntry() TimeEntryResolver { return &timeEntryResolver{r} }
+
+// User returns UserResolver implementation.
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
+
+// UserCycleTime returns UserCycleTimeResolver implementation.
+func (r *Resolver) UserCycleTime() UserCycleTimeResolver { return &userCycleTimeResolver{r} }
+
+type attachmentResolver struct{ *Resolver }
+type mentionResolver struct{ *Resolver }
 type mutationResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type savedViewResolver struct{ *Resolver }
+type sprintResolver struct{ *Resolver }
+type sprintScopeChangeResolver struct{ *Resolver }
+type subscriptionResolver struct{ *Resolver }
+type taskResolver struct{ *Resolver }
+type timeEntryResolver struct{ *Resolver }
+type userResolver struct{ *Resolver }
+type userCycleTimeResolver struct{ *Resolver }
 
 // Helper functions
 
 func toGraphQLUser(user *models.User) *model.User {
 	return &model.User{
-		ID:        user.ID,
-		Email:     user.Email,
-		Name:      user.Name,
-		Avatar:    user.Avatar,
-		CreatedAt: user.CreatedAt,
-		UpdatedAt: user.UpdatedAt,
+		ID:            user.ID,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		Name:          user.Name,
+		Avatar:        user.Avatar,
+		CreatedAt:     user.CreatedAt,
+		UpdatedAt:     user.UpdatedAt,
+	}
+}
+
+// requireVerified is auth.RequireAuth for changes to tasks and what hangs off
+// them: time entries, attachments, watchers and sprints. It also rejects users
+// who have not verified their email address when verification is required.
+func (r *Resolver) requireVerified(ctx context.Context) (*auth.Claims, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if !r.verifications.Required() {
+		return claims, nil
+	}
+
+	user, err := r.userRepo.GetByID(ctx, claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("user not found")
+	}
+	if user.EmailVerifiedAt == nil {
+		return nil, verification.ErrNotVerified
+	}
+
+	return claims, nil
+}
+
+// startSession records a new login of user from the client making the
+// request and issues its first tokens
+func (r *Resolver) startSession(ctx context.Context, user *models.User) (*model.AuthPayload, error) {
+	client := auth.ClientFromContext(ctx)
+
+	var payload *model.AuthPayload
+	err := r.tx.WithTx(ctx, func(ctx context.Context) error {
+		session, err := r.sessionRepo.Create(ctx, &models.Session{
+			UserID:    user.ID,
+			UserAgent: client.UserAgent,
+			IPAddress: client.IP,
+		})
+		if err != nil {
+			return err
+		}
+
+		payload, err = r.issueTokens(ctx, user, session.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// issueTokens signs an access and a refresh token for user's session
+// sessionID and stores the refresh token in the session's family
+func (r *Resolver) issueTokens(ctx context.Context, user *models.User, sessionID string) (*model.AuthPayload, error) {
+	accessToken, err := r.jwtManager.GenerateAccessToken(user.ID, user.Email, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate access token: %w", err)
+	}
+
+	refreshToken, err := r.jwtManager.GenerateRefreshToken(user.ID, user.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+	}
+
+	_, err = r.tokenRepo.Create(ctx, &models.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  sessionID,
+		TokenHash: auth.HashOpaqueToken(refreshToken),
+		ExpiresAt: time.Now().Add(r.jwtManager.RefreshDuration()),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.AuthPayload{
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+		User:         toGraphQLUser(user),
+	}, nil
+}
+
+// endSession revokes sessionID and its refresh tokens, and forgets whether it
+// was active so the auth middleware rejects its access tokens from now on
+func (r *Resolver) endSession(ctx context.Context, sessionID string) error {
+	if err := r.sessionRepo.Revoke(ctx, sessionID); err != nil {
+		return err
+	}
+	if err := r.tokenRepo.RevokeFamily(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.SessionKey(sessionID))
+	}
+	return nil
+}
+
+// endAllSessions ends every session of userID as endSession does
+func (r *Resolver) endAllSessions(ctx context.Context, userID string) error {
+	ids, err := r.revokeAllSessions(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	r.forgetSessions(ctx, ids)
+	return nil
+}
+
+// revokeAllSessions revokes every session of userID and their refresh tokens,
+// returning the IDs of the sessions revoked. Inside a transaction, call
+// forgetSessions with them once it has committed.
+func (r *Resolver) revokeAllSessions(ctx context.Context, userID string) ([]string, error) {
+	ids, err := r.sessionRepo.RevokeAllForUser(ctx, userID)
+	if err != nil {
+		return nil, err
 	}
+	if err := r.tokenRepo.RevokeAllForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// forgetSessions drops whether the sessions with ids were active from the
+// cache, so the auth middleware looks up that they were revoked
+func (r *Resolver) forgetSessions(ctx context.Context, ids []string) {
+	if r.cache == nil || len(ids) == 0 {
+		return
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = cache.SessionKey(id)
+	}
+	r.cache.Delete(ctx, keys...)
 }
 
 func (r *Resolver) taskToGraphQL(ctx context.Context, task *models.Task) (*model.Task, error) {
@@ -499,15 +2057,21 @@ func (r *Resolver) taskToGraphQL(ctx context.Context, task *models.Task) (*model
 	}
 
 	graphqlTask := &model.Task{
-		ID:          task.ID,
-		Title:       task.Title,
-		Description: task.Description,
-		Status:      model.TaskStatus(task.Status),
-		Priority:    model.Priority(task.Priority),
-		CreatedBy:   toGraphQLUser(creator),
-		DueDate:     task.DueDate,
-		CreatedAt:   task.CreatedAt,
-		UpdatedAt:   task.UpdatedAt,
+		ID:             task.ID,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         model.TaskStatus(task.Status),
+		Priority:       model.Priority(task.Priority),
+		CreatedBy:      toGraphQLUser(creator),
+		DueDate:        task.DueDate,
+		CreatedAt:      task.CreatedAt,
+		UpdatedAt:      task.UpdatedAt,
+		Recurrence:     task.RecurrenceRule,
+		StoryPoints:    task.StoryPoints,
+		EstimatedHours: task.EstimatedHours,
+		CompletedAt:    task.CompletedAt,
+		ArchivedAt:     task.ArchivedAt,
+		DeletedAt:      task.DeletedAt,
 	}
 
 	// Get assignee if exists
@@ -528,4 +2092,342 @@ func (r *Resolver) getTaskWithRelations(ctx context.Context, taskID string) (*mo
 	}
 
 	return r.taskToGraphQL(ctx, task)
-}
\ No newline at end of file
+}
+
+// requireSprintManager loads a sprint and checks the caller may manage it
+func (r *Resolver) requireSprintManager(ctx context.Context, sprintID string) (*models.Sprint, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	sprint, err := r.sprintRepo.GetByID(ctx, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("sprint not found")
+	}
+
+	if !auth.CanManageSprint(claims, sprint) {
+		return nil, fmt.Errorf("unauthorized: you can only manage your own sprints")
+	}
+
+	return sprint, nil
+}
+
+// sprintTotals returns the totals of sprint, querying them once per operation
+// however many of its fields need them
+func (r *Resolver) sprintTotals(ctx context.Context, sprint *models.Sprint) (*models.SprintTotals, error) {
+	totals, err := memoize(ctx, sprint, "totals", func() (interface{}, error) {
+		return r.sprintRepo.Totals(ctx, sprint.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return totals.(*models.SprintTotals), nil
+}
+
+// requireViewOwner loads a saved view and checks the caller may change it.
+// Views the caller cannot see are reported as missing.
+func (r *Resolver) requireViewOwner(ctx context.Context, viewID string) (*models.SavedView, error) {
+	claims, err := auth.RequireAuth(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unauthorized")
+	}
+
+	view, err := r.viewRepo.GetByID(ctx, viewID)
+	if err != nil || !auth.CanSeeView(claims, view) {
+		return nil, fmt.Errorf("view not found")
+	}
+
+	if !auth.CanModifyView(claims, view) {
+		return nil, fmt.Errorf("unauthorized: you can only change your own views")
+	}
+
+	return view, nil
+}
+
+// renderMarkdown renders text with markdown.Render, caching the HTML by a
+// hash of text
+func (r *Resolver) renderMarkdown(ctx context.Context, text string) string {
+	key := cache.MarkdownKey(markdown.Hash(text))
+	if r.cache != nil {
+		var cached string
+		if err := r.cache.Get(ctx, key, &cached); err == nil {
+			return cached
+		}
+	}
+
+	html := markdown.Render(text)
+
+	if r.cache != nil {
+		r.cache.Set(ctx, key, html, cache.MarkdownTTL)
+	}
+
+	return html
+}
+
+// bulkPayload finishes a bulk operation: it invalidates the cache once for
+// the whole batch and converts the per-task results
+func (r *Resolver) bulkPayload(ctx context.Context, results []*repository.BulkResult) (*model.BulkTaskPayload, error) {
+	payload := &model.BulkTaskPayload{Results: []*model.BulkTaskResult{}}
+	keys := []string{}
+
+	for _, result := range results {
+		item := &model.BulkTaskResult{ID: result.ID}
+		payload.Results = append(payload.Results, item)
+
+		if result.Err != nil {
+			message := result.Err.Error()
+			item.Error = &message
+			payload.Failed++
+			continue
+		}
+		payload.Succeeded++
+		keys = append(keys, cache.TaskKey(result.ID))
+
+		if result.Task == nil {
+			continue
+		}
+
+		task, err := r.taskToGraphQL(ctx, result.Task)
+		if err != nil {
+			return nil, err
+		}
+		item.Task = task
+	}
+
+	// Invalidate cache
+	if r.cache != nil && len(keys) > 0 {
+		r.cache.Delete(ctx, keys...)
+		r.cache.DeletePattern(ctx, "tasks:*")
+	}
+
+	return payload, nil
+}
+
+// taskUpdateInput converts input for the task service
+func taskUpdateInput(input model.UpdateTaskInput) tasks.UpdateInput {
+	return tasks.UpdateInput{
+		Title:          input.Title,
+		Description:    input.Description,
+		Status:         (*string)(input.Status),
+		Priority:       (*string)(input.Priority),
+		AssignedToID:   input.AssignedToID,
+		DueDate:        input.DueDate,
+		Recurrence:     input.Recurrence,
+		StoryPoints:    input.StoryPoints,
+		EstimatedHours: input.EstimatedHours,
+	}
+}
+
+// assignTask makes assigneeID, or nobody when it is nil, responsible for a task
+func (r *Resolver) assignTask(ctx context.Context, taskID string, assigneeID *string) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := r.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	task, err := r.tasks.Assign(ctx, claims.UserID, existing, assigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Invalidate cache
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.TaskKey(taskID))
+		r.cache.DeletePattern(ctx, "tasks:*")
+	}
+
+	return r.getTaskWithRelations(ctx, task.ID)
+}
+
+// setTaskArchived archives or unarchives a task the caller may modify
+func (r *Resolver) setTaskArchived(ctx context.Context, id string, archived bool) (*model.Task, error) {
+	claims, err := r.requireVerified(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := r.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	if !auth.CanModifyTask(claims, task) {
+		return nil, fmt.Errorf("unauthorized: you can only archive your own tasks")
+	}
+
+	if archived {
+		task, err = r.taskRepo.Archive(ctx, id)
+	} else {
+		task, err = r.taskRepo.Unarchive(ctx, id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	// Invalidate cache
+	if r.cache != nil {
+		r.cache.Delete(ctx, cache.TaskKey(id))
+		r.cache.DeletePattern(ctx, "tasks:*")
+	}
+
+	return r.taskToGraphQL(ctx, task)
+}
+
+// viewFilter converts a GraphQL task filter to its stored form
+func viewFilter(filter *model.TaskFilterInput) (views.Filter, error) {
+	f := convertTaskFilter(filter)
+	if err := f.Validate(); err != nil {
+		return views.Filter{}, err
+	}
+	return f, nil
+}
+
+func convertTaskFilter(filter *model.TaskFilterInput) views.Filter {
+	var f views.Filter
+	if filter == nil {
+		return f
+	}
+
+	if filter.Status != nil {
+		f.Status = string(*filter.Status)
+	}
+	if filter.Priority != nil {
+		f.Priority = string(*filter.Priority)
+	}
+	if filter.AssignedToID != nil {
+		f.AssignedToID = *filter.AssignedToID
+	}
+	if filter.CreatedByID != nil {
+		f.CreatedByID = *filter.CreatedByID
+	}
+	f.IncludeArchived = filter.IncludeArchived != nil && *filter.IncludeArchived
+
+	if filter.StatusIn != nil {
+		f.StatusIn = make([]string, len(filter.StatusIn))
+		for i, status := range filter.StatusIn {
+			f.StatusIn[i] = string(status)
+		}
+	}
+	if filter.PriorityIn != nil {
+		f.PriorityIn = make([]string, len(filter.PriorityIn))
+		for i, priority := range filter.PriorityIn {
+			f.PriorityIn[i] = string(priority)
+		}
+	}
+
+	f.DueBefore = filter.DueBefore
+	f.DueAfter = filter.DueAfter
+	f.CreatedAfter = filter.CreatedAfter
+	f.UpdatedSince = filter.UpdatedSince
+	f.Overdue = filter.Overdue
+	f.Unassigned = filter.Unassigned
+	if filter.TitleContains != nil {
+		f.TitleContains = *filter.TitleContains
+	}
+
+	for _, sub := range filter.And {
+		f.And = append(f.And, convertTaskFilter(sub))
+	}
+	for _, sub := range filter.Or {
+		f.Or = append(f.Or, convertTaskFilter(sub))
+	}
+	if filter.Not != nil {
+		not := convertTaskFilter(filter.Not)
+		f.Not = &not
+	}
+
+	return f
+}
+
+// toGraphQLTaskFilter converts a stored task filter for GraphQL
+func toGraphQLTaskFilter(f views.Filter) *model.TaskFilter {
+	filter := &model.TaskFilter{
+		IncludeArchived: f.IncludeArchived,
+		DueBefore:       f.DueBefore,
+		DueAfter:        f.DueAfter,
+		CreatedAfter:    f.CreatedAfter,
+		UpdatedSince:    f.UpdatedSince,
+		Overdue:         f.Overdue,
+		Unassigned:      f.Unassigned,
+	}
+	if f.Status != "" {
+		status := model.TaskStatus(f.Status)
+		filter.Status = &status
+	}
+	if f.Priority != "" {
+		priority := model.Priority(f.Priority)
+		filter.Priority = &priority
+	}
+	if f.AssignedToID != "" {
+		filter.AssignedToID = &f.AssignedToID
+	}
+	if f.CreatedByID != "" {
+		filter.CreatedByID = &f.CreatedByID
+	}
+	if f.TitleContains != "" {
+		filter.TitleContains = &f.TitleContains
+	}
+
+	for _, status := range f.StatusIn {
+		filter.StatusIn = append(filter.StatusIn, model.TaskStatus(status))
+	}
+	for _, priority := range f.PriorityIn {
+		filter.PriorityIn = append(filter.PriorityIn, model.Priority(priority))
+	}
+
+	for _, sub := range f.And {
+		filter.And = append(filter.And, toGraphQLTaskFilter(sub))
+	}
+	for _, sub := range f.Or {
+		filter.Or = append(filter.Or, toGraphQLTaskFilter(sub))
+	}
+	if f.Not != nil {
+		filter.Not = toGraphQLTaskFilter(*f.Not)
+	}
+
+	return filter
+}
+
+// viewSort converts a GraphQL task sort to its stored form
+func viewSort(sort *model.TaskSortInput) *views.Sort {
+	if sort == nil {
+		return nil
+	}
+
+	return &views.Sort{
+		Field:      strings.ToLower(string(sort.Field)),
+		Descending: sort.Direction == model.SortDirectionDesc,
+	}
+}
+
+const (
+	defaultViewPageSize = 50
+	maxViewPageSize     = 100
+)
+
+// encodeCursor returns the opaque cursor of the task at position in a list
+func encodeCursor(position int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("position:" + strconv.Itoa(position)))
+}
+
+// decodeCursor returns the position encoded by encodeCursor
+func decodeCursor(cursor string) (int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	position, err := strconv.Atoi(strings.TrimPrefix(string(data), "position:"))
+	if err != nil || position < 0 || !strings.HasPrefix(string(data), "position:") {
+		return 0, fmt.Errorf("invalid cursor")
+	}
+
+	return position, nil
+}
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/export"
+	"taskboard/internal/models"
+)
+
+// exportFlushEvery is how many rows are written between flushes to the client
+const exportFlushEvery = 500
+
+// exports serves /exports/tasks and /exports/{id}, where id is the token of an
+// export prepared through the GraphQL exportTasks mutation
+func (h *Handler) exports(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	segments := pathSegments(r, BasePath+"/exports")
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	if segments[0] == "tasks" {
+		h.exportTasks(w, r)
+		return
+	}
+
+	if h.cache == nil {
+		writeError(w, http.StatusNotFound, export.ErrRequestNotFound.Error())
+		return
+	}
+
+	req, err := export.Load(r.Context(), h.cache, segments[0])
+	if errors.Is(err, export.ErrRequestNotFound) {
+		writeError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to load export")
+		return
+	}
+
+	h.streamTasks(w, r, req.Format, req.TaskFilter())
+}
+
+func (h *Handler) exportTasks(w http.ResponseWriter, r *http.Request) {
+	if _, err := auth.RequireAuth(r.Context()); err != nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	format := export.FormatCSV
+	if v := r.URL.Query().Get("format"); v != "" {
+		var err error
+		if format, err = export.ParseFormat(v); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	filter, err := taskFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	h.streamTasks(w, r, format, filter)
+}
+
+// streamTasks writes matching tasks as an attachment while they are read from
+// the database. Once the first byte is out the status can no longer change,
+// so later failures are only logged and the response is cut short.
+func (h *Handler) streamTasks(w http.ResponseWriter, r *http.Request, format export.Format, filter map[string]interface{}) {
+	filename := fmt.Sprintf("tasks-%s.%s", time.Now().UTC().Format("20060102-150405"), format.Extension())
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	w.WriteHeader(http.StatusOK)
+
+	writer := export.NewWriter(w, format)
+	flusher, _ := w.(http.Flusher)
+
+	written := 0
+	err := h.taskRepo.Export(r.Context(), filter, func(task *models.TaskExport) error {
+		if err := writer.Write(task); err != nil {
+			return err
+		}
+
+		written++
+		if written%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
+		log.Printf("task export failed after %d rows: %v", written, err)
+	}
+}
@@ -0,0 +1,237 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/avatars"
+	"taskboard/internal/cache"
+	"taskboard/internal/repository"
+	"taskboard/internal/tasks"
+	"taskboard/internal/verification"
+)
+
+const (
+	// BasePath is the prefix every REST route is mounted under
+	BasePath = "/api/v1"
+
+	defaultPageSize = 50
+	maxPageSize     = 100
+	maxBodyBytes    = 1 << 20
+)
+
+// Handler serves the versioned REST/JSON API. It shares repositories, the
+// task service and authorization rules with the GraphQL resolvers.
+type Handler struct {
+	userRepo    repository.UserStore
+	taskRepo    repository.TaskStore
+	taskService *tasks.Service
+	avatars     *avatars.Service
+	cache       cache.Cache
+	mux         *http.ServeMux
+	// Whether users must verify their email address before changing tasks
+	requireVerifiedEmail bool
+}
+
+func NewHandler(
+	userRepo repository.UserStore,
+	taskRepo repository.TaskStore,
+	taskService *tasks.Service,
+	avatars *avatars.Service,
+	cache cache.Cache,
+	requireVerifiedEmail bool,
+) *Handler {
+	h := &Handler{
+		userRepo:             userRepo,
+		taskRepo:             taskRepo,
+		taskService:          taskService,
+		avatars:              avatars,
+		cache:                cache,
+		mux:                  http.NewServeMux(),
+		requireVerifiedEmail: requireVerifiedEmail,
+	}
+
+	h.mux.HandleFunc(BasePath+"/openapi.json", h.openAPI)
+	h.mux.HandleFunc(BasePath+"/tasks", h.tasks)
+	h.mux.HandleFunc(BasePath+"/tasks/", h.task)
+	h.mux.HandleFunc(BasePath+"/users", h.users)
+	h.mux.HandleFunc(BasePath+"/users/", h.user)
+	h.mux.HandleFunc(BasePath+"/exports/", h.exports)
+	h.mux.HandleFunc(BasePath+"/imports/", h.imports)
+	h.mux.HandleFunc(BasePath+"/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "route not found")
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+// Page is the envelope returned by list endpoints
+type Page struct {
+	Data   interface{} `json:"data"`
+	Limit  int         `json:"limit"`
+	Offset int         `json:"offset"`
+	Total  int         `json:"total"`
+}
+
+// Error is the body of every non-2xx response
+type Error struct {
+	Error string `json:"error"`
+}
+
+// pathSegments returns the parts of the URL path after prefix
+func pathSegments(r *http.Request, prefix string) []string {
+	rest := strings.Trim(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	if rest == "" {
+		return nil
+	}
+	return strings.Split(rest, "/")
+}
+
+// pagination reads limit and offset query parameters
+func pagination(r *http.Request) (limit, offset int, err error) {
+	limit = defaultPageSize
+	if v := r.URL.Query().Get("limit"); v != "" {
+		limit, err = strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxPageSize {
+			return 0, 0, fmt.Errorf("limit must be between 1 and %d", maxPageSize)
+		}
+	}
+
+	if v := r.URL.Query().Get("offset"); v != "" {
+		offset, err = strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return 0, 0, errors.New("offset must be a non-negative integer")
+		}
+	}
+
+	return limit, offset, nil
+}
+
+// decodeJSON strictly decodes a JSON request body into dest
+func decodeJSON(w http.ResponseWriter, r *http.Request, dest interface{}) error {
+	if ct := r.Header.Get("Content-Type"); ct != "" && !strings.HasPrefix(ct, "application/json") {
+		return errors.New("content type must be application/json")
+	}
+
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(dest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return errors.New("request body is required")
+		}
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+
+	return nil
+}
+
+// etag returns a strong entity tag for an encoded representation
+func etag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// matchesETag reports whether an If-Match / If-None-Match header value
+// matches tag
+func matchesETag(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// checkPrecondition enforces If-Match against the current representation of
+// a resource. It writes a 412 and returns false when the client's copy is stale.
+func checkPrecondition(w http.ResponseWriter, r *http.Request, current interface{}) bool {
+	header := r.Header.Get("If-Match")
+	if header == "" {
+		return true
+	}
+
+	body, err := json.Marshal(current)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode resource")
+		return false
+	}
+
+	if !matchesETag(header, etag(body)) {
+		writeError(w, http.StatusPreconditionFailed, "resource has been modified")
+		return false
+	}
+
+	return true
+}
+
+// writeJSON encodes v with an ETag. Successful GET requests whose
+// If-None-Match matches are answered with 304 Not Modified.
+func writeJSON(w http.ResponseWriter, r *http.Request, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to encode response")
+		return
+	}
+
+	tag := etag(body)
+	w.Header().Set("ETag", tag)
+
+	if r.Method == http.MethodGet && status == http.StatusOK && matchesETag(r.Header.Get("If-None-Match"), tag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(body)
+}
+
+// requireVerified authenticates a request that changes tasks, rejecting users
+// who have not verified their email address when that is required. It writes
+// the error response and returns false when the request may not proceed.
+func (h *Handler) requireVerified(w http.ResponseWriter, r *http.Request) (*auth.Claims, bool) {
+	claims, err := auth.RequireAuth(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return nil, false
+	}
+	if !h.requireVerifiedEmail {
+		return claims, true
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return nil, false
+	}
+	if user.EmailVerifiedAt == nil {
+		writeError(w, http.StatusForbidden, verification.ErrNotVerified.Error())
+		return nil, false
+	}
+
+	return claims, true
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Error{Error: message})
+}
+
+func methodNotAllowed(w http.ResponseWriter, allowed ...string) {
+	w.Header().Set("Allow", strings.Join(allowed, ", "))
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+}
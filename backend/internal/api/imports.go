@@ -0,0 +1,101 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"taskboard/internal/importer"
+)
+
+// maxImportBytes caps the size of an uploaded CSV file
+const maxImportBytes = 10 << 20
+
+// imports serves /imports/tasks
+func (h *Handler) imports(w http.ResponseWriter, r *http.Request) {
+	segments := pathSegments(r, BasePath+"/imports")
+	if len(segments) != 1 || segments[0] != "tasks" {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	if r.Method != http.MethodPost {
+		methodNotAllowed(w, http.MethodPost)
+		return
+	}
+
+	h.importTasks(w, r)
+}
+
+// importTasks creates tasks from an uploaded CSV file. The multipart form
+// carries the file in "file", an optional JSON column mapping in "mapping"
+// and "dry_run" to only validate. Nothing is inserted unless every row is valid.
+func (h *Handler) importTasks(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportBytes)
+	if err := r.ParseMultipartForm(maxImportBytes); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			writeError(w, http.StatusRequestEntityTooLarge, "file is larger than 10MB")
+			return
+		}
+		writeError(w, http.StatusBadRequest, "request must be a multipart form")
+		return
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "file is required")
+		return
+	}
+	defer file.Close()
+
+	var mapping importer.Mapping
+	if raw := r.FormValue("mapping"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &mapping); err != nil {
+			writeError(w, http.StatusBadRequest, "mapping must be a JSON object of field to column name")
+			return
+		}
+	}
+
+	dryRun := false
+	if raw := r.FormValue("dry_run"); raw != "" {
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			writeError(w, http.StatusBadRequest, "dry_run must be a boolean")
+			return
+		}
+	}
+
+	tasks, report, err := importer.ParseCSV(r.Context(), file, mapping, claims.UserID, h.userRepo.IDsByEmail)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	report.DryRun = dryRun
+	if dryRun {
+		writeJSON(w, r, http.StatusOK, report)
+		return
+	}
+	if len(report.Errors) > 0 {
+		writeJSON(w, r, http.StatusUnprocessableEntity, report)
+		return
+	}
+
+	if len(tasks) > 0 {
+		imported, err := h.taskRepo.BulkCreate(r.Context(), tasks)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to import tasks")
+			return
+		}
+		report.Imported = int(imported)
+		h.invalidateTask(r.Context(), "")
+	}
+
+	writeJSON(w, r, http.StatusCreated, report)
+}
@@ -0,0 +1,294 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"taskboard/internal/importer"
+	"taskboard/internal/models"
+)
+
+// operation describes one REST route. The OpenAPI document is generated from
+// this table so it cannot drift from the request and response types.
+type operation struct {
+	method   string
+	path     string
+	summary  string
+	auth     bool
+	paged    bool
+	params   []string
+	request  interface{}
+	response interface{}
+	status   int
+}
+
+var operations = []operation{
+	{method: "get", path: "/tasks", summary: "List tasks", paged: true,
+		params:   []string{"status", "priority", "assigned_to_id", "created_by_id", "include_archived"},
+		response: models.TaskWithRelations{}, status: http.StatusOK},
+	{method: "post", path: "/tasks", summary: "Create a task", auth: true,
+		request: CreateTaskRequest{}, response: models.TaskWithRelations{}, status: http.StatusCreated},
+	{method: "get", path: "/tasks/{id}", summary: "Get a task",
+		response: models.TaskWithRelations{}, status: http.StatusOK},
+	{method: "patch", path: "/tasks/{id}", summary: "Update a task", auth: true,
+		request: UpdateTaskRequest{}, response: models.TaskWithRelations{}, status: http.StatusOK},
+	{method: "delete", path: "/tasks/{id}", summary: "Delete a task", auth: true,
+		status: http.StatusNoContent},
+	{method: "put", path: "/tasks/{id}/assignee", summary: "Assign a task", auth: true,
+		request: AssignTaskRequest{}, response: models.TaskWithRelations{}, status: http.StatusOK},
+	{method: "delete", path: "/tasks/{id}/assignee", summary: "Unassign a task", auth: true,
+		response: models.TaskWithRelations{}, status: http.StatusOK},
+	{method: "get", path: "/exports/tasks", summary: "Export tasks as CSV or JSON Lines", auth: true,
+		params: []string{"format", "status", "priority", "assigned_to_id", "created_by_id", "include_archived"},
+		status: http.StatusOK},
+	{method: "get", path: "/exports/{id}", summary: "Download an export prepared with the exportTasks mutation",
+		status: http.StatusOK},
+	{method: "post", path: "/imports/tasks", summary: "Import tasks from a CSV file uploaded as multipart/form-data", auth: true,
+		response: importer.Report{}, status: http.StatusCreated},
+	{method: "get", path: "/users", summary: "List users", paged: true,
+		response: models.User{}, status: http.StatusOK},
+	{method: "get", path: "/users/me", summary: "Get the authenticated user", auth: true,
+		response: models.User{}, status: http.StatusOK},
+	{method: "patch", path: "/users/me", summary: "Update the authenticated user's profile", auth: true,
+		request: UpdateProfileRequest{}, response: models.User{}, status: http.StatusOK},
+	{method: "get", path: "/users/{id}", summary: "Get a user",
+		response: models.User{}, status: http.StatusOK},
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  map[string]interface{}
+)
+
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	openAPIOnce.Do(func() { openAPIDoc = OpenAPIDocument() })
+	writeJSON(w, r, http.StatusOK, openAPIDoc)
+}
+
+// OpenAPIDocument builds the OpenAPI 3 description of the REST API
+func OpenAPIDocument() map[string]interface{} {
+	g := &schemaGenerator{schemas: map[string]interface{}{}}
+
+	paths := map[string]interface{}{}
+	for _, op := range operations {
+		item, ok := paths[BasePath+op.path].(map[string]interface{})
+		if !ok {
+			item = map[string]interface{}{}
+			paths[BasePath+op.path] = item
+		}
+		item[op.method] = g.operation(op)
+	}
+
+	return map[string]interface{}{
+		"openapi": "3.0.3",
+		"info": map[string]interface{}{
+			"title":   "TaskBoard REST API",
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]interface{}{
+				"bearerAuth": map[string]interface{}{
+					"type":         "http",
+					"scheme":       "bearer",
+					"bearerFormat": "JWT",
+				},
+			},
+		},
+	}
+}
+
+type schemaGenerator struct {
+	schemas map[string]interface{}
+}
+
+func (g *schemaGenerator) operation(op operation) map[string]interface{} {
+	var params []interface{}
+	if strings.Contains(op.path, "{id}") {
+		params = append(params, parameter("id", "path", "string", true))
+	}
+	if op.paged {
+		params = append(params,
+			parameter("limit", "query", "integer", false),
+			parameter("offset", "query", "integer", false),
+		)
+	}
+	for _, name := range op.params {
+		params = append(params, parameter(name, "query", "string", false))
+	}
+	if op.method != "get" {
+		params = append(params, header("If-Match"))
+	} else {
+		params = append(params, header("If-None-Match"))
+	}
+
+	responses := map[string]interface{}{
+		"default": jsonContent("Error", g.ref(reflect.TypeOf(Error{}))),
+	}
+
+	status := http.StatusText(op.status)
+	switch {
+	case op.response == nil:
+		responses[strconv.Itoa(op.status)] = map[string]interface{}{"description": status}
+	case op.paged:
+		responses[strconv.Itoa(op.status)] = jsonContent(status, g.pageSchema(reflect.TypeOf(op.response)))
+	default:
+		responses[strconv.Itoa(op.status)] = jsonContent(status, g.ref(reflect.TypeOf(op.response)))
+	}
+
+	result := map[string]interface{}{
+		"summary":   op.summary,
+		"responses": responses,
+	}
+	if len(params) > 0 {
+		result["parameters"] = params
+	}
+	if op.request != nil {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content": map[string]interface{}{
+				"application/json": map[string]interface{}{
+					"schema": g.ref(reflect.TypeOf(op.request)),
+				},
+			},
+		}
+	}
+	if op.auth {
+		result["security"] = []interface{}{map[string]interface{}{"bearerAuth": []string{}}}
+	}
+
+	return result
+}
+
+// ref registers t as a component schema and returns a reference to it
+func (g *schemaGenerator) ref(t reflect.Type) map[string]interface{} {
+	if _, ok := g.schemas[t.Name()]; !ok {
+		// Reserve the name first so self-referencing types terminate
+		g.schemas[t.Name()] = nil
+		g.schemas[t.Name()] = g.structSchema(t)
+	}
+	return map[string]interface{}{"$ref": "#/components/schemas/" + t.Name()}
+}
+
+func (g *schemaGenerator) pageSchema(item reflect.Type) map[string]interface{} {
+	return map[string]interface{}{
+		"type":     "object",
+		"required": []string{"data", "limit", "offset", "total"},
+		"properties": map[string]interface{}{
+			"data":   map[string]interface{}{"type": "array", "items": g.ref(item)},
+			"limit":  map[string]interface{}{"type": "integer"},
+			"offset": map[string]interface{}{"type": "integer"},
+			"total":  map[string]interface{}{"type": "integer"},
+		},
+	}
+}
+
+func (g *schemaGenerator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	g.collectFields(t, properties, &required)
+
+	schema := map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// collectFields walks exported, JSON-visible fields, flattening embedded structs
+// the same way encoding/json does
+func (g *schemaGenerator) collectFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if !field.IsExported() || tag == "-" {
+			continue
+		}
+
+		name := strings.Split(tag, ",")[0]
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			g.collectFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		ft := field.Type
+		nullable := false
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+			nullable = true
+		}
+
+		schema := g.typeSchema(ft)
+		if nullable {
+			if _, isRef := schema["$ref"]; isRef {
+				schema = map[string]interface{}{"allOf": []interface{}{schema}, "nullable": true}
+			} else {
+				schema["nullable"] = true
+			}
+		} else {
+			*required = append(*required, name)
+		}
+		properties[name] = schema
+	}
+}
+
+func (g *schemaGenerator) typeSchema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.typeSchema(t.Elem())}
+	case reflect.Struct:
+		return g.ref(t)
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+func parameter(name, in, typ string, required bool) map[string]interface{} {
+	return map[string]interface{}{
+		"name":     name,
+		"in":       in,
+		"required": required,
+		"schema":   map[string]interface{}{"type": typ},
+	}
+}
+
+func header(name string) map[string]interface{} {
+	return parameter(name, "header", "string", false)
+}
+
+func jsonContent(description string, schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"description": description,
+		"content": map[string]interface{}{
+			"application/json": map[string]interface{}{"schema": schema},
+		},
+	}
+}
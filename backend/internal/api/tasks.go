@@ -0,0 +1,399 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/cache"
+	"taskboard/internal/models"
+	"taskboard/internal/tasks"
+)
+
+// CreateTaskRequest is the body of POST /tasks
+type CreateTaskRequest struct {
+	Title          string     `json:"title"`
+	Description    *string    `json:"description"`
+	Status         *string    `json:"status"`
+	Priority       *string    `json:"priority"`
+	AssignedToID   *string    `json:"assigned_to_id"`
+	DueDate        *time.Time `json:"due_date"`
+	Recurrence     *string    `json:"recurrence"`
+	StoryPoints    *int       `json:"story_points"`
+	EstimatedHours *float64   `json:"estimated_hours"`
+}
+
+// UpdateTaskRequest is the body of PATCH /tasks/{id}. Omitted fields are left unchanged.
+type UpdateTaskRequest struct {
+	Title        *string    `json:"title"`
+	Description  *string    `json:"description"`
+	Status       *string    `json:"status"`
+	Priority     *string    `json:"priority"`
+	AssignedToID *string    `json:"assigned_to_id"`
+	DueDate      *time.Time `json:"due_date"`
+	// An empty string stops the task from recurring
+	Recurrence     *string  `json:"recurrence"`
+	StoryPoints    *int     `json:"story_points"`
+	EstimatedHours *float64 `json:"estimated_hours"`
+}
+
+// AssignTaskRequest is the body of PUT /tasks/{id}/assignee
+type AssignTaskRequest struct {
+	UserID string `json:"user_id"`
+}
+
+// tasks serves the task collection
+func (h *Handler) tasks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.listTasks(w, r)
+	case http.MethodPost:
+		h.createTask(w, r)
+	default:
+		methodNotAllowed(w, http.MethodGet, http.MethodPost)
+	}
+}
+
+// task serves /tasks/{id} and /tasks/{id}/assignee
+func (h *Handler) task(w http.ResponseWriter, r *http.Request) {
+	segments := pathSegments(r, BasePath+"/tasks")
+
+	switch {
+	case len(segments) == 1:
+		switch r.Method {
+		case http.MethodGet:
+			h.getTask(w, r, segments[0])
+		case http.MethodPatch:
+			h.updateTask(w, r, segments[0])
+		case http.MethodDelete:
+			h.deleteTask(w, r, segments[0])
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodPatch, http.MethodDelete)
+		}
+	case len(segments) == 2 && segments[1] == "assignee":
+		switch r.Method {
+		case http.MethodPut:
+			h.assignTask(w, r, segments[0])
+		case http.MethodDelete:
+			h.unassignTask(w, r, segments[0])
+		default:
+			methodNotAllowed(w, http.MethodPut, http.MethodDelete)
+		}
+	default:
+		writeError(w, http.StatusNotFound, "route not found")
+	}
+}
+
+func (h *Handler) listTasks(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	filter, err := taskFilter(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := h.taskRepo.Count(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to count tasks")
+		return
+	}
+
+	filter["limit"] = limit
+	filter["offset"] = offset
+
+	tasks, err := h.taskRepo.List(r.Context(), filter)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list tasks")
+		return
+	}
+
+	users := make(map[string]*models.User)
+	data := make([]*models.TaskWithRelations, 0, len(tasks))
+	for _, task := range tasks {
+		withRelations, err := h.taskWithRelations(r.Context(), task, users)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+		data = append(data, withRelations)
+	}
+
+	writeJSON(w, r, http.StatusOK, Page{Data: data, Limit: limit, Offset: offset, Total: total})
+}
+
+// taskFilter reads the task filter query parameters shared by list and export routes
+func taskFilter(r *http.Request) (map[string]interface{}, error) {
+	query := r.URL.Query()
+	filter := make(map[string]interface{})
+
+	if status := query.Get("status"); status != "" {
+		if !contains(tasks.Statuses, status) {
+			return nil, errors.New("invalid status")
+		}
+		filter["status"] = status
+	}
+	if priority := query.Get("priority"); priority != "" {
+		if !contains(tasks.Priorities, priority) {
+			return nil, errors.New("invalid priority")
+		}
+		filter["priority"] = priority
+	}
+	if assignedToID := query.Get("assigned_to_id"); assignedToID != "" {
+		filter["assigned_to_id"] = assignedToID
+	}
+	if createdByID := query.Get("created_by_id"); createdByID != "" {
+		filter["created_by_id"] = createdByID
+	}
+	if includeArchived := query.Get("include_archived"); includeArchived != "" {
+		value, err := strconv.ParseBool(includeArchived)
+		if err != nil {
+			return nil, errors.New("invalid include_archived")
+		}
+		filter["include_archived"] = value
+	}
+
+	return filter, nil
+}
+
+func (h *Handler) getTask(w http.ResponseWriter, r *http.Request, id string) {
+	task, ok := h.loadTask(w, r, id)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, task)
+}
+
+func (h *Handler) createTask(w http.ResponseWriter, r *http.Request) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	var req CreateTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Create(r.Context(), claims.UserID, tasks.CreateInput(req))
+	if err != nil {
+		writeTaskError(w, err, "failed to create task")
+		return
+	}
+
+	h.invalidateTask(r.Context(), "")
+
+	created, err := h.taskWithRelations(r.Context(), task, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", BasePath+"/tasks/"+task.ID)
+	writeJSON(w, r, http.StatusCreated, created)
+}
+
+func (h *Handler) updateTask(w http.ResponseWriter, r *http.Request, id string) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.loadTask(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !auth.CanModifyTask(claims, &existing.Task) {
+		writeError(w, http.StatusForbidden, "you can only update your own tasks")
+		return
+	}
+
+	if !checkPrecondition(w, r, existing) {
+		return
+	}
+
+	var req UpdateTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Update(r.Context(), claims.UserID, &existing.Task, tasks.UpdateInput(req))
+	h.respondUpdated(w, r, task, err)
+}
+
+func (h *Handler) deleteTask(w http.ResponseWriter, r *http.Request, id string) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.loadTask(w, r, id)
+	if !ok {
+		return
+	}
+
+	if !auth.CanModifyTask(claims, &existing.Task) {
+		writeError(w, http.StatusForbidden, "you can only delete your own tasks")
+		return
+	}
+
+	if !checkPrecondition(w, r, existing) {
+		return
+	}
+
+	if err := h.taskRepo.Delete(r.Context(), id); err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to delete task")
+		return
+	}
+
+	h.invalidateTask(r.Context(), id)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) assignTask(w http.ResponseWriter, r *http.Request, id string) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.loadTask(w, r, id)
+	if !ok || !checkPrecondition(w, r, existing) {
+		return
+	}
+
+	var req AssignTaskRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	task, err := h.taskService.Assign(r.Context(), claims.UserID, &existing.Task, &req.UserID)
+	h.respondUpdated(w, r, task, err)
+}
+
+func (h *Handler) unassignTask(w http.ResponseWriter, r *http.Request, id string) {
+	claims, ok := h.requireVerified(w, r)
+	if !ok {
+		return
+	}
+
+	existing, ok := h.loadTask(w, r, id)
+	if !ok || !checkPrecondition(w, r, existing) {
+		return
+	}
+
+	task, err := h.taskService.Assign(r.Context(), claims.UserID, &existing.Task, nil)
+	h.respondUpdated(w, r, task, err)
+}
+
+// respondUpdated responds to a task update with the new representation of
+// task, or with the error the update failed with
+func (h *Handler) respondUpdated(w http.ResponseWriter, r *http.Request, task *models.Task, err error) {
+	if err != nil {
+		writeTaskError(w, err, "failed to update task")
+		return
+	}
+
+	h.invalidateTask(r.Context(), task.ID)
+
+	updated, err := h.taskWithRelations(r.Context(), task, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, updated)
+}
+
+// loadTask fetches a task with its relations, writing a 404 if it does not exist
+func (h *Handler) loadTask(w http.ResponseWriter, r *http.Request, id string) (*models.TaskWithRelations, bool) {
+	task, err := h.taskRepo.GetByID(r.Context(), id)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "task not found")
+		return nil, false
+	}
+
+	withRelations, err := h.taskWithRelations(r.Context(), task, nil)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+
+	return withRelations, true
+}
+
+// taskWithRelations resolves the creator and assignee of a task. users, when
+// non-nil, memoizes lookups across a list response.
+func (h *Handler) taskWithRelations(ctx context.Context, task *models.Task, users map[string]*models.User) (*models.TaskWithRelations, error) {
+	getUser := func(id string) (*models.User, error) {
+		if user, ok := users[id]; ok {
+			return user, nil
+		}
+		user, err := h.userRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if users != nil {
+			users[id] = user
+		}
+		return user, nil
+	}
+
+	creator, err := getUser(task.CreatedByID)
+	if err != nil {
+		return nil, errors.New("failed to get creator")
+	}
+
+	result := &models.TaskWithRelations{Task: *task, CreatedBy: creator}
+
+	if task.AssignedToID != nil {
+		if assignee, err := getUser(*task.AssignedToID); err == nil {
+			result.AssignedTo = assignee
+		}
+	}
+
+	return result, nil
+}
+
+// invalidateTask mirrors the cache invalidation done by the task resolvers
+func (h *Handler) invalidateTask(ctx context.Context, id string) {
+	if h.cache == nil {
+		return
+	}
+	if id != "" {
+		h.cache.Delete(ctx, cache.TaskKey(id))
+	}
+	h.cache.DeletePattern(ctx, "tasks:*")
+}
+
+// writeTaskError writes a 422 for input the task service rejected, and a 500
+// with message for any other failure
+func writeTaskError(w http.ResponseWriter, err error, message string) {
+	var invalid *tasks.ValidationError
+	if errors.As(err, &invalid) {
+		writeError(w, http.StatusUnprocessableEntity, invalid.Message)
+		return
+	}
+	writeError(w, http.StatusInternalServerError, message)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,158 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/avatars"
+	"taskboard/internal/cache"
+	"taskboard/internal/models"
+)
+
+// UpdateProfileRequest is the body of PATCH /users/me
+type UpdateProfileRequest struct {
+	Name   *string `json:"name"`
+	Avatar *string `json:"avatar"`
+}
+
+// users serves the user collection
+func (h *Handler) users(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	limit, offset, err := pagination(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	total, err := h.userRepo.Count(r.Context())
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to count users")
+		return
+	}
+
+	users, err := h.userRepo.ListPage(r.Context(), limit, offset)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to list users")
+		return
+	}
+	if users == nil {
+		users = []*models.User{}
+	}
+
+	writeJSON(w, r, http.StatusOK, Page{Data: users, Limit: limit, Offset: offset, Total: total})
+}
+
+// user serves /users/{id} and /users/me
+func (h *Handler) user(w http.ResponseWriter, r *http.Request) {
+	segments := pathSegments(r, BasePath+"/users")
+	if len(segments) != 1 {
+		writeError(w, http.StatusNotFound, "route not found")
+		return
+	}
+
+	if segments[0] == "me" {
+		switch r.Method {
+		case http.MethodGet:
+			h.getMe(w, r)
+		case http.MethodPatch:
+			h.updateMe(w, r)
+		default:
+			methodNotAllowed(w, http.MethodGet, http.MethodPatch)
+		}
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, http.MethodGet)
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), segments[0])
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, user)
+}
+
+func (h *Handler) getMe(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.RequireAuth(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	user, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	writeJSON(w, r, http.StatusOK, user)
+}
+
+func (h *Handler) updateMe(w http.ResponseWriter, r *http.Request) {
+	claims, err := auth.RequireAuth(r.Context())
+	if err != nil {
+		writeError(w, http.StatusUnauthorized, "unauthorized")
+		return
+	}
+
+	current, err := h.userRepo.GetByID(r.Context(), claims.UserID)
+	if err != nil {
+		writeError(w, http.StatusNotFound, "user not found")
+		return
+	}
+
+	if !checkPrecondition(w, r, current) {
+		return
+	}
+
+	var req UpdateProfileRequest
+	if err := decodeJSON(w, r, &req); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			writeError(w, http.StatusUnprocessableEntity, "name cannot be empty")
+			return
+		}
+		updates["name"] = *req.Name
+	}
+	if req.Avatar != nil {
+		switch {
+		case *req.Avatar == "":
+			updates["avatar"] = (*string)(nil)
+		case avatars.OwnedBy(*req.Avatar, claims.UserID):
+			updates["avatar"] = req.Avatar
+		default:
+			writeError(w, http.StatusUnprocessableEntity, "avatar must be uploaded with the uploadAvatar mutation")
+			return
+		}
+	}
+
+	user, err := h.userRepo.Update(r.Context(), claims.UserID, updates)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "failed to update profile")
+		return
+	}
+
+	if current.Avatar != nil && (user.Avatar == nil || *user.Avatar != *current.Avatar) {
+		h.avatars.Delete(r.Context(), *current.Avatar)
+	}
+
+	if h.cache != nil {
+		h.cache.Delete(r.Context(), cache.UserKey(claims.UserID))
+	}
+
+	writeJSON(w, r, http.StatusOK, user)
+}
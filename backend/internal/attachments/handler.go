@@ -0,0 +1,93 @@
+package attachments
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskboard/internal/storage"
+)
+
+// Handler serves attachment content for signed links made by
+// Service.DownloadURL. The signature is the only credential, so links can be
+// used directly in <img> tags and by browsers downloading files.
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, DownloadPath)
+	if id == "" || strings.Contains(id, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	err := h.service.signer.Verify(DownloadPath+id, r.URL.Query(), time.Now())
+	if errors.Is(err, storage.ErrURLExpired) {
+		http.Error(w, err.Error(), http.StatusGone)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
+	attachment, err := h.service.repo.GetByID(r.Context(), id)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	content, err := h.service.blobs.Open(r.Context(), attachment.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to open attachment %s: %v", attachment.ID, err)
+		http.Error(w, "failed to read attachment", http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"; filename*=UTF-8''%s`,
+		asciiFilename(attachment.Filename), url.PathEscape(attachment.Filename)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("failed to send attachment %s: %v", attachment.ID, err)
+	}
+}
+
+// asciiFilename is the fallback filename for clients without RFC 5987 support
+func asciiFilename(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+}
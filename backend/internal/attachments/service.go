@@ -0,0 +1,208 @@
+// Package attachments stores files uploaded to tasks and serves them through
+// signed, expiring links.
+package attachments
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+	"taskboard/internal/storage"
+)
+
+// DownloadPath is where attachment content is served, as DownloadPath + ID
+const DownloadPath = "/attachments/"
+
+const maxFilenameLength = 255
+
+var (
+	ErrTooLarge        = errors.New("file is too large")
+	ErrTypeNotAllowed  = errors.New("file type is not allowed")
+	ErrMissingFilename = errors.New("filename is required")
+)
+
+// Limits restricts what may be uploaded
+type Limits struct {
+	MaxBytes     int64
+	AllowedTypes []string
+}
+
+// Service stores attachment content in a blob store and its metadata in the database
+type Service struct {
+	repo   *repository.AttachmentRepository
+	blobs  storage.Blob
+	signer *storage.URLSigner
+	limits Limits
+}
+
+func NewService(repo *repository.AttachmentRepository, blobs storage.Blob, signer *storage.URLSigner, limits Limits) *Service {
+	return &Service{repo: repo, blobs: blobs, signer: signer, limits: limits}
+}
+
+// Limits returns the upload limits
+func (s *Service) Limits() Limits {
+	return s.limits
+}
+
+// Get returns an attachment's metadata
+func (s *Service) Get(ctx context.Context, id string) (*models.Attachment, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// List returns a task's attachments, oldest first
+func (s *Service) List(ctx context.Context, taskID string) ([]*models.Attachment, error) {
+	return s.repo.ListByTask(ctx, taskID)
+}
+
+// Upload stores content as a new attachment of a task. The content type is
+// sniffed from the content itself; the client's claim is not trusted. Neither
+// is the declared size, which only rejects oversized uploads before reading.
+func (s *Service) Upload(ctx context.Context, taskID, uploaderID, filename string, content io.Reader, size int64) (*models.Attachment, error) {
+	filename = cleanFilename(filename)
+	if filename == "" {
+		return nil, ErrMissingFilename
+	}
+	if size > s.limits.MaxBytes {
+		return nil, ErrTooLarge
+	}
+
+	buffered := bufio.NewReaderSize(content, 512)
+	head, err := buffered.Peek(512)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+
+	contentType, err := s.CheckType(head)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		ID:          uuid.New().String(),
+		TaskID:      taskID,
+		UploaderID:  uploaderID,
+		Filename:    filename,
+		ContentType: contentType,
+	}
+	attachment.StorageKey = fmt.Sprintf("attachments/%s/%s", taskID, attachment.ID)
+
+	// The declared size may be wrong, so spool the content and give the blob
+	// store the counted length; nothing over the limit is ever stored
+	spool, err := os.CreateTemp("", "upload-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool upload: %w", err)
+	}
+	defer os.Remove(spool.Name())
+	defer spool.Close()
+
+	n, err := io.Copy(spool, io.LimitReader(buffered, s.limits.MaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read upload: %w", err)
+	}
+	if n > s.limits.MaxBytes {
+		return nil, ErrTooLarge
+	}
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to spool upload: %w", err)
+	}
+
+	if err := s.blobs.Put(ctx, attachment.StorageKey, spool, n, contentType); err != nil {
+		return nil, err
+	}
+	attachment.Size = n
+
+	created, err := s.repo.Create(ctx, attachment)
+	if err != nil {
+		s.deleteBlob(ctx, attachment.StorageKey)
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// CheckType sniffs the media type of content from its first bytes and checks
+// it against the allowed types
+func (s *Service) CheckType(head []byte) (string, error) {
+	contentType, _, err := mime.ParseMediaType(http.DetectContentType(head))
+	if err != nil {
+		return "", ErrTypeNotAllowed
+	}
+
+	for _, allowed := range s.limits.AllowedTypes {
+		if strings.EqualFold(strings.TrimSpace(allowed), contentType) {
+			return contentType, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w: %s", ErrTypeNotAllowed, contentType)
+}
+
+// Delete removes an attachment and its content
+func (s *Service) Delete(ctx context.Context, attachment *models.Attachment) error {
+	if err := s.repo.Delete(ctx, attachment.ID); err != nil {
+		return err
+	}
+
+	s.deleteBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+// DeleteContent removes the content stored under keys, for attachments whose
+// rows were removed along with their task
+func (s *Service) DeleteContent(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		s.deleteBlob(ctx, key)
+	}
+}
+
+// DownloadURL returns a relative link to the attachment's content that
+// expires after the configured TTL
+func (s *Service) DownloadURL(attachment *models.Attachment) string {
+	return s.signer.Sign(DownloadPath+attachment.ID, time.Now())
+}
+
+// deleteBlob removes orphaned content. Failures only leave garbage behind, so
+// they are logged rather than returned.
+func (s *Service) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Printf("failed to delete blob %s: %v", key, err)
+	}
+}
+
+// cleanFilename keeps the base name and drops characters that would break a
+// Content-Disposition header
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		return ""
+	}
+
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+
+	name = strings.TrimSpace(name)
+	for len(name) > maxFilenameLength {
+		_, width := utf8.DecodeLastRuneInString(name)
+		name = name[:len(name)-width]
+	}
+
+	return name
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
 var (
@@ -15,6 +16,8 @@ var (
 type Claims struct {
 	UserID string `json:"user_id"`
 	Email  string `json:"email"`
+	// SessionID is the login an access token was issued for
+	SessionID string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -34,11 +37,12 @@ func NewJWTManager(secretKey, refreshSecretKey string) *JWTManager {
 	}
 }
 
-// GenerateAccessToken creates a new JWT access token
-func (m *JWTManager) GenerateAccessToken(userID, email string) (string, error) {
+// GenerateAccessToken creates a new JWT access token for a session
+func (m *JWTManager) GenerateAccessToken(userID, email, sessionID string) (string, error) {
 	claims := Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:    userID,
+		Email:     email,
+		SessionID: sessionID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.accessDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
@@ -50,12 +54,15 @@ func (m *JWTManager) GenerateAccessToken(userID, email string) (string, error) {
 	return token.SignedString(m.secretKey)
 }
 
-// GenerateRefreshToken creates a new refresh token
+// GenerateRefreshToken creates a new refresh token. Each token carries a
+// unique ID, so tokens issued in the same second can be told apart once
+// stored.
 func (m *JWTManager) GenerateRefreshToken(userID, email string) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(m.refreshDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
@@ -66,6 +73,11 @@ func (m *JWTManager) GenerateRefreshToken(userID, email string) (string, error)
 	return token.SignedString(m.refreshSecretKey)
 }
 
+// RefreshDuration returns how long refresh tokens are valid for
+func (m *JWTManager) RefreshDuration() time.Duration {
+	return m.refreshDuration
+}
+
 // ValidateAccessToken verifies and parses an access token
 func (m *JWTManager) ValidateAccessToken(tokenString string) (*Claims, error) {
 	return m.validateToken(tokenString, m.secretKey)
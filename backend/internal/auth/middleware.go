@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"net"
 	"net/http"
 	"strings"
 )
@@ -10,17 +11,33 @@ type contextKey string
 
 const UserContextKey contextKey = "user"
 
+const clientContextKey contextKey = "client"
+
+// Client describes where a request came from
+type Client struct {
+	UserAgent string
+	IP        string
+}
+
 type AuthMiddleware struct {
 	jwtManager *JWTManager
+	sessions   *SessionChecker
+	trustProxy bool
 }
 
-func NewAuthMiddleware(jwtManager *JWTManager) *AuthMiddleware {
-	return &AuthMiddleware{jwtManager: jwtManager}
+// NewAuthMiddleware returns middleware authenticating requests with
+// jwtManager's access tokens. When sessions is not nil, tokens of revoked
+// sessions are rejected. With trustProxy, the client address is the one a
+// reverse proxy reports in X-Forwarded-For.
+func NewAuthMiddleware(jwtManager *JWTManager, sessions *SessionChecker, trustProxy bool) *AuthMiddleware {
+	return &AuthMiddleware{jwtManager: jwtManager, sessions: sessions, trustProxy: trustProxy}
 }
 
 // Middleware extracts and validates JWT from Authorization header
 func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = r.WithContext(WithClient(r.Context(), m.client(r)))
+
 		authHeader := r.Header.Get("Authorization")
 		
 		if authHeader == "" {
@@ -43,12 +60,49 @@ func (m *AuthMiddleware) Middleware(next http.Handler) http.Handler {
 			return
 		}
 
+		// Tokens issued before sessions were recorded carry none; they
+		// expire on their own shortly
+		if claims.SessionID != "" && m.sessions != nil && !m.sessions.Active(r.Context(), claims.SessionID) {
+			http.Error(w, "Session has been revoked", http.StatusUnauthorized)
+			return
+		}
+
 		// Add user info to context
 		ctx := context.WithValue(r.Context(), UserContextKey, claims)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	})
 }
 
+// client returns where r came from
+func (m *AuthMiddleware) client(r *http.Request) Client {
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+
+	if m.trustProxy {
+		// Earlier entries are whatever the client sent; the last one was
+		// added by the proxy
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			entries := strings.Split(forwarded, ",")
+			ip = strings.TrimSpace(entries[len(entries)-1])
+		}
+	}
+
+	return Client{UserAgent: r.UserAgent(), IP: ip}
+}
+
+// WithClient returns ctx recording that its request came from client
+func WithClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientContextKey, client)
+}
+
+// ClientFromContext returns where the request in ctx came from
+func ClientFromContext(ctx context.Context) Client {
+	client, _ := ctx.Value(clientContextKey).(Client)
+	return client
+}
+
 // GetUserFromContext extracts user claims from context
 func GetUserFromContext(ctx context.Context) (*Claims, bool) {
 	claims, ok := ctx.Value(UserContextKey).(*Claims)
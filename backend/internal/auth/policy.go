@@ -0,0 +1,46 @@
+package auth
+
+import "taskboard/internal/models"
+
+// CanModifyTask reports whether the authenticated user may update or delete a task.
+// Only the creator of a task is allowed to change it.
+func CanModifyTask(claims *Claims, task *models.Task) bool {
+	return claims != nil && task != nil && task.CreatedByID == claims.UserID
+}
+
+// CanManageSprint reports whether the authenticated user may edit a sprint or
+// change its scope. Only the creator of a sprint is allowed to.
+func CanManageSprint(claims *Claims, sprint *models.Sprint) bool {
+	return claims != nil && sprint != nil && sprint.CreatedByID == claims.UserID
+}
+
+// CanAttachToTask reports whether the authenticated user may upload files to
+// a task. Its creator and its assignee may.
+func CanAttachToTask(claims *Claims, task *models.Task) bool {
+	if claims == nil || task == nil {
+		return false
+	}
+	return task.CreatedByID == claims.UserID ||
+		(task.AssignedToID != nil && *task.AssignedToID == claims.UserID)
+}
+
+// CanDeleteAttachment reports whether the authenticated user may delete an
+// attachment. Its uploader and the task's creator may.
+func CanDeleteAttachment(claims *Claims, task *models.Task, attachment *models.Attachment) bool {
+	if claims == nil || attachment == nil {
+		return false
+	}
+	return attachment.UploaderID == claims.UserID || CanModifyTask(claims, task)
+}
+
+// CanSeeView reports whether the authenticated user may see and apply a
+// saved view. Its owner may, and everyone may once it is shared.
+func CanSeeView(claims *Claims, view *models.SavedView) bool {
+	return claims != nil && view != nil && (view.Shared || view.OwnerID == claims.UserID)
+}
+
+// CanModifyView reports whether the authenticated user may change or delete a
+// saved view. Only its owner may, even when it is shared.
+func CanModifyView(claims *Claims, view *models.SavedView) bool {
+	return claims != nil && view != nil && view.OwnerID == claims.UserID
+}
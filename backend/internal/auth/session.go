@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+
+	"taskboard/internal/cache"
+	"taskboard/internal/models"
+)
+
+// SessionLookup finds sessions by ID. repository.SessionStore satisfies it.
+type SessionLookup interface {
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+}
+
+// SessionChecker tells whether the session an access token was issued for is
+// still active. Answers are cached for SessionTTL, so anything that revokes a
+// session must delete cache.SessionKey for it.
+type SessionChecker struct {
+	sessions SessionLookup
+	cache    cache.Cache
+}
+
+// NewSessionChecker returns a checker that looks sessions up in sessions,
+// through c unless it is nil
+func NewSessionChecker(sessions SessionLookup, c cache.Cache) *SessionChecker {
+	return &SessionChecker{sessions: sessions, cache: c}
+}
+
+// Active reports whether sessionID exists and has not been revoked
+func (s *SessionChecker) Active(ctx context.Context, sessionID string) bool {
+	var active bool
+	if s.cache != nil {
+		if err := s.cache.Get(ctx, cache.SessionKey(sessionID), &active); err == nil {
+			return active
+		}
+	}
+
+	session, err := s.sessions.GetByID(ctx, sessionID)
+	if err != nil {
+		// Not cached: a database error should not end the session for good
+		return false
+	}
+	active = session.RevokedAt == nil
+
+	if s.cache != nil {
+		s.cache.Set(ctx, cache.SessionKey(sessionID), active, cache.SessionTTL)
+	}
+
+	return active
+}
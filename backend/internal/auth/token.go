@@ -0,0 +1,27 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// opaqueTokenBytes is the entropy of tokens handed out outside of JWTs
+const opaqueTokenBytes = 32
+
+// NewOpaqueToken returns a random, URL-safe secret token
+func NewOpaqueToken() (string, error) {
+	buf := make([]byte, opaqueTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// HashOpaqueToken returns the digest an opaque token is stored as, so a
+// leaked database does not leak usable tokens
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
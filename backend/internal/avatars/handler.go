@@ -0,0 +1,59 @@
+package avatars
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	"taskboard/internal/storage"
+)
+
+// Handler serves the thumbnails stored by Service.Upload. Avatars are public,
+// like the names they sit next to, so no credentials are required.
+type Handler struct {
+	service *Service
+}
+
+func NewHandler(service *Service) *Handler {
+	return &Handler{service: service}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, _, _, ok := Parse(r.URL.Path); !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	content, err := h.service.blobs.Open(r.Context(), strings.TrimPrefix(r.URL.Path, "/"))
+	if errors.Is(err, storage.ErrNotFound) {
+		http.NotFound(w, r)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to open avatar %s: %v", r.URL.Path, err)
+		http.Error(w, "failed to read avatar", http.StatusInternalServerError)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	// A new upload gets a new path, so a thumbnail never changes
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	if _, err := io.Copy(w, content); err != nil {
+		log.Printf("failed to send avatar %s: %v", r.URL.Path, err)
+	}
+}
@@ -0,0 +1,134 @@
+// Package avatars turns uploaded profile pictures into square thumbnails
+// served from this server, so clients never load images from user-supplied
+// URLs.
+package avatars
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Sizes are the thumbnail edge lengths generated for every avatar, in pixels
+var Sizes = []int{32, 64, 128, 256}
+
+// DefaultSize is the thumbnail User.avatar points at
+const DefaultSize = 128
+
+// maxDimension bounds decoded images so a small, highly compressed file cannot
+// expand into gigabytes of pixels
+const maxDimension = 4096
+
+var (
+	ErrTooLarge = errors.New("image is too large")
+	ErrNotImage = errors.New("file is not a PNG, JPEG or GIF image")
+)
+
+// Decode reads a PNG, JPEG or GIF image of at most maxBytes. Only the first
+// frame of an animated GIF is kept.
+func Decode(r io.Reader, maxBytes int64) (image.Image, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image: %w", err)
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, ErrTooLarge
+	}
+
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+	if format != "png" && format != "jpeg" && format != "gif" {
+		return nil, ErrNotImage
+	}
+	if config.Width > maxDimension || config.Height > maxDimension {
+		return nil, fmt.Errorf("%w: images may be at most %dx%d pixels", ErrTooLarge, maxDimension, maxDimension)
+	}
+	if config.Width == 0 || config.Height == 0 {
+		return nil, ErrNotImage
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrNotImage
+	}
+
+	return img, nil
+}
+
+// Thumbnail crops the centre square of src and scales it to size x size.
+// Each output pixel averages the source pixels it covers, which keeps
+// downscaled photos smooth without anything beyond the standard library.
+func Thumbnail(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	edge := bounds.Dx()
+	if bounds.Dy() < edge {
+		edge = bounds.Dy()
+	}
+	crop := image.Rect(0, 0, edge, edge).Add(image.Pt(
+		bounds.Min.X+(bounds.Dx()-edge)/2,
+		bounds.Min.Y+(bounds.Dy()-edge)/2,
+	))
+
+	// Work on premultiplied RGBA so transparent pixels do not darken edges
+	square := image.NewRGBA(image.Rect(0, 0, edge, edge))
+	draw.Draw(square, square.Bounds(), src, crop.Min, draw.Src)
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		y0, y1 := span(dy, size, edge)
+		for dx := 0; dx < size; dx++ {
+			x0, x1 := span(dx, size, edge)
+
+			var r, g, b, a uint64
+			for y := y0; y < y1; y++ {
+				row := square.Pix[y*square.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += uint64(p[0])
+					g += uint64(p[1])
+					b += uint64(p[2])
+					a += uint64(p[3])
+				}
+			}
+
+			out := dst.Pix[dy*dst.Stride+dx*4 : dy*dst.Stride+dx*4+4]
+			if a == 0 {
+				continue
+			}
+			n := uint64((y1 - y0) * (x1 - x0))
+			// Un-premultiply the averages for the NRGBA output
+			out[0] = uint8((r*255 + a/2) / a)
+			out[1] = uint8((g*255 + a/2) / a)
+			out[2] = uint8((b*255 + a/2) / a)
+			out[3] = uint8((a + n/2) / n)
+		}
+	}
+
+	return dst
+}
+
+// span returns the source range [from, to) covered by output index i when
+// scaling edge source pixels to size output pixels. It always covers at least
+// one pixel, so upscaling repeats pixels.
+func span(i, size, edge int) (int, int) {
+	from := i * edge / size
+	to := (i + 1) * edge / size
+	if to <= from {
+		to = from + 1
+	}
+	return from, to
+}
+
+// EncodePNG writes img as a PNG
+func EncodePNG(w io.Writer, img image.Image) error {
+	encoder := png.Encoder{CompressionLevel: png.BestCompression}
+	return encoder.Encode(w, img)
+}
@@ -0,0 +1,157 @@
+package avatars
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"taskboard/internal/storage"
+)
+
+// BasePath is where thumbnails are served, as
+// BasePath + "<user id>/<version>/<size>.png"
+const BasePath = "/avatars/"
+
+// Service stores avatar thumbnails in a blob store
+type Service struct {
+	blobs    storage.Blob
+	maxBytes int64
+}
+
+func NewService(blobs storage.Blob, maxBytes int64) *Service {
+	return &Service{blobs: blobs, maxBytes: maxBytes}
+}
+
+// MaxBytes returns the largest accepted upload
+func (s *Service) MaxBytes() int64 {
+	return s.maxBytes
+}
+
+// Upload decodes an image, stores a thumbnail for each of Sizes and returns
+// the URL of the DefaultSize one. Every upload gets a new version in its path
+// so thumbnails can be cached forever.
+func (s *Service) Upload(ctx context.Context, userID string, content io.Reader) (string, error) {
+	img, err := Decode(content, s.maxBytes)
+	if err != nil {
+		return "", err
+	}
+
+	version := uuid.New().String()
+	var stored []string
+	for _, size := range Sizes {
+		var buf bytes.Buffer
+		if err := EncodePNG(&buf, Thumbnail(img, size)); err != nil {
+			s.deleteBlobs(ctx, stored)
+			return "", fmt.Errorf("failed to encode thumbnail: %w", err)
+		}
+
+		key := blobKey(userID, version, size)
+		if err := s.blobs.Put(ctx, key, &buf, int64(buf.Len()), "image/png"); err != nil {
+			s.deleteBlobs(ctx, stored)
+			return "", err
+		}
+		stored = append(stored, key)
+	}
+
+	return path(userID, version, DefaultSize), nil
+}
+
+// Delete removes the thumbnails behind an avatar URL made by Upload. Other
+// values are ignored.
+func (s *Service) Delete(ctx context.Context, avatar string) {
+	userID, version, _, ok := Parse(avatar)
+	if !ok {
+		return
+	}
+
+	keys := make([]string, len(Sizes))
+	for i, size := range Sizes {
+		keys[i] = blobKey(userID, version, size)
+	}
+	s.deleteBlobs(ctx, keys)
+}
+
+// deleteBlobs removes orphaned thumbnails. Failures only leave garbage
+// behind, so they are logged rather than returned.
+func (s *Service) deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := s.blobs.Delete(ctx, key); err != nil {
+			log.Printf("failed to delete blob %s: %v", key, err)
+		}
+	}
+}
+
+// URL returns the link to the thumbnail closest to size for an avatar URL
+// made by Upload: the smallest one at least that large, or the largest one.
+// ok is false for anything else, such as a legacy external URL.
+func URL(avatar string, size int) (string, bool) {
+	userID, version, _, ok := Parse(avatar)
+	if !ok {
+		return "", false
+	}
+
+	chosen := Sizes[len(Sizes)-1]
+	for _, s := range Sizes {
+		if s >= size {
+			chosen = s
+			break
+		}
+	}
+
+	return path(userID, version, chosen), true
+}
+
+// OwnedBy reports whether avatar is a thumbnail URL uploaded by userID
+func OwnedBy(avatar, userID string) bool {
+	owner, _, _, ok := Parse(avatar)
+	return ok && owner == userID
+}
+
+// Parse splits a thumbnail URL into its parts. Only URLs with a generated
+// size are accepted.
+func Parse(avatar string) (userID, version string, size int, ok bool) {
+	rest := strings.TrimPrefix(avatar, BasePath)
+	if rest == avatar {
+		return "", "", 0, false
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+		return "", "", 0, false
+	}
+	if _, err := uuid.Parse(parts[0]); err != nil {
+		return "", "", 0, false
+	}
+	if _, err := uuid.Parse(parts[1]); err != nil {
+		return "", "", 0, false
+	}
+
+	size, err := strconv.Atoi(strings.TrimSuffix(parts[2], ".png"))
+	if err != nil || !strings.HasSuffix(parts[2], ".png") || !isSize(size) {
+		return "", "", 0, false
+	}
+
+	return parts[0], parts[1], size, true
+}
+
+func isSize(size int) bool {
+	for _, s := range Sizes {
+		if s == size {
+			return true
+		}
+	}
+	return false
+}
+
+func path(userID, version string, size int) string {
+	return fmt.Sprintf("%s%s/%s/%d.png", BasePath, userID, version, size)
+}
+
+func blobKey(userID, version string, size int) string {
+	return strings.TrimPrefix(path(userID, version, size), "/")
+}
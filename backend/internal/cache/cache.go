@@ -0,0 +1,25 @@
+package cache
+
+import (
+	"context"
+	"time"
+)
+
+// Cache is the caching the GraphQL resolvers depend on. RedisCache implements
+// it; MemoryCache implements it in-process for tests. Values are stored as
+// JSON, and Get returns ErrCacheMiss for missing or expired keys.
+type Cache interface {
+	Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error
+	Get(ctx context.Context, key string, dest interface{}) error
+	Delete(ctx context.Context, keys ...string) error
+	// DeletePattern deletes the keys matching a Redis glob pattern
+	DeletePattern(ctx context.Context, pattern string) error
+	// Increment atomically increments a counter, which expires expiration
+	// after its first increment, and returns its new value
+	Increment(ctx context.Context, key string, expiration time.Duration) (int64, error)
+}
+
+var (
+	_ Cache = (*RedisCache)(nil)
+	_ Cache = (*MemoryCache)(nil)
+)
@@ -0,0 +1,137 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// MemoryCache is an in-process Cache with the same semantics as RedisCache
+type MemoryCache struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+}
+
+type memoryEntry struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{entries: make(map[string]memoryEntry)}
+}
+
+// Set stores a value with expiration; zero means it never expires
+func (c *MemoryCache) Set(ctx context.Context, key string, value interface{}, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	entry := memoryEntry{data: data}
+	if expiration > 0 {
+		entry.expiresAt = time.Now().Add(expiration)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	return nil
+}
+
+// Get retrieves and unmarshals a value
+func (c *MemoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	if ok && c.expired(entry) {
+		delete(c.entries, key)
+		ok = false
+	}
+	c.mu.Unlock()
+
+	if !ok {
+		return ErrCacheMiss
+	}
+
+	if err := json.Unmarshal(entry.data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes keys
+func (c *MemoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+// DeletePattern deletes all keys matching a pattern. Cache keys never contain
+// "/", so path.Match agrees with Redis glob matching on them.
+func (c *MemoryCache) DeletePattern(ctx context.Context, pattern string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		matched, err := path.Match(pattern, key)
+		if err != nil {
+			return err
+		}
+		if matched {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// Increment atomically increments a counter, which expires expiration after
+// its first increment
+func (c *MemoryCache) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var count int64
+	entry, ok := c.entries[key]
+	if ok && !c.expired(entry) {
+		if err := json.Unmarshal(entry.data, &count); err != nil {
+			return 0, fmt.Errorf("value is not a counter: %w", err)
+		}
+	} else {
+		entry = memoryEntry{}
+		if expiration > 0 {
+			entry.expiresAt = time.Now().Add(expiration)
+		}
+	}
+
+	count++
+	entry.data = []byte(strconv.FormatInt(count, 10))
+	c.entries[key] = entry
+	return count, nil
+}
+
+// Keys returns the keys currently stored, for assertions in tests
+func (c *MemoryCache) Keys() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var keys []string
+	for key, entry := range c.entries {
+		if !c.expired(entry) {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func (c *MemoryCache) expired(entry memoryEntry) bool {
+	return !entry.expiresAt.IsZero() && !time.Now().Before(entry.expiresAt)
+}
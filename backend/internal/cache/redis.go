@@ -107,9 +107,19 @@ func (c *RedisCache) SetNX(ctx context.Context, key string, value interface{}, e
 	return c.client.SetNX(ctx, key, data, expiration).Result()
 }
 
-// Increment atomically increments a key
-func (c *RedisCache) Increment(ctx context.Context, key string) (int64, error) {
-	return c.client.Incr(ctx, key).Result()
+// Increment atomically increments a counter, which expires expiration after
+// its first increment
+func (c *RedisCache) Increment(ctx context.Context, key string, expiration time.Duration) (int64, error) {
+	var incr *redis.IntCmd
+	_, err := c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		pipe.ExpireNX(ctx, key, expiration)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return incr.Val(), nil
 }
 
 // Close closes the Redis connection
@@ -134,4 +144,41 @@ func UserTasksKey(userID string) string {
 	return fmt.Sprintf("user:%s:tasks", userID)
 }
 
+func ExportKey(token string) string {
+	return fmt.Sprintf("export:%s", token)
+}
+
+func PasswordResetRequestsKey(kind, value string) string {
+	return fmt.Sprintf("password-reset:%s:%s", kind, value)
+}
+
+// SessionTTL bounds how long whether a session is active is cached. Revoking
+// a session deletes its key, so this only limits how long a miss in doing so
+// goes unnoticed.
+const SessionTTL = 15 * time.Minute
+
+func SessionKey(id string) string {
+	return fmt.Sprintf("session:%s", id)
+}
+
+// MarkdownTTL is how long rendered descriptions are kept. Keys are derived
+// from the source, so edits never make them stale.
+const MarkdownTTL = 24 * time.Hour
+
+func MarkdownKey(hash string) string {
+	return fmt.Sprintf("markdown:%s", hash)
+}
+
+// StatsTTL bounds how stale dashboard analytics may get. Stats keys live under
+// "tasks:" so task writes also clear them.
+const StatsTTL = 30 * time.Second
+
+func StatsKey(name string, params ...string) string {
+	key := "tasks:stats:" + name
+	for _, param := range params {
+		key += ":" + param
+	}
+	return key
+}
+
 var ErrCacheMiss = fmt.Errorf("cache miss")
\ No newline at end of file
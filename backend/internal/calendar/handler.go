@@ -0,0 +1,81 @@
+package calendar
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/repository"
+)
+
+// BasePath is where feeds are served, as BasePath + token + ".ics"
+const BasePath = "/calendar/"
+
+// FeedPath returns the path of the feed for a token
+func FeedPath(token string) string {
+	return BasePath + token + ".ics"
+}
+
+// Handler serves per-user feeds of assigned tasks' due dates. Calendar
+// clients cannot send an Authorization header, so the secret token in the
+// path is the only credential.
+type Handler struct {
+	userRepo repository.UserStore
+	taskRepo repository.TaskStore
+}
+
+func NewHandler(userRepo repository.UserStore, taskRepo repository.TaskStore) *Handler {
+	return &Handler{userRepo: userRepo, taskRepo: taskRepo}
+}
+
+// ServeHTTP serves /calendar/{token}.ics. ?component=VTODO publishes tasks as
+// to-dos instead of events.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := strings.TrimPrefix(r.URL.Path, BasePath)
+	token := strings.TrimSuffix(name, ".ics")
+	if token == "" || token == name || strings.Contains(token, "/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	component := ComponentEvent
+	if strings.EqualFold(r.URL.Query().Get("component"), ComponentTodo) {
+		component = ComponentTodo
+	}
+
+	user, err := h.userRepo.GetByCalendarTokenHash(r.Context(), auth.HashOpaqueToken(token))
+	if err != nil {
+		http.Error(w, "failed to load calendar", http.StatusInternalServerError)
+		return
+	}
+	if user == nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	tasks, err := h.taskRepo.List(r.Context(), map[string]interface{}{"assigned_to_id": user.ID})
+	if err != nil {
+		http.Error(w, "failed to load calendar", http.StatusInternalServerError)
+		return
+	}
+
+	var body bytes.Buffer
+	if err := Write(&body, "TaskBoard - "+user.Name, component, tasks, time.Now()); err != nil {
+		log.Printf("failed to encode calendar for user %s: %v", user.ID, err)
+		http.Error(w, "failed to encode calendar", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	w.Write(body.Bytes())
+}
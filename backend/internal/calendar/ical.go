@@ -0,0 +1,157 @@
+// Package calendar publishes task due dates as RFC 5545 iCalendar feeds.
+package calendar
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskboard/internal/models"
+)
+
+// Components a task can be published as
+const (
+	ComponentEvent = "VEVENT"
+	ComponentTodo  = "VTODO"
+)
+
+const (
+	prodID       = "-//TaskBoard//Task Due Dates//EN"
+	uidDomain    = "taskboard"
+	dateTimeForm = "20060102T150405Z"
+	// RFC 5545 limits content lines to 75 octets, excluding the line break
+	maxLineOctets = 75
+)
+
+// UID identifies a task's entry across feed refreshes, so clients update it
+// in place instead of adding a duplicate
+func UID(taskID string) string {
+	return fmt.Sprintf("task-%s@%s", taskID, uidDomain)
+}
+
+// Write encodes a VCALENDAR with one component per task that has a due date.
+// Events are zero-length and start at the due date; to-dos carry it as DUE.
+func Write(w io.Writer, name, component string, tasks []*models.Task, now time.Time) error {
+	e := &encoder{w: w}
+
+	e.line("BEGIN", "VCALENDAR")
+	e.line("VERSION", "2.0")
+	e.line("PRODID", prodID)
+	e.line("CALSCALE", "GREGORIAN")
+	e.line("METHOD", "PUBLISH")
+	e.line("X-WR-CALNAME", escapeText(name))
+
+	for _, task := range tasks {
+		if task.DueDate == nil {
+			continue
+		}
+
+		e.line("BEGIN", component)
+		e.line("UID", UID(task.ID))
+		e.line("DTSTAMP", formatTime(now))
+		e.line("CREATED", formatTime(task.CreatedAt))
+		e.line("LAST-MODIFIED", formatTime(task.UpdatedAt))
+		e.line("SUMMARY", escapeText(task.Title))
+		if task.Description != nil && *task.Description != "" {
+			e.line("DESCRIPTION", escapeText(*task.Description))
+		}
+		e.line("PRIORITY", fmt.Sprint(icalPriority(task.Priority)))
+
+		if component == ComponentTodo {
+			e.line("DUE", formatTime(*task.DueDate))
+			e.line("STATUS", todoStatus(task.Status))
+			if task.CompletedAt != nil {
+				e.line("COMPLETED", formatTime(*task.CompletedAt))
+			}
+		} else {
+			// DTEND must be later than DTSTART. Without one, an event
+			// starting at a date-time ends at that same instant.
+			e.line("DTSTART", formatTime(*task.DueDate))
+			e.line("TRANSP", "TRANSPARENT")
+		}
+
+		e.line("END", component)
+	}
+
+	e.line("END", "VCALENDAR")
+
+	return e.err
+}
+
+// encoder writes folded content lines, remembering the first error
+type encoder struct {
+	w   io.Writer
+	err error
+}
+
+func (e *encoder) line(name, value string) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = io.WriteString(e.w, fold(name+":"+value))
+}
+
+// fold splits a content line into chunks of at most 75 octets without
+// breaking UTF-8 sequences. Continuation lines start with a space.
+func fold(line string) string {
+	var b strings.Builder
+	limit := maxLineOctets
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// The leading space counts towards the next line's length
+		limit = maxLineOctets - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	return b.String()
+}
+
+// escapeText escapes a TEXT property value
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeForm)
+}
+
+// icalPriority maps priorities onto RFC 5545's 1 (highest) to 9 (lowest)
+func icalPriority(priority string) int {
+	switch priority {
+	case "URGENT":
+		return 1
+	case "HIGH":
+		return 3
+	case "LOW":
+		return 9
+	default:
+		return 5
+	}
+}
+
+func todoStatus(status string) string {
+	switch status {
+	case "DONE":
+		return "COMPLETED"
+	case "IN_PROGRESS", "REVIEW":
+		return "IN-PROCESS"
+	default:
+		return "NEEDS-ACTION"
+	}
+}
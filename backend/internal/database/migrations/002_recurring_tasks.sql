@@ -0,0 +1,11 @@
+-- Recurring tasks: every occurrence of a series shares series_id and carries
+-- the RRULE it was generated from
+ALTER TABLE tasks ADD COLUMN recurrence_rule TEXT;
+ALTER TABLE tasks ADD COLUMN series_id UUID;
+ALTER TABLE tasks ADD COLUMN occurrence INTEGER NOT NULL DEFAULT 1;
+
+-- One task per position in a series, so generating the next occurrence is idempotent
+CREATE UNIQUE INDEX idx_tasks_series_occurrence ON tasks(series_id, occurrence);
+
+CREATE INDEX idx_tasks_recurring_due ON tasks(due_date)
+    WHERE recurrence_rule IS NOT NULL;
@@ -0,0 +1,56 @@
+-- Estimates on tasks
+ALTER TABLE tasks ADD COLUMN story_points INTEGER CHECK (story_points >= 0);
+ALTER TABLE tasks ADD COLUMN estimated_hours NUMERIC(8, 2) CHECK (estimated_hours >= 0);
+
+-- When a task reached DONE, maintained by trigger so every write path records it
+ALTER TABLE tasks ADD COLUMN completed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE tasks SET completed_at = updated_at WHERE status = 'DONE';
+
+CREATE OR REPLACE FUNCTION set_task_completed_at()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'DONE' THEN
+        IF TG_OP = 'INSERT' OR OLD.status <> 'DONE' THEN
+            NEW.completed_at = NOW();
+        END IF;
+    ELSE
+        NEW.completed_at = NULL;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER set_tasks_completed_at BEFORE INSERT OR UPDATE OF status ON tasks
+    FOR EACH ROW EXECUTE FUNCTION set_task_completed_at();
+
+-- Sprints
+CREATE TABLE IF NOT EXISTS sprints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(255) NOT NULL,
+    goal TEXT,
+    start_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    end_date TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_by_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_sprint_dates CHECK (end_date > start_date)
+);
+
+CREATE INDEX idx_sprints_start_date ON sprints(start_date DESC);
+
+CREATE TRIGGER update_sprints_updated_at BEFORE UPDATE ON sprints
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
+
+-- Sprint membership history. Removing a task closes its row instead of deleting
+-- it, so the burndown can replay scope changes; re-adding opens a new row.
+CREATE TABLE IF NOT EXISTS sprint_tasks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    sprint_id UUID NOT NULL REFERENCES sprints(id) ON DELETE CASCADE,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    added_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    removed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE UNIQUE INDEX idx_sprint_tasks_current ON sprint_tasks(sprint_id, task_id) WHERE removed_at IS NULL;
+CREATE INDEX idx_sprint_tasks_task ON sprint_tasks(task_id);
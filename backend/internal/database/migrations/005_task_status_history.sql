@@ -0,0 +1,33 @@
+-- Every status a task has been in and when it entered it. Used for cycle time analytics.
+CREATE TABLE IF NOT EXISTS task_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    status VARCHAR(50) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_task_status_history_task ON task_status_history(task_id, changed_at);
+
+-- Seed history for existing tasks from what is known about them
+INSERT INTO task_status_history (task_id, status, changed_at)
+SELECT id, 'TODO', created_at FROM tasks;
+
+INSERT INTO task_status_history (task_id, status, changed_at)
+SELECT id, status, COALESCE(completed_at, updated_at) FROM tasks WHERE status <> 'TODO';
+
+CREATE OR REPLACE FUNCTION record_task_status()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' OR NEW.status IS DISTINCT FROM OLD.status THEN
+        INSERT INTO task_status_history (task_id, status) VALUES (NEW.id, NEW.status);
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER record_tasks_status AFTER INSERT OR UPDATE OF status ON tasks
+    FOR EACH ROW EXECUTE FUNCTION record_task_status();
+
+-- Supports overdue and throughput queries
+CREATE INDEX idx_tasks_due_date ON tasks(due_date) WHERE status <> 'DONE';
+CREATE INDEX idx_tasks_completed_at ON tasks(completed_at) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,22 @@
+-- Links records imported from other tools (Trello, Jira) to the rows created
+-- for them, so running an import again does not create duplicates
+CREATE TABLE IF NOT EXISTS import_mappings (
+    source VARCHAR(50) NOT NULL,
+    kind VARCHAR(20) NOT NULL CHECK (kind IN ('task', 'user')),
+    external_id TEXT NOT NULL,
+    internal_id UUID NOT NULL,
+    imported_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (source, kind, external_id)
+);
+
+-- Drop mappings whose task has since been deleted
+CREATE OR REPLACE FUNCTION delete_task_import_mapping()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM import_mappings WHERE kind = 'task' AND internal_id = OLD.id;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER delete_tasks_import_mapping AFTER DELETE ON tasks
+    FOR EACH ROW EXECUTE FUNCTION delete_task_import_mapping();
@@ -0,0 +1,6 @@
+-- Secret token for the per-user iCalendar feed. Only its SHA-256 digest is
+-- stored; rotating it invalidates existing subscriptions.
+ALTER TABLE users ADD COLUMN calendar_token_hash VARCHAR(64);
+
+CREATE UNIQUE INDEX idx_users_calendar_token ON users(calendar_token_hash)
+    WHERE calendar_token_hash IS NOT NULL;
@@ -0,0 +1,4 @@
+-- Avatars are now uploaded and served by the server. Arbitrary URLs stored by
+-- the old updateProfile mutation let a profile make other users' browsers
+-- fetch any address, so they are dropped.
+UPDATE users SET avatar = NULL WHERE avatar IS NOT NULL AND avatar NOT LIKE '/avatars/%';
@@ -0,0 +1,3 @@
+-- Archived tasks are hidden from task lists unless asked for. Unlike deleted
+-- tasks they are never purged.
+ALTER TABLE tasks ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
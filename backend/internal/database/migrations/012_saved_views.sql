@@ -0,0 +1,19 @@
+-- Named task filters. query holds the filter and sort as versioned JSON (see
+-- internal/views). Shared views are visible to every user; only the owner may
+-- change them.
+CREATE TABLE IF NOT EXISTS saved_views (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    owner_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    query JSONB NOT NULL,
+    shared BOOLEAN NOT NULL DEFAULT FALSE,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_saved_views_owner_name ON saved_views(owner_id, lower(name));
+CREATE INDEX idx_saved_views_shared ON saved_views(shared) WHERE shared;
+
+CREATE TRIGGER update_saved_views_updated_at BEFORE UPDATE ON saved_views
+    FOR EACH ROW EXECUTE FUNCTION update_updated_at_column();
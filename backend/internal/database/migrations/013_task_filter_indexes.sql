@@ -0,0 +1,10 @@
+-- Indexes for the task filters compiled by TaskRepository.List. Trigram
+-- matching lets titleContains use an index despite the leading wildcard.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX idx_tasks_title_trgm ON tasks USING GIN (title gin_trgm_ops);
+-- idx_tasks_due_date only covers open tasks, which dueBefore and dueAfter
+-- do not restrict themselves to
+CREATE INDEX idx_tasks_due_date_all ON tasks(due_date);
+CREATE INDEX idx_tasks_updated_at ON tasks(updated_at DESC);
+CREATE INDEX idx_tasks_unassigned ON tasks(created_at DESC) WHERE assigned_to_id IS NULL;
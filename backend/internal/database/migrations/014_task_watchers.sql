@@ -0,0 +1,37 @@
+-- Users following a task, who are told when it changes. Creators and
+-- assignees start watching automatically but may stop like anyone else.
+CREATE TABLE IF NOT EXISTS task_watchers (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_watchers_user ON task_watchers(user_id);
+
+-- Existing creators and assignees watch their tasks
+INSERT INTO task_watchers (task_id, user_id, created_at)
+SELECT id, created_by_id, created_at FROM tasks
+ON CONFLICT DO NOTHING;
+
+INSERT INTO task_watchers (task_id, user_id)
+SELECT id, assigned_to_id FROM tasks WHERE assigned_to_id IS NOT NULL
+ON CONFLICT DO NOTHING;
+
+CREATE OR REPLACE FUNCTION watch_task_participants()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'INSERT' THEN
+        INSERT INTO task_watchers (task_id, user_id) VALUES (NEW.id, NEW.created_by_id)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    IF NEW.assigned_to_id IS NOT NULL AND (TG_OP = 'INSERT' OR NEW.assigned_to_id IS DISTINCT FROM OLD.assigned_to_id) THEN
+        INSERT INTO task_watchers (task_id, user_id) VALUES (NEW.id, NEW.assigned_to_id)
+        ON CONFLICT DO NOTHING;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER watch_tasks_participants AFTER INSERT OR UPDATE OF assigned_to_id ON tasks
+    FOR EACH ROW EXECUTE FUNCTION watch_task_participants();
@@ -0,0 +1,16 @@
+-- Users mentioned in a task's description, as "@handle" or "@email". A row
+-- lives as long as the mention stays in the description, so created_at is
+-- when it was first added.
+CREATE TABLE IF NOT EXISTS task_mentions (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    mentioned_by_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (task_id, user_id)
+);
+
+CREATE INDEX idx_task_mentions_user ON task_mentions(user_id, created_at DESC);
+
+-- Mentions are resolved by email address or handle, the part before the "@"
+CREATE INDEX idx_users_email_lower ON users(lower(email));
+CREATE INDEX idx_users_handle ON users(lower(split_part(email, '@', 1)));
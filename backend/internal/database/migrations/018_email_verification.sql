@@ -0,0 +1,4 @@
+-- Set once a user follows the verification link mailed to their address.
+-- Accounts created before verification existed start out unverified and can
+-- ask for a link with resendVerification.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP WITH TIME ZONE;
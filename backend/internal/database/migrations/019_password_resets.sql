@@ -0,0 +1,14 @@
+-- Password reset tokens, stored as SHA-256 hashes. A token is short-lived and
+-- works once; redeeming one spends every other token of the same user.
+CREATE TABLE IF NOT EXISTS password_resets (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    used_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_password_resets_hash ON password_resets(token_hash);
+CREATE INDEX idx_password_resets_user ON password_resets(user_id) WHERE used_at IS NULL;
+CREATE INDEX idx_password_resets_expires_at ON password_resets(expires_at);
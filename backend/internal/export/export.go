@@ -0,0 +1,162 @@
+// Package export writes tasks as CSV or JSON Lines for spreadsheets and other tools.
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"taskboard/internal/models"
+)
+
+type Format string
+
+const (
+	FormatCSV   Format = "CSV"
+	FormatJSONL Format = "JSONL"
+)
+
+// ParseFormat accepts a format name in any case
+func ParseFormat(s string) (Format, error) {
+	switch format := Format(strings.ToUpper(s)); format {
+	case FormatCSV, FormatJSONL:
+		return format, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q", s)
+}
+
+// ContentType is the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Extension is the file extension of the format, without the dot
+func (f Format) Extension() string {
+	if f == FormatJSONL {
+		return "jsonl"
+	}
+	return "csv"
+}
+
+// DateFormat is how dates are written in both formats
+const DateFormat = time.RFC3339
+
+var csvHeader = []string{
+	"id", "title", "description", "status", "priority",
+	"creator_name", "creator_email", "assignee_name", "assignee_email",
+	"due_date", "created_at", "updated_at", "completed_at",
+}
+
+// Writer encodes tasks one at a time
+type Writer interface {
+	Write(task *models.TaskExport) error
+	// Flush writes any buffered data to the underlying writer
+	Flush() error
+}
+
+// NewWriter returns a Writer for format. CSV output starts with a header row.
+func NewWriter(w io.Writer, format Format) Writer {
+	if format == FormatJSONL {
+		buffered := bufio.NewWriter(w)
+		return &jsonLinesWriter{buffered: buffered, encoder: json.NewEncoder(buffered)}
+	}
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+type csvWriter struct {
+	writer      *csv.Writer
+	wroteHeader bool
+}
+
+func (c *csvWriter) Write(task *models.TaskExport) error {
+	if !c.wroteHeader {
+		if err := c.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	return c.writer.Write([]string{
+		task.ID, cell(task.Title), cell(stringValue(task.Description)), task.Status, task.Priority,
+		cell(task.CreatorName), cell(task.CreatorEmail), cell(stringValue(task.AssigneeName)), cell(stringValue(task.AssigneeEmail)),
+		formatDate(task.DueDate), formatDate(&task.CreatedAt), formatDate(&task.UpdatedAt), formatDate(task.CompletedAt),
+	})
+}
+
+// cell keeps spreadsheets from evaluating text users entered as a formula, by
+// prefixing text that starts like one with a quote
+func cell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
+
+func (c *csvWriter) Flush() error {
+	if !c.wroteHeader {
+		if err := c.writer.Write(csvHeader); err != nil {
+			return err
+		}
+		c.wroteHeader = true
+	}
+
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+// jsonLine fixes the date format independently of the model's JSON encoding
+type jsonLine struct {
+	*models.TaskExport
+	DueDate     *string `json:"due_date"`
+	CreatedAt   string  `json:"created_at"`
+	UpdatedAt   string  `json:"updated_at"`
+	CompletedAt *string `json:"completed_at"`
+}
+
+type jsonLinesWriter struct {
+	buffered *bufio.Writer
+	encoder  *json.Encoder
+}
+
+func (j *jsonLinesWriter) Write(task *models.TaskExport) error {
+	return j.encoder.Encode(jsonLine{
+		TaskExport:  task,
+		DueDate:     nullableDate(task.DueDate),
+		CreatedAt:   formatDate(&task.CreatedAt),
+		UpdatedAt:   formatDate(&task.UpdatedAt),
+		CompletedAt: nullableDate(task.CompletedAt),
+	})
+}
+
+func (j *jsonLinesWriter) Flush() error {
+	return j.buffered.Flush()
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(DateFormat)
+}
+
+func nullableDate(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := formatDate(t)
+	return &formatted
+}
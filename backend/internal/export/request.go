@@ -0,0 +1,60 @@
+package export
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"taskboard/internal/cache"
+	"taskboard/internal/views"
+)
+
+// RequestTTL is how long a prepared export can be downloaded
+const RequestTTL = 10 * time.Minute
+
+var ErrRequestNotFound = errors.New("export not found or expired")
+
+// Request is an export prepared for later download, e.g. from a GraphQL
+// mutation that cannot stream the file itself. The random token identifying
+// it is the only credential needed to download it.
+type Request struct {
+	UserID string       `json:"user_id"`
+	Format Format       `json:"format"`
+	Filter views.Filter `json:"filter"`
+}
+
+// Save stores req and returns its token and expiry
+func Save(ctx context.Context, c cache.Cache, req *Request) (string, time.Time, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate export token: %w", err)
+	}
+	token := hex.EncodeToString(buf)
+
+	if err := c.Set(ctx, cache.ExportKey(token), req, RequestTTL); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to save export: %w", err)
+	}
+
+	return token, time.Now().Add(RequestTTL), nil
+}
+
+// Load returns the export stored under token
+func Load(ctx context.Context, c cache.Cache, token string) (*Request, error) {
+	var req Request
+	if err := c.Get(ctx, cache.ExportKey(token), &req); err != nil {
+		if errors.Is(err, cache.ErrCacheMiss) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to load export: %w", err)
+	}
+
+	return &req, nil
+}
+
+// TaskFilter converts the stored filter for TaskRepository
+func (r *Request) TaskFilter() map[string]interface{} {
+	return r.Filter.Map()
+}
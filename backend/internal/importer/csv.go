@@ -0,0 +1,270 @@
+// Package importer turns tasks kept in other tools into taskboard tasks.
+package importer
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskboard/internal/models"
+)
+
+// Fields a CSV column can be mapped to
+const (
+	FieldTitle         = "title"
+	FieldDescription   = "description"
+	FieldStatus        = "status"
+	FieldPriority      = "priority"
+	FieldAssigneeEmail = "assignee_email"
+	FieldDueDate       = "due_date"
+)
+
+var Fields = []string{FieldTitle, FieldDescription, FieldStatus, FieldPriority, FieldAssigneeEmail, FieldDueDate}
+
+// maxTitleLength is the length of the title column, in characters
+const maxTitleLength = 500
+
+var (
+	statuses   = []string{"TODO", "IN_PROGRESS", "REVIEW", "DONE"}
+	priorities = []string{"LOW", "MEDIUM", "HIGH", "URGENT"}
+
+	// Accepted due date layouts, tried in order
+	dateLayouts = []string{time.RFC3339, "2006-01-02 15:04", "2006-01-02"}
+)
+
+// Mapping maps a field to the header of the CSV column holding it, e.g.
+// {"title": "Summary"}. Unmapped fields are read from a column named like the
+// field, if there is one. Headers are matched case-insensitively.
+type Mapping map[string]string
+
+// ResolveEmails returns the IDs of the users with the given emails, keyed by
+// lowercased email. Unknown emails are left out.
+type ResolveEmails func(ctx context.Context, emails []string) (map[string]string, error)
+
+// RowError is a problem with one row. Rows are numbered as in a spreadsheet,
+// with the header on row 1.
+type RowError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// Report summarizes an import
+type Report struct {
+	Rows     int        `json:"rows"`
+	Imported int        `json:"imported"`
+	DryRun   bool       `json:"dry_run"`
+	Errors   []RowError `json:"errors"`
+}
+
+// ParseCSV reads and validates every row of a CSV file, creating tasks owned
+// by creatorID. The returned report lists every row error; the tasks are only
+// meant to be inserted when there are none.
+func ParseCSV(ctx context.Context, r io.Reader, mapping Mapping, creatorID string, resolve ResolveEmails) ([]*models.Task, *Report, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil, errors.New("CSV file is empty")
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns, err := resolveColumns(header, mapping)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := &Report{Errors: []RowError{}}
+	var tasks []*models.Task
+	// Row number and assignee email of every task, checked once all emails are known
+	var rows []int
+	var assignees []string
+
+	row := 1
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		row++
+		report.Rows++
+
+		if err != nil {
+			report.Errors = append(report.Errors, RowError{Row: row, Message: err.Error()})
+			continue
+		}
+
+		value := func(field string) string {
+			if index, ok := columns[field]; ok && index < len(record) {
+				return strings.TrimSpace(record[index])
+			}
+			return ""
+		}
+
+		task, rowErrors := parseRow(row, value, creatorID)
+		if len(rowErrors) > 0 {
+			report.Errors = append(report.Errors, rowErrors...)
+			continue
+		}
+
+		tasks = append(tasks, task)
+		rows = append(rows, row)
+		assignees = append(assignees, strings.ToLower(value(FieldAssigneeEmail)))
+	}
+
+	var emails []string
+	for _, email := range assignees {
+		if email != "" {
+			emails = append(emails, email)
+		}
+	}
+
+	if len(emails) > 0 {
+		userIDs, err := resolve(ctx, emails)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to look up assignees: %w", err)
+		}
+
+		for i, email := range assignees {
+			if email == "" {
+				continue
+			}
+			userID, ok := userIDs[email]
+			if !ok {
+				report.Errors = append(report.Errors, RowError{
+					Row: rows[i], Field: FieldAssigneeEmail,
+					Message: fmt.Sprintf("no user with email %s", email),
+				})
+				continue
+			}
+			tasks[i].AssignedToID = &userID
+		}
+	}
+
+	// Assignee errors are found after the first pass; keep the report in row order
+	sort.SliceStable(report.Errors, func(i, j int) bool {
+		return report.Errors[i].Row < report.Errors[j].Row
+	})
+
+	return tasks, report, nil
+}
+
+// resolveColumns finds the index of the column holding each field
+func resolveColumns(header []string, mapping Mapping) (map[string]int, error) {
+	indexes := make(map[string]int, len(header))
+	for i, name := range header {
+		indexes[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+
+	columns := make(map[string]int)
+	for _, field := range Fields {
+		name, mapped := mapping[field]
+		if !mapped {
+			name = field
+		}
+
+		index, ok := indexes[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			if mapped {
+				return nil, fmt.Errorf("column %q mapped to %s is not in the CSV header", name, field)
+			}
+			continue
+		}
+		columns[field] = index
+	}
+
+	for field := range mapping {
+		if !contains(Fields, field) {
+			return nil, fmt.Errorf("unknown field %q in column mapping", field)
+		}
+	}
+
+	if _, ok := columns[FieldTitle]; !ok {
+		return nil, errors.New("no column is mapped to title")
+	}
+
+	return columns, nil
+}
+
+func parseRow(row int, value func(string) string, creatorID string) (*models.Task, []RowError) {
+	var rowErrors []RowError
+	fail := func(field, message string) {
+		rowErrors = append(rowErrors, RowError{Row: row, Field: field, Message: message})
+	}
+
+	task := &models.Task{
+		Title:       value(FieldTitle),
+		Status:      "TODO",
+		Priority:    "MEDIUM",
+		CreatedByID: creatorID,
+		Occurrence:  1,
+	}
+
+	switch {
+	case task.Title == "":
+		fail(FieldTitle, "title is required")
+	case utf8.RuneCountInString(task.Title) > maxTitleLength:
+		fail(FieldTitle, fmt.Sprintf("title is longer than %d characters", maxTitleLength))
+	}
+
+	if description := value(FieldDescription); description != "" {
+		task.Description = &description
+	}
+
+	if status := value(FieldStatus); status != "" {
+		task.Status = normalizeEnum(status)
+		if !contains(statuses, task.Status) {
+			fail(FieldStatus, fmt.Sprintf("invalid status %q, expected one of %s", status, strings.Join(statuses, ", ")))
+		}
+	}
+
+	if priority := value(FieldPriority); priority != "" {
+		task.Priority = normalizeEnum(priority)
+		if !contains(priorities, task.Priority) {
+			fail(FieldPriority, fmt.Sprintf("invalid priority %q, expected one of %s", priority, strings.Join(priorities, ", ")))
+		}
+	}
+
+	if dueDate := value(FieldDueDate); dueDate != "" {
+		parsed, err := parseDate(dueDate)
+		if err != nil {
+			fail(FieldDueDate, err.Error())
+		} else {
+			task.DueDate = &parsed
+		}
+	}
+
+	return task, rowErrors
+}
+
+// normalizeEnum accepts spreadsheet spellings such as "In progress"
+func normalizeEnum(s string) string {
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(strings.ToUpper(s))
+}
+
+func parseDate(s string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q, expected YYYY-MM-DD or RFC 3339", s)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+type jiraUser struct {
+	AccountID    string `json:"accountId"`
+	Name         string `json:"name"`
+	DisplayName  string `json:"displayName"`
+	EmailAddress string `json:"emailAddress"`
+}
+
+// jiraExport is the part of a Jira issue search JSON export that is imported
+type jiraExport struct {
+	Issues []struct {
+		ID     string `json:"id"`
+		Key    string `json:"key"`
+		Fields struct {
+			Summary string `json:"summary"`
+			// A string in API v2 exports, an Atlassian Document Format tree in v3
+			Description json.RawMessage `json:"description"`
+			Status      struct {
+				Name           string `json:"name"`
+				StatusCategory struct {
+					Key string `json:"key"`
+				} `json:"statusCategory"`
+			} `json:"status"`
+			Priority *struct {
+				Name string `json:"name"`
+			} `json:"priority"`
+			Assignee *jiraUser `json:"assignee"`
+			Reporter *jiraUser `json:"reporter"`
+			DueDate  string    `json:"duedate"`
+		} `json:"fields"`
+	} `json:"issues"`
+}
+
+// ParseJira reads a Jira issue search JSON export. Statuses are mapped through
+// their status category, using the status name to tell review from other work
+// in progress. The reporter becomes the creator.
+func ParseJira(r io.Reader) ([]*Item, error) {
+	var export jiraExport
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("invalid Jira export: %w", err)
+	}
+
+	items := make([]*Item, 0, len(export.Issues))
+	for _, issue := range export.Issues {
+		fields := issue.Fields
+
+		item := &Item{
+			ExternalID:  issue.ID,
+			Title:       truncateTitle(fields.Summary),
+			Description: optionalString(jiraText(fields.Description)),
+			Priority:    "MEDIUM",
+			Creator:     jiraPerson(fields.Reporter),
+			Assignee:    jiraPerson(fields.Assignee),
+		}
+		if item.Title == "" {
+			item.Title = issue.Key
+		}
+
+		switch fields.Status.StatusCategory.Key {
+		case "done":
+			item.Status = "DONE"
+		case "indeterminate":
+			item.Status = "IN_PROGRESS"
+			if statusFromName(fields.Status.Name) == "REVIEW" {
+				item.Status = "REVIEW"
+			}
+		case "new":
+			item.Status = "TODO"
+		default:
+			item.Status = statusFromName(fields.Status.Name)
+		}
+
+		if fields.Priority != nil {
+			if priority, ok := priorityFromName(fields.Priority.Name); ok {
+				item.Priority = priority
+			}
+		}
+
+		if fields.DueDate != "" {
+			due, err := time.Parse("2006-01-02", fields.DueDate)
+			if err != nil {
+				return nil, fmt.Errorf("invalid due date on %s: %w", issue.Key, err)
+			}
+			item.DueDate = &due
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func jiraPerson(user *jiraUser) *Person {
+	if user == nil {
+		return nil
+	}
+
+	id := user.AccountID
+	if id == "" {
+		// Jira Server identifies users by name
+		id = user.Name
+	}
+	if id == "" {
+		return nil
+	}
+
+	return &Person{ExternalID: id, Name: user.DisplayName, Email: user.EmailAddress}
+}
+
+// jiraText flattens a description to plain text, whichever format it is in
+func jiraText(raw json.RawMessage) string {
+	if len(raw) == 0 || string(raw) == "null" {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return text
+	}
+
+	var doc adfNode
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+
+	var b strings.Builder
+	doc.writeText(&b)
+	return strings.TrimSpace(b.String())
+}
+
+// adfNode is a node of an Atlassian Document Format tree
+type adfNode struct {
+	Type    string    `json:"type"`
+	Text    string    `json:"text"`
+	Content []adfNode `json:"content"`
+}
+
+func (n adfNode) writeText(b *strings.Builder) {
+	switch n.Type {
+	case "text":
+		b.WriteString(n.Text)
+	case "hardBreak":
+		b.WriteString("\n")
+	}
+
+	for _, child := range n.Content {
+		child.writeText(b)
+	}
+
+	switch n.Type {
+	case "paragraph", "heading", "listItem", "codeBlock", "blockquote":
+		b.WriteString("\n")
+	}
+}
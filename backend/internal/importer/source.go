@@ -0,0 +1,254 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// Sources tasks can be imported from
+const (
+	SourceTrello = "trello"
+	SourceJira   = "jira"
+)
+
+// placeholderPasswordHash is not a valid bcrypt hash, so placeholder users
+// cannot log in until they set a password
+const placeholderPasswordHash = "!"
+
+// Person is a user as known to the source tool. Email may be empty.
+type Person struct {
+	ExternalID string
+	Name       string
+	Email      string
+}
+
+// Item is one task read from a source export, already mapped onto our
+// statuses and priorities
+type Item struct {
+	ExternalID  string
+	Title       string
+	Description *string
+	Status      string
+	Priority    string
+	DueDate     *time.Time
+	Creator     *Person
+	Assignee    *Person
+}
+
+// Result summarizes an import from a source
+type Result struct {
+	Created      int
+	Skipped      int
+	UsersCreated int
+	UsersMatched int
+}
+
+// Importer creates tasks and users for items read from a source, skipping
+// items imported before
+type Importer struct {
+	userRepo   *repository.UserRepository
+	taskRepo   *repository.TaskRepository
+	importRepo *repository.ImportRepository
+	tx         repository.TxRunner
+}
+
+func NewImporter(
+	userRepo *repository.UserRepository,
+	taskRepo *repository.TaskRepository,
+	importRepo *repository.ImportRepository,
+	tx repository.TxRunner,
+) *Importer {
+	return &Importer{userRepo: userRepo, taskRepo: taskRepo, importRepo: importRepo, tx: tx}
+}
+
+// Run imports items from source. Tasks without a known creator are created
+// by defaultCreatorID. With dryRun nothing is written, but the result still
+// counts what would happen. Each item is imported in a transaction of its own,
+// so a task is never left behind without its ID mapping.
+func (imp *Importer) Run(ctx context.Context, source string, items []*Item, defaultCreatorID string, dryRun bool) (*Result, error) {
+	result := &Result{}
+	users := make(map[string]string)
+
+	for _, item := range items {
+		err := imp.tx.WithTx(ctx, func(ctx context.Context) error {
+			return imp.importItem(ctx, source, item, defaultCreatorID, users, result, dryRun)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// importItem creates the task for item and records its mapping, unless it
+// was imported before
+func (imp *Importer) importItem(ctx context.Context, source string, item *Item, defaultCreatorID string, users map[string]string, result *Result, dryRun bool) error {
+	resolve := func(person *Person) (*string, error) {
+		if person == nil {
+			return nil, nil
+		}
+		id, err := imp.resolveUser(ctx, source, person, users, result, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		return &id, nil
+	}
+
+	existing, err := imp.importRepo.Lookup(ctx, source, repository.ImportKindTask, item.ExternalID)
+	if err != nil {
+		return err
+	}
+	if existing != "" {
+		result.Skipped++
+		return nil
+	}
+
+	creatorID := defaultCreatorID
+	if creator, err := resolve(item.Creator); err != nil {
+		return err
+	} else if creator != nil {
+		creatorID = *creator
+	}
+
+	assigneeID, err := resolve(item.Assignee)
+	if err != nil {
+		return err
+	}
+
+	result.Created++
+	if dryRun {
+		return nil
+	}
+
+	task, err := imp.taskRepo.Create(ctx, &models.Task{
+		Title:        item.Title,
+		Description:  item.Description,
+		Status:       item.Status,
+		Priority:     item.Priority,
+		CreatedByID:  creatorID,
+		AssignedToID: assigneeID,
+		DueDate:      item.DueDate,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to import %s: %w", item.ExternalID, err)
+	}
+
+	return imp.importRepo.Record(ctx, source, repository.ImportKindTask, item.ExternalID, task.ID)
+}
+
+// resolveUser finds the user for a person, by earlier import or by email,
+// creating a placeholder user if there is none. users caches the answer per
+// external ID for the current run.
+func (imp *Importer) resolveUser(ctx context.Context, source string, person *Person, users map[string]string, result *Result, dryRun bool) (string, error) {
+	if id, ok := users[person.ExternalID]; ok {
+		return id, nil
+	}
+
+	id, err := imp.importRepo.Lookup(ctx, source, repository.ImportKindUser, person.ExternalID)
+	if err != nil {
+		return "", err
+	}
+
+	email := strings.ToLower(strings.TrimSpace(person.Email))
+	if email == "" {
+		// Sources that hide emails get a unique, undeliverable address
+		email = fmt.Sprintf("%s-%s@placeholder.invalid", source, strings.ToLower(person.ExternalID))
+	}
+
+	if id == "" {
+		ids, err := imp.userRepo.IDsByEmail(ctx, []string{email})
+		if err != nil {
+			return "", err
+		}
+		id = ids[email]
+		if id != "" {
+			result.UsersMatched++
+		}
+	}
+
+	if id == "" {
+		result.UsersCreated++
+		if dryRun {
+			id = "placeholder:" + person.ExternalID
+		} else {
+			name := person.Name
+			if name == "" {
+				name = email
+			}
+			user, err := imp.userRepo.Create(ctx, &models.User{
+				Email:        email,
+				PasswordHash: placeholderPasswordHash,
+				Name:         name,
+			})
+			if err != nil {
+				return "", fmt.Errorf("failed to create placeholder user for %s: %w", person.ExternalID, err)
+			}
+			id = user.ID
+		}
+	}
+
+	if !dryRun {
+		if err := imp.importRepo.Record(ctx, source, repository.ImportKindUser, person.ExternalID, id); err != nil {
+			return "", err
+		}
+	}
+
+	users[person.ExternalID] = id
+	return id, nil
+}
+
+// statusFromName guesses a status from a Trello list or Jira status name
+func statusFromName(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "done"), strings.Contains(name, "complete"),
+		strings.Contains(name, "closed"), strings.Contains(name, "resolved"):
+		return "DONE"
+	case strings.Contains(name, "review"), strings.Contains(name, "qa"),
+		strings.Contains(name, "test"), strings.Contains(name, "verif"):
+		return "REVIEW"
+	case strings.Contains(name, "progress"), strings.Contains(name, "doing"),
+		strings.Contains(name, "started"), strings.Contains(name, "develop"):
+		return "IN_PROGRESS"
+	default:
+		return "TODO"
+	}
+}
+
+// priorityFromName maps Jira priority names and Trello priority labels
+func priorityFromName(name string) (string, bool) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "highest", "blocker", "critical", "urgent":
+		return "URGENT", true
+	case "high", "major":
+		return "HIGH", true
+	case "medium", "normal":
+		return "MEDIUM", true
+	case "low", "lowest", "minor", "trivial":
+		return "LOW", true
+	}
+	return "", false
+}
+
+func truncateTitle(title string) string {
+	title = strings.TrimSpace(title)
+	if utf8.RuneCountInString(title) <= maxTitleLength {
+		return title
+	}
+	return string([]rune(title)[:maxTitleLength])
+}
+
+func optionalString(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// trelloBoard is the part of a Trello board JSON export that is imported
+type trelloBoard struct {
+	Lists []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"lists"`
+	Cards []struct {
+		ID          string     `json:"id"`
+		Name        string     `json:"name"`
+		Desc        string     `json:"desc"`
+		IDList      string     `json:"idList"`
+		IDMembers   []string   `json:"idMembers"`
+		Due         *time.Time `json:"due"`
+		DueComplete bool       `json:"dueComplete"`
+		Labels      []struct {
+			Name string `json:"name"`
+		} `json:"labels"`
+	} `json:"cards"`
+	Members []struct {
+		ID       string `json:"id"`
+		FullName string `json:"fullName"`
+		Username string `json:"username"`
+		Email    string `json:"email"`
+	} `json:"members"`
+	Actions []struct {
+		Type            string `json:"type"`
+		IDMemberCreator string `json:"idMemberCreator"`
+		Data            struct {
+			Card struct {
+				ID string `json:"id"`
+			} `json:"card"`
+		} `json:"data"`
+	} `json:"actions"`
+}
+
+// ParseTrello reads a Trello board JSON export. A card's status comes from the
+// name of its list, or DONE once its due date is marked complete; its priority
+// from a label named like a priority. The first member becomes the assignee
+// and whoever created the card, if the export still has that action, the creator.
+func ParseTrello(r io.Reader) ([]*Item, error) {
+	var board trelloBoard
+	if err := json.NewDecoder(r).Decode(&board); err != nil {
+		return nil, fmt.Errorf("invalid Trello export: %w", err)
+	}
+
+	lists := make(map[string]string, len(board.Lists))
+	for _, list := range board.Lists {
+		lists[list.ID] = list.Name
+	}
+
+	members := make(map[string]*Person, len(board.Members))
+	for _, member := range board.Members {
+		name := member.FullName
+		if name == "" {
+			name = member.Username
+		}
+		members[member.ID] = &Person{ExternalID: member.ID, Name: name, Email: member.Email}
+	}
+
+	creators := make(map[string]string)
+	for _, action := range board.Actions {
+		if action.Type == "createCard" {
+			creators[action.Data.Card.ID] = action.IDMemberCreator
+		}
+	}
+
+	items := make([]*Item, 0, len(board.Cards))
+	for _, card := range board.Cards {
+		item := &Item{
+			ExternalID:  card.ID,
+			Title:       truncateTitle(card.Name),
+			Description: optionalString(card.Desc),
+			Status:      statusFromName(lists[card.IDList]),
+			Priority:    "MEDIUM",
+			DueDate:     card.Due,
+			Creator:     members[creators[card.ID]],
+		}
+		if item.Title == "" {
+			item.Title = "Untitled card"
+		}
+		if card.DueComplete {
+			item.Status = "DONE"
+		}
+		for _, label := range card.Labels {
+			if priority, ok := priorityFromName(label.Name); ok {
+				item.Priority = priority
+				break
+			}
+		}
+		if len(card.IDMembers) > 0 {
+			item.Assignee = members[card.IDMembers[0]]
+		}
+
+		items = append(items, item)
+	}
+
+	return items, nil
+}
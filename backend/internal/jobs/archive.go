@@ -0,0 +1,25 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"taskboard/internal/cache"
+	"taskboard/internal/repository"
+)
+
+// ArchiveCompletedTasks archives tasks that have been DONE for longer than after
+func ArchiveCompletedTasks(taskRepo repository.TaskStore, taskCache cache.Cache, after time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		archived, err := taskRepo.ArchiveCompleted(ctx, time.Now().Add(-after))
+		if err != nil {
+			return err
+		}
+
+		if archived > 0 && taskCache != nil {
+			taskCache.DeletePattern(ctx, "tasks:*")
+		}
+
+		return nil
+	}
+}
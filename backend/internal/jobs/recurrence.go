@@ -0,0 +1,41 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"taskboard/internal/cache"
+	"taskboard/internal/repository"
+)
+
+// SpawnRecurringTasks schedules the next occurrence of every recurring task
+// whose due date has passed, whether or not it was completed. A series that
+// fell behind resumes at its next future occurrence; the ones it missed are
+// not created.
+func SpawnRecurringTasks(taskRepo repository.TaskStore, taskCache cache.Cache) func(context.Context) error {
+	return func(ctx context.Context) error {
+		due, err := taskRepo.ListRecurrenceDue(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+
+		spawned := 0
+		for _, task := range due {
+			next, err := taskRepo.SpawnNextOccurrence(ctx, task)
+			if err != nil {
+				log.Printf("failed to spawn next occurrence of task %s: %v", task.ID, err)
+				continue
+			}
+			if next != nil {
+				spawned++
+			}
+		}
+
+		if spawned > 0 && taskCache != nil {
+			taskCache.DeletePattern(ctx, "tasks:*")
+		}
+
+		return nil
+	}
+}
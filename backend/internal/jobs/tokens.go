@@ -0,0 +1,61 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"taskboard/internal/repository"
+)
+
+// PurgeExpiredRefreshTokens removes refresh tokens that expired over a day
+// ago. Expired tokens are rejected before reuse is checked, so keeping them
+// longer would not catch anything more.
+func PurgeExpiredRefreshTokens(tokenRepo repository.RefreshTokenStore) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := tokenRepo.PurgeExpired(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+
+		if purged > 0 {
+			log.Printf("purged %d expired refresh tokens", purged)
+		}
+
+		return nil
+	}
+}
+
+// PurgeInactiveSessions removes sessions unused for longer than a refresh
+// token lives, which can no longer be resumed, together with their tokens
+func PurgeInactiveSessions(sessionRepo repository.SessionStore, idle time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := sessionRepo.PurgeInactive(ctx, time.Now().Add(-idle))
+		if err != nil {
+			return err
+		}
+
+		if purged > 0 {
+			log.Printf("purged %d inactive sessions", purged)
+		}
+
+		return nil
+	}
+}
+
+// PurgeExpiredPasswordResets removes password reset tokens that expired over a
+// day ago, used or not
+func PurgeExpiredPasswordResets(resetRepo repository.PasswordResetStore) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, err := resetRepo.PurgeExpired(ctx, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+
+		if purged > 0 {
+			log.Printf("purged %d expired password resets", purged)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,30 @@
+package jobs
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"taskboard/internal/attachments"
+	"taskboard/internal/repository"
+)
+
+// PurgeDeletedTasks permanently removes tasks that have been in the trash for
+// longer than retention, along with the content of their attachments
+func PurgeDeletedTasks(taskRepo repository.TaskStore, attachmentService *attachments.Service, retention time.Duration) func(context.Context) error {
+	return func(ctx context.Context) error {
+		purged, keys, err := taskRepo.PurgeDeleted(ctx, time.Now().Add(-retention))
+		if err != nil {
+			return err
+		}
+
+		// Only once no row refers to it
+		attachmentService.DeleteContent(ctx, keys)
+
+		if purged > 0 {
+			log.Printf("purged %d deleted tasks", purged)
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// FileSender writes each message to a file in a directory instead of sending
+// it, for development
+type FileSender struct {
+	dir string
+}
+
+func NewFileSender(dir string) (*FileSender, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create mail directory: %w", err)
+	}
+	return &FileSender{dir: dir}, nil
+}
+
+// Send writes msg to a new .eml file named after the time it was sent
+func (s *FileSender) Send(ctx context.Context, msg Message) error {
+	now := time.Now()
+
+	f, err := os.CreateTemp(s.dir, now.UTC().Format("20060102T150405.000000000")+"-*.eml")
+	if err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(format(msg, now)); err != nil {
+		return fmt.Errorf("failed to write mail: %w", err)
+	}
+
+	return f.Close()
+}
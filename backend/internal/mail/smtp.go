@@ -0,0 +1,46 @@
+package mail
+
+import (
+	"context"
+	"fmt"
+	"net"
+	netmail "net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPSender sends messages through an SMTP server, authenticating when a
+// username is set
+type SMTPSender struct {
+	addr string
+	from string
+	auth smtp.Auth
+}
+
+func NewSMTPSender(host string, port int, username, password, from string) *SMTPSender {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPSender{addr: net.JoinHostPort(host, fmt.Sprint(port)), from: from, auth: auth}
+}
+
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if strings.ContainsAny(msg.To, "\r\n") {
+		return fmt.Errorf("invalid recipient %q", msg.To)
+	}
+
+	// The envelope takes the bare address of a "Name <address>" sender
+	envelope := s.from
+	if addr, err := netmail.ParseAddress(s.from); err == nil {
+		envelope = addr.Address
+	}
+
+	data := append([]byte("From: "+s.from+"\r\n"), format(msg, time.Now())...)
+	if err := smtp.SendMail(s.addr, s.auth, envelope, []string{msg.To}, data); err != nil {
+		return fmt.Errorf("failed to send mail: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package markdown renders task descriptions written in CommonMark, with the
+// GitHub extensions for task lists, tables, strikethrough and autolinks, to
+// HTML that is safe to insert into a page.
+package markdown
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"html"
+	"regexp"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Version identifies the rendering rules. Bump it when the parser options or
+// the allowlist change so cached HTML is rendered again.
+const Version = 1
+
+// MaxLength bounds the text renderMarkdown previews, in bytes
+const MaxLength = 100_000
+
+var converter = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+// policy is the allowlist rendered HTML is reduced to. Raw HTML in the source
+// is already dropped by the converter; the policy also removes unsafe URLs
+// and attributes, keeps code block languages and task list checkboxes, and
+// makes external links open in a new tab without passing on the referrer.
+var policy = func() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.AllowAttrs("class").Matching(regexp.MustCompile(`^language-[\w+#-]+$`)).OnElements("code")
+	p.AllowAttrs("type").Matching(regexp.MustCompile(`^checkbox$`)).OnElements("input")
+	p.AllowAttrs("checked", "disabled").OnElements("input")
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.RequireNoReferrerOnLinks(true)
+	return p
+}()
+
+// Render converts text to sanitized HTML
+func Render(text string) string {
+	var buf bytes.Buffer
+	if err := converter.Convert([]byte(text), &buf); err != nil {
+		// Converting into a buffer does not fail, but should it, show the
+		// source as plain text
+		return "<p>" + html.EscapeString(text) + "</p>"
+	}
+	return policy.Sanitize(buf.String())
+}
+
+// Hash identifies text and the rules it is rendered with, for caching
+func Hash(text string) string {
+	sum := sha256.Sum256(append([]byte{Version}, text...))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,96 @@
+// Package mentions finds the users a task description mentions. A mention is
+// "@" followed by a user's handle, the part of their email address before the
+// "@", or by their full email address: "@alice" or "@alice@example.com".
+package mentions
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"taskboard/internal/models"
+)
+
+// mentionPattern matches a mention that does not continue a word or an email
+// address. The name is captured with any trailing dots, which end sentences
+// rather than addresses.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w.@+-])@([\w.+-]+(?:@[\w-]+(?:\.[\w-]+)+)?)`)
+
+// Parse returns the handles and email addresses mentioned in text, lower
+// cased, each once, in the order they first appear
+func Parse(text string) []string {
+	var names []string
+	seen := make(map[string]bool)
+	for _, match := range mentionPattern.FindAllStringSubmatch(text, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], "."))
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names
+}
+
+// Handle returns the handle of the user with email
+func Handle(email string) string {
+	handle, _, _ := strings.Cut(strings.ToLower(email), "@")
+	return handle
+}
+
+// Resolve returns the IDs of the users names refers to, each once, in the
+// order of names. An email address resolves to the user registered with it;
+// a handle only resolves when exactly one of users has it, since anything
+// else would guess who was meant.
+func Resolve(names []string, users []*models.User) []string {
+	byEmail := make(map[string]string)
+	byHandle := make(map[string][]string)
+	for _, user := range users {
+		byEmail[strings.ToLower(user.Email)] = user.ID
+		handle := Handle(user.Email)
+		byHandle[handle] = append(byHandle[handle], user.ID)
+	}
+
+	var ids []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		id, ok := byEmail[name]
+		if !ok && !strings.Contains(name, "@") && len(byHandle[name]) == 1 {
+			id, ok = byHandle[name][0], true
+		}
+		if ok && !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+// Users looks up the users mentions may refer to
+type Users interface {
+	ListByMention(ctx context.Context, names []string) ([]*models.User, error)
+}
+
+// Recorder stores which users a task mentions
+type Recorder interface {
+	Sync(ctx context.Context, taskID, mentionedByID string, userIDs []string) ([]string, error)
+}
+
+// Sync records the users task's description mentions. Users already
+// mentioned keep their mention, so only newly added mentions are attributed
+// to authorID.
+func Sync(ctx context.Context, users Users, recorder Recorder, task *models.Task, authorID string) error {
+	var userIDs []string
+	if task.Description != nil {
+		if names := Parse(*task.Description); len(names) > 0 {
+			found, err := users.ListByMention(ctx, names)
+			if err != nil {
+				return err
+			}
+			userIDs = Resolve(names, found)
+		}
+	}
+
+	_, err := recorder.Sync(ctx, task.ID, authorID, userIDs)
+	return err
+}
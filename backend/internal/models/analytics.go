@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+)
+
+// CountBucket is the number of tasks sharing one value of a dimension
+type CountBucket struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Count int    `json:"count"`
+}
+
+// TaskStats summarizes every task by status, priority and assignee
+type TaskStats struct {
+	Total      int            `json:"total"`
+	Overdue    int            `json:"overdue"`
+	ByStatus   []*CountBucket `json:"by_status"`
+	ByPriority []*CountBucket `json:"by_priority"`
+	ByAssignee []*CountBucket `json:"by_assignee"`
+}
+
+// DailyThroughput counts tasks created and completed on one day
+type DailyThroughput struct {
+	Date                time.Time `json:"date"`
+	Created             int       `json:"created"`
+	Completed           int       `json:"completed"`
+	CumulativeCreated   int       `json:"cumulative_created"`
+	CumulativeCompleted int       `json:"cumulative_completed"`
+}
+
+// UserCycleTime averages how long an assignee's tasks took to finish, in seconds.
+// Cycle time runs from first entering IN_PROGRESS to DONE, lead time from creation to DONE.
+type UserCycleTime struct {
+	UserID           *string  `json:"user_id"`
+	CompletedCount   int      `json:"completed_count"`
+	AverageCycleTime *float64 `json:"average_cycle_time"`
+	AverageLeadTime  float64  `json:"average_lead_time"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"time"
+)
+
+// Attachment is a file uploaded to a task
+type Attachment struct {
+	ID          string    `json:"id" db:"id"`
+	TaskID      string    `json:"task_id" db:"task_id"`
+	UploaderID  string    `json:"uploader_id" db:"uploader_id"`
+	Filename    string    `json:"filename" db:"filename"`
+	ContentType string    `json:"content_type" db:"content_type"`
+	Size        int64     `json:"size" db:"size"`
+	StorageKey  string    `json:"-" db:"storage_key"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+}
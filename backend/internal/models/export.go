@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+)
+
+// TaskExport is one task flattened for spreadsheets, with its creator and
+// assignee resolved. Assignee fields are nil for unassigned tasks.
+type TaskExport struct {
+	ID            string     `json:"id"`
+	Title         string     `json:"title"`
+	Description   *string    `json:"description"`
+	Status        string     `json:"status"`
+	Priority      string     `json:"priority"`
+	CreatorName   string     `json:"creator_name"`
+	CreatorEmail  string     `json:"creator_email"`
+	AssigneeName  *string    `json:"assignee_name"`
+	AssigneeEmail *string    `json:"assignee_email"`
+	DueDate       *time.Time `json:"due_date"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+}
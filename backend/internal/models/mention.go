@@ -0,0 +1,14 @@
+package models
+
+import (
+	"time"
+)
+
+// Mention records that a task's description mentions a user. CreatedAt is
+// when the mention was added, which later edits keeping it do not change.
+type Mention struct {
+	TaskID        string    `json:"task_id" db:"task_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	MentionedByID string    `json:"mentioned_by_id" db:"mentioned_by_id"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// PasswordReset is a request to reset a user's password, stored by the hash
+// of the token mailed to them
+type PasswordReset struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	TokenHash string     `json:"-" db:"token_hash"`
+	ExpiresAt time.Time  `json:"expires_at" db:"expires_at"`
+	UsedAt    *time.Time `json:"used_at" db:"used_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+)
+
+type Sprint struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	Goal        *string   `json:"goal" db:"goal"`
+	StartDate   time.Time `json:"start_date" db:"start_date"`
+	EndDate     time.Time `json:"end_date" db:"end_date"`
+	CreatedByID string    `json:"created_by_id" db:"created_by_id"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// SprintTotals sums the estimates of the tasks currently in a sprint
+type SprintTotals struct {
+	ScopePoints     int     `json:"scope_points"`
+	CompletedPoints int     `json:"completed_points"`
+	ScopeHours      float64 `json:"scope_hours"`
+	CompletedHours  float64 `json:"completed_hours"`
+}
+
+// SprintScopeChange records a task entering or leaving a sprint
+type SprintScopeChange struct {
+	TaskID string    `json:"task_id"`
+	Change string    `json:"change"`
+	At     time.Time `json:"at"`
+}
+
+// BurndownPoint is the state of a sprint at the end of one day
+type BurndownPoint struct {
+	Date            time.Time `json:"date"`
+	ScopePoints     int       `json:"scope_points"`
+	CompletedPoints int       `json:"completed_points"`
+	ScopeHours      float64   `json:"scope_hours"`
+	CompletedHours  float64   `json:"completed_hours"`
+}
+
+func (p *BurndownPoint) RemainingPoints() int {
+	return p.ScopePoints - p.CompletedPoints
+}
+
+func (p *BurndownPoint) RemainingHours() float64 {
+	return p.ScopeHours - p.CompletedHours
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+)
+
+type TimeEntry struct {
+	ID        string     `json:"id" db:"id"`
+	TaskID    string     `json:"task_id" db:"task_id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	StartedAt time.Time  `json:"started_at" db:"started_at"`
+	EndedAt   *time.Time `json:"ended_at" db:"ended_at"`
+	Note      *string    `json:"note" db:"note"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+// Running reports whether the entry is an unstopped timer
+func (e *TimeEntry) Running() bool {
+	return e.EndedAt == nil
+}
+
+// Duration returns the tracked time in seconds, counting a running timer up to now
+func (e *TimeEntry) Duration() int64 {
+	end := time.Now()
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	return int64(end.Sub(e.StartedAt).Seconds())
+}
+
+// TimeReportRow is one group of a time report
+type TimeReportRow struct {
+	Key     string `json:"key"`
+	Label   string `json:"label"`
+	Seconds int64  `json:"seconds"`
+}
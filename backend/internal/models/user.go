@@ -12,6 +12,9 @@ type User struct {
 	Avatar       *string   `json:"avatar" db:"avatar"`
 	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at" db:"updated_at"`
+
+	// Set once the user follows the link mailed to their address
+	EmailVerifiedAt *time.Time `json:"email_verified_at" db:"email_verified_at"`
 }
 
 type Task struct {
@@ -25,6 +28,22 @@ type Task struct {
 	DueDate      *time.Time `json:"due_date" db:"due_date"`
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
+
+	// Recurrence
+	RecurrenceRule *string `json:"recurrence" db:"recurrence_rule"`
+	SeriesID       *string `json:"series_id" db:"series_id"`
+	Occurrence     int     `json:"occurrence" db:"occurrence"`
+
+	// Estimates
+	StoryPoints    *int       `json:"story_points" db:"story_points"`
+	EstimatedHours *float64   `json:"estimated_hours" db:"estimated_hours"`
+	CompletedAt    *time.Time `json:"completed_at" db:"completed_at"`
+
+	// Set once the task is archived, by hand or after being done for a while
+	ArchivedAt *time.Time `json:"archived_at" db:"archived_at"`
+
+	// Set while the task is in the trash
+	DeletedAt *time.Time `json:"deleted_at,omitempty" db:"deleted_at"`
 }
 
 // For GraphQL relationships
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// SavedView is a named task filter and sort. Query holds them in the
+// versioned format of package views.
+type SavedView struct {
+	ID        string    `json:"id" db:"id"`
+	OwnerID   string    `json:"owner_id" db:"owner_id"`
+	Name      string    `json:"name" db:"name"`
+	Query     []byte    `json:"-" db:"query"`
+	Shared    bool      `json:"shared" db:"shared"`
+	Pinned    bool      `json:"pinned" db:"pinned"`
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
+}
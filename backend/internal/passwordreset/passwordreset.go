@@ -0,0 +1,149 @@
+// Package passwordreset lets users who forgot their password set a new one.
+// Links carry a random token that is stored only as a hash, works once and
+// expires quickly; redeeming a token also spends every other token the user
+// was sent.
+package passwordreset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"taskboard/internal/auth"
+	"taskboard/internal/cache"
+	"taskboard/internal/mail"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// How many resets may be requested for one address, and from one client
+// address, within RequestWindow
+const (
+	MaxRequestsPerEmail = 3
+	MaxRequestsPerIP    = 10
+	RequestWindow       = time.Hour
+)
+
+// sendTimeout bounds mailing a link in the background
+const sendTimeout = time.Minute
+
+var ErrTooManyRequests = errors.New("too many password reset requests, try again later")
+
+// Service mails password reset links and redeems the tokens in them
+type Service struct {
+	resets  repository.PasswordResetStore
+	mailer  mail.Sender
+	limits  cache.Cache
+	linkURL string
+	ttl     time.Duration
+	pending sync.WaitGroup
+}
+
+// NewService returns a service whose links point at appURL's /reset-password
+// page and expire after ttl. Requests are counted in limits, which is shared
+// by every server so the limits hold across them.
+func NewService(resets repository.PasswordResetStore, mailer mail.Sender, limits cache.Cache, appURL string, ttl time.Duration) *Service {
+	return &Service{
+		resets:  resets,
+		mailer:  mailer,
+		limits:  limits,
+		linkURL: strings.TrimRight(appURL, "/") + "/reset-password",
+		ttl:     ttl,
+	}
+}
+
+// Allow counts a reset request for email coming from ip, and returns
+// ErrTooManyRequests once either made too many lately. Requests are counted
+// whether or not email has an account, so the answer does not tell. An empty
+// ip is not counted.
+func (s *Service) Allow(ctx context.Context, email, ip string) error {
+	limits := map[string]int64{
+		cache.PasswordResetRequestsKey("email", strings.ToLower(strings.TrimSpace(email))): MaxRequestsPerEmail,
+	}
+	if ip != "" {
+		limits[cache.PasswordResetRequestsKey("ip", ip)] = MaxRequestsPerIP
+	}
+
+	allowed := true
+	for key, max := range limits {
+		count, err := s.limits.Increment(ctx, key, RequestWindow)
+		if err != nil {
+			// Rather let requests through than lock everyone out
+			log.Printf("failed to count password reset requests: %v", err)
+			continue
+		}
+		if count > max {
+			allowed = false
+		}
+	}
+	if !allowed {
+		return ErrTooManyRequests
+	}
+
+	return nil
+}
+
+// SendLater does what Send does in the background, with a context of its
+// own, so answering takes no longer for addresses that have an account.
+// Failures are logged.
+func (s *Service) SendLater(user *models.User) {
+	s.pending.Add(1)
+	go func() {
+		defer s.pending.Done()
+
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		if err := s.Send(ctx, user); err != nil {
+			log.Printf("failed to send password reset email to user %s: %v", user.ID, err)
+		}
+	}()
+}
+
+// Wait blocks until the links SendLater started sending are sent
+func (s *Service) Wait() {
+	s.pending.Wait()
+}
+
+// Send stores a new reset token for user and mails them a link with it
+func (s *Service) Send(ctx context.Context, user *models.User) error {
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	_, err = s.resets.Create(ctx, &models.PasswordReset{
+		UserID:    user.ID,
+		TokenHash: auth.HashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(s.ttl),
+	})
+	if err != nil {
+		return err
+	}
+
+	link := s.linkURL + "?token=" + url.QueryEscape(token)
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to choose a new password:\n\n%s\n\n"+
+			"The link expires in %s and works once. If you did not ask to reset your password, ignore this email.\n",
+			user.Name, link, s.ttl),
+	})
+}
+
+// Redeem spends token and returns the ID of the user it was issued to, or
+// repository.ErrPasswordResetInvalid if it is unknown, expired or used
+func (s *Service) Redeem(ctx context.Context, token string) (string, error) {
+	reset, err := s.resets.Use(ctx, auth.HashOpaqueToken(token))
+	if err != nil {
+		return "", err
+	}
+
+	return reset.UserID, nil
+}
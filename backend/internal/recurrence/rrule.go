@@ -0,0 +1,264 @@
+// Package recurrence implements the subset of RFC 5545 recurrence rules
+// supported for repeating tasks: DAILY, WEEKLY (optionally BYDAY) and MONTHLY
+// (optionally BYMONTHDAY) frequencies, INTERVAL, and COUNT or UNTIL end conditions.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+)
+
+var ErrInvalidRule = errors.New("invalid recurrence rule")
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay int
+	Count      int
+	Until      *time.Time
+}
+
+// Parse parses an RRULE value such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR;COUNT=10".
+// A leading "RRULE:" is accepted.
+func Parse(value string) (*Rule, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "RRULE:")
+	if value == "" {
+		return nil, fmt.Errorf("%w: empty rule", ErrInvalidRule)
+	}
+
+	rule := &Rule{Interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("%w: malformed part %q", ErrInvalidRule, part)
+		}
+
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			rule.Freq = Frequency(strings.ToUpper(val))
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly {
+				return nil, fmt.Errorf("%w: unsupported FREQ %s", ErrInvalidRule, val)
+			}
+		case "INTERVAL":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: INTERVAL must be a positive integer", ErrInvalidRule)
+			}
+			rule.Interval = n
+		case "BYDAY":
+			for _, day := range strings.Split(strings.ToUpper(val), ",") {
+				wd, ok := weekdays[day]
+				if !ok {
+					return nil, fmt.Errorf("%w: unsupported BYDAY value %s", ErrInvalidRule, day)
+				}
+				rule.ByDay = append(rule.ByDay, wd)
+			}
+		case "BYMONTHDAY":
+			n, err := strconv.Atoi(val)
+			if err != nil || n == 0 || n < -31 || n > 31 {
+				return nil, fmt.Errorf("%w: BYMONTHDAY must be between 1 and 31 or -31 and -1", ErrInvalidRule)
+			}
+			rule.ByMonthDay = n
+		case "COUNT":
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("%w: COUNT must be a positive integer", ErrInvalidRule)
+			}
+			rule.Count = n
+		case "UNTIL":
+			until, err := parseUntil(val)
+			if err != nil {
+				return nil, err
+			}
+			rule.Until = &until
+		default:
+			return nil, fmt.Errorf("%w: unsupported part %s", ErrInvalidRule, key)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, fmt.Errorf("%w: FREQ is required", ErrInvalidRule)
+	case rule.Count > 0 && rule.Until != nil:
+		return nil, fmt.Errorf("%w: COUNT and UNTIL are mutually exclusive", ErrInvalidRule)
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, fmt.Errorf("%w: BYDAY is only supported with FREQ=WEEKLY", ErrInvalidRule)
+	case rule.ByMonthDay != 0 && rule.Freq != Monthly:
+		return nil, fmt.Errorf("%w: BYMONTHDAY is only supported with FREQ=MONTHLY", ErrInvalidRule)
+	}
+
+	sort.Slice(rule.ByDay, func(i, j int) bool {
+		return mondayIndex(rule.ByDay[i]) < mondayIndex(rule.ByDay[j])
+	})
+
+	return rule, nil
+}
+
+func parseUntil(val string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, val); err == nil {
+			if layout == "20060102" {
+				// A date-only UNTIL includes the whole day
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: UNTIL must be YYYYMMDD or YYYYMMDDTHHMMSSZ", ErrInvalidRule)
+}
+
+// String renders the rule in canonical RRULE form
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, wd := range r.ByDay {
+			days[i] = strings.ToUpper(wd.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if r.ByMonthDay != 0 {
+		parts = append(parts, "BYMONTHDAY="+strconv.Itoa(r.ByMonthDay))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.Until != nil {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the occurrence following prev, which is the occurrence-th
+// (1-based) occurrence of the series. It reports false once the series has ended.
+func (r *Rule) Next(prev time.Time, occurrence int) (time.Time, bool) {
+	if r.Count > 0 && occurrence >= r.Count {
+		return time.Time{}, false
+	}
+
+	var next time.Time
+	switch r.Freq {
+	case Daily:
+		next = prev.AddDate(0, 0, r.Interval)
+	case Weekly:
+		next = r.nextWeekly(prev)
+	case Monthly:
+		var ok bool
+		if next, ok = r.nextMonthly(prev); !ok {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if r.Until != nil && next.After(*r.Until) {
+		return time.Time{}, false
+	}
+
+	return next, true
+}
+
+// NextAfter returns the first occurrence following prev that falls after
+// after, with its 1-based number, skipping the occurrences in between. prev is
+// the occurrence-th occurrence. It reports false if the series ends first.
+func (r *Rule) NextAfter(prev time.Time, occurrence int, after time.Time) (time.Time, int, bool) {
+	for {
+		next, ok := r.Next(prev, occurrence)
+		if !ok {
+			return time.Time{}, 0, false
+		}
+		occurrence++
+		if next.After(after) {
+			return next, occurrence, true
+		}
+		prev = next
+	}
+}
+
+func (r *Rule) nextWeekly(prev time.Time) time.Time {
+	if len(r.ByDay) == 0 {
+		return prev.AddDate(0, 0, 7*r.Interval)
+	}
+
+	// Later days in the same week come first
+	current := mondayIndex(prev.Weekday())
+	for _, wd := range r.ByDay {
+		if idx := mondayIndex(wd); idx > current {
+			return prev.AddDate(0, 0, idx-current)
+		}
+	}
+
+	// Otherwise the first listed day, Interval weeks on
+	weekStart := prev.AddDate(0, 0, -current)
+	return weekStart.AddDate(0, 0, 7*r.Interval+mondayIndex(r.ByDay[0]))
+}
+
+func (r *Rule) nextMonthly(prev time.Time) (time.Time, bool) {
+	day := r.ByMonthDay
+	if day == 0 {
+		day = prev.Day()
+	}
+
+	year, month, _ := prev.Date()
+	// Months without the requested day are skipped, as RFC 5545 requires.
+	// Four years of candidates always contain a valid one. The month of prev
+	// itself is a candidate when prev falls before the requested day.
+	for i := 0; i <= 48; i++ {
+		y, m := year, month+time.Month(i*r.Interval)
+		first := time.Date(y, m, 1, prev.Hour(), prev.Minute(), prev.Second(), 0, prev.Location())
+		length := first.AddDate(0, 1, -1).Day()
+
+		d := day
+		if d < 0 {
+			d = length + d + 1
+		}
+		if d >= 1 && d <= length {
+			if next := first.AddDate(0, 0, d-1); next.After(prev) {
+				return next, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// mondayIndex numbers weekdays from Monday (0) to Sunday (6), the RFC 5545 default WKST
+func mondayIndex(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// Normalize validates an RRULE value and returns its canonical form
+func Normalize(value string) (string, error) {
+	rule, err := Parse(value)
+	if err != nil {
+		return "", err
+	}
+	return rule.String(), nil
+}
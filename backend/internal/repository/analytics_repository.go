@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+type AnalyticsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAnalyticsRepository(db *pgxpool.Pool) *AnalyticsRepository {
+	return &AnalyticsRepository{db: db}
+}
+
+// Stats counts every task by status, priority and assignee in a single pass.
+// Unassigned tasks are reported under an empty key.
+func (r *AnalyticsRepository) Stats(ctx context.Context) (*models.TaskStats, error) {
+	query := `
+		SELECT CASE
+		           WHEN GROUPING(t.status) = 0 THEN 'status'
+		           WHEN GROUPING(t.priority) = 0 THEN 'priority'
+		           WHEN GROUPING(t.assigned_to_id) = 0 THEN 'assignee'
+		           ELSE 'total'
+		       END AS dimension,
+		       COALESCE(t.status, t.priority, t.assigned_to_id::text, '') AS key,
+		       COALESCE(t.status, t.priority, u.name, '') AS label,
+		       COUNT(*) AS count,
+		       COUNT(*) FILTER (WHERE t.due_date < NOW() AND t.status <> 'DONE') AS overdue
+		FROM tasks t
+		LEFT JOIN users u ON u.id = t.assigned_to_id
+		WHERE t.deleted_at IS NULL
+		GROUP BY GROUPING SETS ((t.status), (t.priority), (t.assigned_to_id, u.name), ())
+		ORDER BY dimension, count DESC, key
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute task stats: %w", err)
+	}
+	defer rows.Close()
+
+	stats := &models.TaskStats{
+		ByStatus:   []*models.CountBucket{},
+		ByPriority: []*models.CountBucket{},
+		ByAssignee: []*models.CountBucket{},
+	}
+	for rows.Next() {
+		var dimension string
+		var overdue int
+		var bucket models.CountBucket
+		if err := rows.Scan(&dimension, &bucket.Key, &bucket.Label, &bucket.Count, &overdue); err != nil {
+			return nil, fmt.Errorf("failed to scan task stats: %w", err)
+		}
+
+		switch dimension {
+		case "status":
+			stats.ByStatus = append(stats.ByStatus, &bucket)
+		case "priority":
+			stats.ByPriority = append(stats.ByPriority, &bucket)
+		case "assignee":
+			if bucket.Key == "" {
+				bucket.Label = "Unassigned"
+			}
+			stats.ByAssignee = append(stats.ByAssignee, &bucket)
+		default:
+			stats.Total = bucket.Count
+			stats.Overdue = overdue
+		}
+	}
+
+	return stats, rows.Err()
+}
+
+// Throughput counts tasks created and completed on each UTC day in [from, to),
+// along with running totals since from. from is rounded down to midnight.
+func (r *AnalyticsRepository) Throughput(ctx context.Context, from, to time.Time) ([]*models.DailyThroughput, error) {
+	query := `
+		WITH days AS (
+		    SELECT d AS day, d + INTERVAL '1 day' AS day_end
+		    FROM generate_series($1::timestamptz, $2::timestamptz, INTERVAL '1 day') AS d
+		    WHERE d < $2
+		),
+		counts AS (
+		    SELECT days.day,
+		           COUNT(t.id) FILTER (WHERE t.created_at >= days.day AND t.created_at < days.day_end) AS created,
+		           COUNT(t.id) FILTER (WHERE t.completed_at >= days.day AND t.completed_at < days.day_end) AS completed
+		    FROM days
+		    LEFT JOIN tasks t
+		           ON t.deleted_at IS NULL
+		          AND ((t.created_at >= days.day AND t.created_at < days.day_end)
+		           OR (t.completed_at >= days.day AND t.completed_at < days.day_end))
+		    GROUP BY days.day
+		)
+		SELECT day, created, completed,
+		       SUM(created) OVER (ORDER BY day)::bigint,
+		       SUM(completed) OVER (ORDER BY day)::bigint
+		FROM counts
+		ORDER BY day
+	`
+
+	from = from.UTC().Truncate(24 * time.Hour)
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute throughput: %w", err)
+	}
+	defer rows.Close()
+
+	var days []*models.DailyThroughput
+	for rows.Next() {
+		var day models.DailyThroughput
+		err := rows.Scan(
+			&day.Date, &day.Created, &day.Completed,
+			&day.CumulativeCreated, &day.CumulativeCompleted,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan throughput: %w", err)
+		}
+		days = append(days, &day)
+	}
+
+	return days, rows.Err()
+}
+
+// CycleTimes averages cycle and lead time per assignee over the tasks completed
+// in [from, to). Cycle time is measured from the first time a task entered
+// IN_PROGRESS; tasks that went straight to DONE only count towards lead time.
+func (r *AnalyticsRepository) CycleTimes(ctx context.Context, from, to time.Time) ([]*models.UserCycleTime, error) {
+	query := `
+		WITH completed AS (
+		    SELECT id, assigned_to_id, created_at, completed_at
+		    FROM tasks
+		    WHERE status = 'DONE' AND completed_at >= $1 AND completed_at < $2
+		      AND deleted_at IS NULL
+		),
+		started AS (
+		    SELECT DISTINCT h.task_id,
+		           FIRST_VALUE(h.changed_at) OVER (PARTITION BY h.task_id ORDER BY h.changed_at) AS started_at
+		    FROM task_status_history h
+		    JOIN completed c ON c.id = h.task_id
+		    WHERE h.status = 'IN_PROGRESS' AND h.changed_at <= c.completed_at
+		)
+		SELECT c.assigned_to_id::text,
+		       COUNT(*) AS completed_count,
+		       AVG(EXTRACT(EPOCH FROM (c.completed_at - s.started_at)))::float8,
+		       AVG(EXTRACT(EPOCH FROM (c.completed_at - c.created_at)))::float8
+		FROM completed c
+		LEFT JOIN started s ON s.task_id = c.id
+		GROUP BY c.assigned_to_id
+		ORDER BY completed_count DESC, c.assigned_to_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute cycle times: %w", err)
+	}
+	defer rows.Close()
+
+	var result []*models.UserCycleTime
+	for rows.Next() {
+		var row models.UserCycleTime
+		err := rows.Scan(&row.UserID, &row.CompletedCount, &row.AverageCycleTime, &row.AverageLeadTime)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cycle times: %w", err)
+		}
+		result = append(result, &row)
+	}
+
+	return result, rows.Err()
+}
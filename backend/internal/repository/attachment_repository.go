@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+const attachmentColumns = `id, task_id, uploader_id, filename, content_type, size, storage_key, created_at`
+
+type AttachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+// Create inserts an attachment whose ID and storage key the caller has chosen
+func (r *AttachmentRepository) Create(ctx context.Context, attachment *models.Attachment) (*models.Attachment, error) {
+	query := `
+		INSERT INTO attachments (id, task_id, uploader_id, filename, content_type, size, storage_key)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING created_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		attachment.ID, attachment.TaskID, attachment.UploaderID, attachment.Filename,
+		attachment.ContentType, attachment.Size, attachment.StorageKey,
+	).Scan(&attachment.CreatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) GetByID(ctx context.Context, id string) (*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE id = $1`
+
+	attachment, err := scanAttachment(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("attachment not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	return attachment, nil
+}
+
+func (r *AttachmentRepository) ListByTask(ctx context.Context, taskID string) ([]*models.Attachment, error) {
+	query := `SELECT ` + attachmentColumns + ` FROM attachments WHERE task_id = $1 ORDER BY created_at`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list attachments: %w", err)
+	}
+	defer rows.Close()
+
+	var attachments []*models.Attachment
+	for rows.Next() {
+		attachment, err := scanAttachment(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan attachment: %w", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, rows.Err()
+}
+
+func (r *AttachmentRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM attachments WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("attachment not found")
+	}
+
+	return nil
+}
+
+func scanAttachment(row pgx.Row) (*models.Attachment, error) {
+	var attachment models.Attachment
+	err := row.Scan(
+		&attachment.ID, &attachment.TaskID, &attachment.UploaderID, &attachment.Filename,
+		&attachment.ContentType, &attachment.Size, &attachment.StorageKey, &attachment.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Kinds of imported records
+const (
+	ImportKindTask = "task"
+	ImportKindUser = "user"
+)
+
+// ImportRepository remembers which row was created for each record imported
+// from another tool
+type ImportRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewImportRepository(db *pgxpool.Pool) *ImportRepository {
+	return &ImportRepository{db: db}
+}
+
+// Lookup returns the ID of the row created for an external record, or "" if
+// it has not been imported yet
+func (r *ImportRepository) Lookup(ctx context.Context, source, kind, externalID string) (string, error) {
+	query := `
+		SELECT internal_id::text FROM import_mappings
+		WHERE source = $1 AND kind = $2 AND external_id = $3
+	`
+
+	var id string
+	err := conn(ctx, r.db).QueryRow(ctx, query, source, kind, externalID).Scan(&id)
+	if err == pgx.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to look up import mapping: %w", err)
+	}
+
+	return id, nil
+}
+
+// Record links an external record to the row created for it
+func (r *ImportRepository) Record(ctx context.Context, source, kind, externalID, internalID string) error {
+	query := `
+		INSERT INTO import_mappings (source, kind, external_id, internal_id)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (source, kind, external_id) DO UPDATE SET internal_id = EXCLUDED.internal_id
+	`
+
+	if _, err := conn(ctx, r.db).Exec(ctx, query, source, kind, externalID, internalID); err != nil {
+		return fmt.Errorf("failed to record import mapping: %w", err)
+	}
+
+	return nil
+}
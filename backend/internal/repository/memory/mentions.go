@@ -0,0 +1,84 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"taskboard/internal/models"
+)
+
+// MentionStore is the in-memory counterpart of repository.MentionRepository
+type MentionStore struct {
+	s *Store
+}
+
+// Sync makes userIDs the users taskID mentions, keeping the mentions that
+// remain, and returns the IDs of the users newly mentioned
+func (r *MentionStore) Sync(ctx context.Context, taskID, mentionedByID string, userIDs []string) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.tasks[taskID]; !ok {
+		return nil, fmt.Errorf("failed to save mentions: task %s does not exist", taskID)
+	}
+	for _, id := range append([]string{mentionedByID}, userIDs...) {
+		if _, ok := r.s.users[id]; !ok {
+			return nil, fmt.Errorf("failed to save mentions: user %s does not exist", id)
+		}
+	}
+
+	wanted := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		wanted[id] = true
+	}
+
+	// Remaining mentions keep their place ahead of new ones. The slice is
+	// replaced rather than changed in place, so snapshots are unaffected.
+	var next []*models.Mention
+	kept := make(map[string]bool)
+	for _, mention := range r.s.mentions[taskID] {
+		if wanted[mention.UserID] {
+			next = append(next, mention)
+			kept[mention.UserID] = true
+		}
+	}
+
+	var added []string
+	now := time.Now()
+	for _, id := range userIDs {
+		if kept[id] {
+			continue
+		}
+		kept[id] = true
+		next = append(next, &models.Mention{TaskID: taskID, UserID: id, MentionedByID: mentionedByID, CreatedAt: now})
+		added = append(added, id)
+	}
+	r.s.mentions[taskID] = next
+
+	return added, nil
+}
+
+// ListForTask returns the mentions in taskID's description, oldest first
+func (r *MentionStore) ListForTask(ctx context.Context, taskID string) ([]*models.Mention, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var list []*models.Mention
+	for _, mention := range r.s.mentions[taskID] {
+		copied := *mention
+		list = append(list, &copied)
+	}
+
+	return list, nil
+}
+
+// mentioning reports whether taskID mentions userID; s.mu must be held
+func (s *Store) mentioning(taskID, userID string) bool {
+	for _, mention := range s.mentions[taskID] {
+		if mention.UserID == userID {
+			return true
+		}
+	}
+	return false
+}
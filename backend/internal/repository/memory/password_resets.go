@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// PasswordResetStore is the in-memory counterpart of
+// repository.PasswordResetRepository
+type PasswordResetStore struct {
+	s *Store
+}
+
+func (r *PasswordResetStore) Create(ctx context.Context, reset *models.PasswordReset) (*models.PasswordReset, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[reset.UserID]; !ok {
+		return nil, fmt.Errorf("failed to create password reset: user %s does not exist", reset.UserID)
+	}
+	if _, ok := r.s.passwordResets[reset.TokenHash]; ok {
+		return nil, fmt.Errorf("failed to create password reset: duplicate token hash")
+	}
+
+	reset.ID = uuid.New().String()
+	reset.CreatedAt = time.Now()
+
+	copied := *reset
+	r.s.passwordResets[reset.TokenHash] = &copied
+
+	return reset, nil
+}
+
+// Use spends the token with tokenHash, along with every other unused token of
+// the same user, and returns it
+func (r *PasswordResetStore) Use(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	reset, ok := r.s.passwordResets[tokenHash]
+	if !ok || reset.UsedAt != nil || !reset.ExpiresAt.After(now) {
+		return nil, repository.ErrPasswordResetInvalid
+	}
+
+	var used *models.PasswordReset
+	for hash, other := range r.s.passwordResets {
+		if other.UserID != reset.UserID || other.UsedAt != nil {
+			continue
+		}
+		next := *other
+		next.UsedAt = &now
+		r.s.passwordResets[hash] = &next
+		if hash == tokenHash {
+			used = &next
+		}
+	}
+
+	copied := *used
+	return &copied, nil
+}
+
+// PurgeExpired removes the tokens that expired before cutoff and returns how
+// many were removed
+func (r *PasswordResetStore) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for hash, reset := range r.s.passwordResets {
+		if reset.ExpiresAt.Before(cutoff) {
+			delete(r.s.passwordResets, hash)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
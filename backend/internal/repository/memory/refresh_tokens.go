@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// RefreshTokenStore is the in-memory counterpart of
+// repository.RefreshTokenRepository
+type RefreshTokenStore struct {
+	s *Store
+}
+
+func (r *RefreshTokenStore) Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[token.UserID]; !ok {
+		return nil, fmt.Errorf("failed to create refresh token: user %s does not exist", token.UserID)
+	}
+	if _, ok := r.s.sessions[token.FamilyID]; !ok {
+		return nil, fmt.Errorf("failed to create refresh token: session %s does not exist", token.FamilyID)
+	}
+	if _, ok := r.s.refreshTokens[token.TokenHash]; ok {
+		return nil, fmt.Errorf("failed to create refresh token: duplicate token hash")
+	}
+
+	token.ID = uuid.New().String()
+	token.CreatedAt = time.Now()
+
+	copied := *token
+	r.s.refreshTokens[token.TokenHash] = &copied
+
+	return token, nil
+}
+
+func (r *RefreshTokenStore) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token, ok := r.s.refreshTokens[tokenHash]
+	if !ok {
+		return nil, fmt.Errorf("refresh token not found")
+	}
+
+	copied := *token
+	return &copied, nil
+}
+
+// Use marks the token with tokenHash as exchanged, revoking its family if it
+// already was
+func (r *RefreshTokenStore) Use(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	token, ok := r.s.refreshTokens[tokenHash]
+	if !ok {
+		return nil, repository.ErrRefreshTokenInvalid
+	}
+	if token.UsedAt != nil {
+		r.s.revokeRefreshTokens(func(t *models.RefreshToken) bool { return t.FamilyID == token.FamilyID })
+		return nil, repository.ErrRefreshTokenReused
+	}
+	now := time.Now()
+	if token.RevokedAt != nil || !token.ExpiresAt.After(now) {
+		return nil, repository.ErrRefreshTokenInvalid
+	}
+
+	used := *token
+	used.UsedAt = &now
+	r.s.refreshTokens[tokenHash] = &used
+
+	copied := used
+	return &copied, nil
+}
+
+func (r *RefreshTokenStore) RevokeFamily(ctx context.Context, familyID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.revokeRefreshTokens(func(t *models.RefreshToken) bool { return t.FamilyID == familyID })
+	return nil
+}
+
+func (r *RefreshTokenStore) RevokeAllForUser(ctx context.Context, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	r.s.revokeRefreshTokens(func(t *models.RefreshToken) bool { return t.UserID == userID })
+	return nil
+}
+
+// PurgeExpired removes the tokens that expired before cutoff and returns how
+// many were removed
+func (r *RefreshTokenStore) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for hash, token := range r.s.refreshTokens {
+		if token.ExpiresAt.Before(cutoff) {
+			delete(r.s.refreshTokens, hash)
+			purged++
+		}
+	}
+
+	return purged, nil
+}
+
+// revokeRefreshTokens revokes the unrevoked tokens match selects, replacing
+// rather than changing them so snapshots are unaffected; s.mu must be held
+func (s *Store) revokeRefreshTokens(match func(*models.RefreshToken) bool) {
+	now := time.Now()
+	for hash, token := range s.refreshTokens {
+		if token.RevokedAt != nil || !match(token) {
+			continue
+		}
+		revoked := *token
+		revoked.RevokedAt = &now
+		s.refreshTokens[hash] = &revoked
+	}
+}
@@ -0,0 +1,151 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+)
+
+// SessionStore is the in-memory counterpart of repository.SessionRepository
+type SessionStore struct {
+	s *Store
+}
+
+func (r *SessionStore) Create(ctx context.Context, session *models.Session) (*models.Session, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[session.UserID]; !ok {
+		return nil, fmt.Errorf("failed to create session: user %s does not exist", session.UserID)
+	}
+
+	session.ID = uuid.New().String()
+	session.CreatedAt = time.Now()
+	session.LastSeenAt = session.CreatedAt
+
+	copied := *session
+	r.s.sessions[session.ID] = &copied
+
+	return session, nil
+}
+
+func (r *SessionStore) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	session, ok := r.s.sessions[id]
+	if !ok {
+		return nil, fmt.Errorf("session not found")
+	}
+
+	copied := *session
+	return &copied, nil
+}
+
+// ListActive returns userID's sessions that are not revoked and were seen
+// since seenSince, most recently seen first
+func (r *SessionStore) ListActive(ctx context.Context, userID string, seenSince time.Time) ([]*models.Session, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var sessions []*models.Session
+	for _, session := range r.s.sessions {
+		if session.UserID != userID || session.RevokedAt != nil || session.LastSeenAt.Before(seenSince) {
+			continue
+		}
+		copied := *session
+		sessions = append(sessions, &copied)
+	}
+
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].LastSeenAt.Equal(sessions[j].LastSeenAt) {
+			return sessions[i].LastSeenAt.After(sessions[j].LastSeenAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+
+	return sessions, nil
+}
+
+func (r *SessionStore) Touch(ctx context.Context, id, userAgent, ipAddress string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	session, ok := r.s.sessions[id]
+	if !ok {
+		return nil
+	}
+
+	touched := *session
+	touched.LastSeenAt = time.Now()
+	touched.UserAgent = userAgent
+	touched.IPAddress = ipAddress
+	r.s.sessions[id] = &touched
+
+	return nil
+}
+
+func (r *SessionStore) Revoke(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if session, ok := r.s.sessions[id]; ok && session.RevokedAt == nil {
+		r.s.revokeSession(session)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser ends every session of userID and returns the IDs of the
+// sessions it ended
+func (r *SessionStore) RevokeAllForUser(ctx context.Context, userID string) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var ids []string
+	for _, session := range r.s.sessions {
+		if session.UserID == userID && session.RevokedAt == nil {
+			r.s.revokeSession(session)
+			ids = append(ids, session.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+// PurgeInactive removes the sessions last seen before cutoff, with their
+// refresh tokens, and returns how many were removed
+func (r *SessionStore) PurgeInactive(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, session := range r.s.sessions {
+		if !session.LastSeenAt.Before(cutoff) {
+			continue
+		}
+		delete(r.s.sessions, id)
+		purged++
+
+		// Like the refresh_tokens.family_id foreign key's ON DELETE CASCADE
+		for hash, token := range r.s.refreshTokens {
+			if token.FamilyID == id {
+				delete(r.s.refreshTokens, hash)
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+// revokeSession replaces session with a revoked copy; s.mu must be held
+func (s *Store) revokeSession(session *models.Session) {
+	now := time.Now()
+	revoked := *session
+	revoked.RevokedAt = &now
+	s.sessions[session.ID] = &revoked
+}
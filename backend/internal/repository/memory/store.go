@@ -0,0 +1,213 @@
+// Package memory implements the repository store interfaces in process
+// memory. Filtering, ordering and errors match the Postgres repositories, so
+// code written against the interfaces can be tested without a database.
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// Store holds users, tasks, watchers, mentions, saved views, sessions,
+// refresh tokens and password resets. Stored records are never modified in place: every write
+// replaces the record, so snapshots only need to copy the maps.
+type Store struct {
+	mu    sync.Mutex
+	users map[string]*models.User
+	tasks map[string]*models.Task
+	views map[string]*models.SavedView
+	// watchers maps task IDs to their watchers' user IDs, in the order they
+	// started watching
+	watchers map[string][]string
+	// mentions maps task IDs to the mentions in their description, oldest
+	// first
+	mentions map[string][]*models.Mention
+	// sessions maps session IDs to sessions
+	sessions map[string]*models.Session
+	// refreshTokens maps token hashes to refresh tokens
+	refreshTokens map[string]*models.RefreshToken
+	// passwordResets maps token hashes to password resets
+	passwordResets map[string]*models.PasswordReset
+	// calendarTokens maps user IDs to their calendar feed token hash
+	calendarTokens map[string]string
+	// seq orders records created within the same clock tick
+	seq  map[string]int
+	next int
+}
+
+var (
+	_ repository.UserStore          = (*UserStore)(nil)
+	_ repository.TaskStore          = (*TaskStore)(nil)
+	_ repository.ViewStore          = (*ViewStore)(nil)
+	_ repository.WatcherStore       = (*WatcherStore)(nil)
+	_ repository.MentionStore       = (*MentionStore)(nil)
+	_ repository.RefreshTokenStore  = (*RefreshTokenStore)(nil)
+	_ repository.SessionStore       = (*SessionStore)(nil)
+	_ repository.PasswordResetStore = (*PasswordResetStore)(nil)
+	_ repository.TxRunner           = (*Store)(nil)
+)
+
+func New() *Store {
+	return &Store{
+		users:          make(map[string]*models.User),
+		tasks:          make(map[string]*models.Task),
+		views:          make(map[string]*models.SavedView),
+		watchers:       make(map[string][]string),
+		mentions:       make(map[string][]*models.Mention),
+		sessions:       make(map[string]*models.Session),
+		refreshTokens:  make(map[string]*models.RefreshToken),
+		passwordResets: make(map[string]*models.PasswordReset),
+		calendarTokens: make(map[string]string),
+		seq:            make(map[string]int),
+	}
+}
+
+// Users returns the store's UserStore
+func (s *Store) Users() *UserStore {
+	return &UserStore{s: s}
+}
+
+// Tasks returns the store's TaskStore
+func (s *Store) Tasks() *TaskStore {
+	return &TaskStore{s: s}
+}
+
+// Views returns the store's ViewStore
+func (s *Store) Views() *ViewStore {
+	return &ViewStore{s: s}
+}
+
+// Watchers returns the store's WatcherStore
+func (s *Store) Watchers() *WatcherStore {
+	return &WatcherStore{s: s}
+}
+
+// Mentions returns the store's MentionStore
+func (s *Store) Mentions() *MentionStore {
+	return &MentionStore{s: s}
+}
+
+// Sessions returns the store's SessionStore
+func (s *Store) Sessions() *SessionStore {
+	return &SessionStore{s: s}
+}
+
+// RefreshTokens returns the store's RefreshTokenStore
+func (s *Store) RefreshTokens() *RefreshTokenStore {
+	return &RefreshTokenStore{s: s}
+}
+
+// PasswordResets returns the store's PasswordResetStore
+func (s *Store) PasswordResets() *PasswordResetStore {
+	return &PasswordResetStore{s: s}
+}
+
+// WithTx calls fn and undoes every change made while it ran if it returns an
+// error. Unlike a database transaction it is not isolated from concurrent
+// writers, which tests do not need.
+func (s *Store) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	s.mu.Lock()
+	saved := s.snapshot()
+	s.mu.Unlock()
+
+	if err := fn(ctx); err != nil {
+		s.mu.Lock()
+		s.restore(saved)
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+type snapshot struct {
+	users          map[string]*models.User
+	tasks          map[string]*models.Task
+	views          map[string]*models.SavedView
+	watchers       map[string][]string
+	mentions       map[string][]*models.Mention
+	sessions       map[string]*models.Session
+	refreshTokens  map[string]*models.RefreshToken
+	passwordResets map[string]*models.PasswordReset
+	calendarTokens map[string]string
+	seq            map[string]int
+}
+
+// snapshot copies the store's maps; s.mu must be held
+func (s *Store) snapshot() snapshot {
+	saved := snapshot{
+		users:          make(map[string]*models.User, len(s.users)),
+		tasks:          make(map[string]*models.Task, len(s.tasks)),
+		views:          make(map[string]*models.SavedView, len(s.views)),
+		watchers:       make(map[string][]string, len(s.watchers)),
+		mentions:       make(map[string][]*models.Mention, len(s.mentions)),
+		sessions:       make(map[string]*models.Session, len(s.sessions)),
+		refreshTokens:  make(map[string]*models.RefreshToken, len(s.refreshTokens)),
+		passwordResets: make(map[string]*models.PasswordReset, len(s.passwordResets)),
+		calendarTokens: make(map[string]string, len(s.calendarTokens)),
+		seq:            make(map[string]int, len(s.seq)),
+	}
+	for id, user := range s.users {
+		saved.users[id] = user
+	}
+	for id, task := range s.tasks {
+		saved.tasks[id] = task
+	}
+	for id, view := range s.views {
+		saved.views[id] = view
+	}
+	for id, userIDs := range s.watchers {
+		saved.watchers[id] = userIDs
+	}
+	for id, taskMentions := range s.mentions {
+		saved.mentions[id] = taskMentions
+	}
+	for id, session := range s.sessions {
+		saved.sessions[id] = session
+	}
+	for hash, token := range s.refreshTokens {
+		saved.refreshTokens[hash] = token
+	}
+	for hash, reset := range s.passwordResets {
+		saved.passwordResets[hash] = reset
+	}
+	for id, hash := range s.calendarTokens {
+		saved.calendarTokens[id] = hash
+	}
+	for id, n := range s.seq {
+		saved.seq[id] = n
+	}
+	return saved
+}
+
+// restore puts back a snapshot; s.mu must be held
+func (s *Store) restore(saved snapshot) {
+	s.users = saved.users
+	s.tasks = saved.tasks
+	s.views = saved.views
+	s.watchers = saved.watchers
+	s.mentions = saved.mentions
+	s.sessions = saved.sessions
+	s.refreshTokens = saved.refreshTokens
+	s.passwordResets = saved.passwordResets
+	s.calendarTokens = saved.calendarTokens
+	s.seq = saved.seq
+}
+
+// created records the creation order of a new record; s.mu must be held
+func (s *Store) created(id string) {
+	s.next++
+	s.seq[id] = s.next
+}
+
+// newer reports whether the record a sorts before b when ordering by
+// created_at DESC
+func (s *Store) newer(aID string, aCreated time.Time, bID string, bCreated time.Time) bool {
+	if !aCreated.Equal(bCreated) {
+		return aCreated.After(bCreated)
+	}
+	return s.seq[aID] > s.seq[bID]
+}
@@ -0,0 +1,837 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+	"taskboard/internal/recurrence"
+	"taskboard/internal/repository"
+)
+
+// TaskStore is the in-memory counterpart of repository.TaskRepository,
+// including the soft delete, archiving and completed_at rules the Postgres
+// schema enforces with columns and triggers
+type TaskStore struct {
+	s *Store
+}
+
+var (
+	taskStatuses   = map[string]bool{"TODO": true, "IN_PROGRESS": true, "REVIEW": true, "DONE": true}
+	taskPriorities = map[string]bool{"LOW": true, "MEDIUM": true, "HIGH": true, "URGENT": true}
+)
+
+func (r *TaskStore) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task.ID = uuid.New().String()
+
+	if task.RecurrenceRule != nil && task.SeriesID == nil {
+		task.SeriesID = &task.ID
+	}
+	if task.Occurrence == 0 {
+		task.Occurrence = 1
+	}
+
+	if err := r.insert(task); err != nil {
+		return nil, fmt.Errorf("failed to create task: %w", err)
+	}
+
+	return task, nil
+}
+
+// BulkCreate inserts tasks, assigning their IDs. Like the COPY it mirrors,
+// it only stores the columns a plain task has, and either every task is
+// inserted or none is.
+func (r *TaskStore) BulkCreate(ctx context.Context, tasks []*models.Task) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	saved := r.s.snapshot()
+	for _, task := range tasks {
+		task.ID = uuid.New().String()
+		if task.Occurrence == 0 {
+			task.Occurrence = 1
+		}
+
+		if err := r.insert(&models.Task{
+			ID: task.ID, Title: task.Title, Description: copyString(task.Description),
+			Status: task.Status, Priority: task.Priority, CreatedByID: task.CreatedByID,
+			AssignedToID: copyString(task.AssignedToID), DueDate: copyTime(task.DueDate), Occurrence: task.Occurrence,
+		}); err != nil {
+			r.s.restore(saved)
+			return 0, fmt.Errorf("failed to copy tasks: %w", err)
+		}
+	}
+
+	return int64(len(tasks)), nil
+}
+
+// insert stores a new task, filling in the columns Postgres defaults; s.mu
+// must be held
+func (r *TaskStore) insert(task *models.Task) error {
+	if err := r.validate(task); err != nil {
+		return err
+	}
+	if task.SeriesID != nil {
+		for _, existing := range r.s.tasks {
+			if existing.SeriesID != nil && *existing.SeriesID == *task.SeriesID && existing.Occurrence == task.Occurrence {
+				return errDuplicateOccurrence
+			}
+		}
+	}
+
+	now := time.Now()
+	task.CreatedAt = now
+	task.UpdatedAt = now
+	task.CompletedAt = nil
+	task.ArchivedAt = nil
+	task.DeletedAt = nil
+	if task.Status == "DONE" {
+		task.CompletedAt = &now
+	}
+
+	r.s.tasks[task.ID] = copyTask(task)
+	r.s.created(task.ID)
+
+	// Like the watch_tasks_participants trigger
+	r.s.watch(task.ID, task.CreatedByID)
+	if task.AssignedToID != nil {
+		r.s.watch(task.ID, *task.AssignedToID)
+	}
+	return nil
+}
+
+var errDuplicateOccurrence = fmt.Errorf("occurrence already exists in its series")
+
+// validate applies the table's check and foreign key constraints; s.mu must
+// be held
+func (r *TaskStore) validate(task *models.Task) error {
+	if !taskStatuses[task.Status] {
+		return fmt.Errorf("invalid status %q", task.Status)
+	}
+	if !taskPriorities[task.Priority] {
+		return fmt.Errorf("invalid priority %q", task.Priority)
+	}
+	if _, ok := r.s.users[task.CreatedByID]; !ok {
+		return fmt.Errorf("creator %s does not exist", task.CreatedByID)
+	}
+	if task.AssignedToID != nil {
+		if _, ok := r.s.users[*task.AssignedToID]; !ok {
+			return fmt.Errorf("assignee %s does not exist", *task.AssignedToID)
+		}
+	}
+	if task.StoryPoints != nil && *task.StoryPoints < 0 {
+		return fmt.Errorf("story points must not be negative")
+	}
+	if task.EstimatedHours != nil && *task.EstimatedHours < 0 {
+		return fmt.Errorf("estimated hours must not be negative")
+	}
+	return nil
+}
+
+func (r *TaskStore) GetByID(ctx context.Context, id string) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return copyTask(task), nil
+}
+
+func (r *TaskStore) List(ctx context.Context, filter map[string]interface{}) ([]*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	tasks := r.matching(filter)
+
+	if offset, ok := filter["offset"].(int); ok && offset > 0 {
+		if offset >= len(tasks) {
+			return nil, nil
+		}
+		tasks = tasks[offset:]
+	}
+
+	if limit, ok := filter["limit"].(int); ok && limit > 0 && limit < len(tasks) {
+		tasks = tasks[:limit]
+	}
+
+	return tasks, nil
+}
+
+// Count returns the number of tasks matching filter, ignoring limit and offset
+func (r *TaskStore) Count(ctx context.Context, filter map[string]interface{}) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return len(r.matching(filter)), nil
+}
+
+// Export calls fn for every task matching filter, newest first, with creator
+// and assignee resolved. fn is called after the store is unlocked.
+func (r *TaskStore) Export(ctx context.Context, filter map[string]interface{}, fn func(*models.TaskExport) error) error {
+	r.s.mu.Lock()
+	tasks := r.matching(filter)
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].CreatedAt.After(tasks[j].CreatedAt)
+	})
+
+	rows := make([]*models.TaskExport, len(tasks))
+	for i, task := range tasks {
+		creator := r.s.users[task.CreatedByID]
+		row := &models.TaskExport{
+			ID: task.ID, Title: task.Title, Description: task.Description,
+			Status: task.Status, Priority: task.Priority,
+			CreatorName: creator.Name, CreatorEmail: creator.Email,
+			DueDate: task.DueDate, CreatedAt: task.CreatedAt, UpdatedAt: task.UpdatedAt, CompletedAt: task.CompletedAt,
+		}
+		if task.AssignedToID != nil {
+			if assignee, ok := r.s.users[*task.AssignedToID]; ok {
+				row.AssigneeName = copyString(&assignee.Name)
+				row.AssigneeEmail = copyString(&assignee.Email)
+			}
+		}
+		rows[i] = row
+	}
+	r.s.mu.Unlock()
+
+	for _, row := range rows {
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// matching returns copies of the tasks matching filter, sorted. Like
+// buildTaskFilter, deleted tasks never match, archived tasks only match
+// when include_archived is true, and watched_by and mentioned limit the
+// tasks to those a user watches or is mentioned in. s.mu must be held.
+func (r *TaskStore) matching(filter map[string]interface{}) []*models.Task {
+	includeArchived, _ := filter["include_archived"].(bool)
+	watchedBy, watched := filter["watched_by"].(string)
+	mentionedID, mentioned := filter["mentioned"].(string)
+	now := time.Now()
+
+	var tasks []*models.Task
+	for _, task := range r.s.tasks {
+		if task.DeletedAt != nil || (task.ArchivedAt != nil && !includeArchived) {
+			continue
+		}
+		if watched && !r.s.watching(task.ID, watchedBy) {
+			continue
+		}
+		if mentioned && !r.s.mentioning(task.ID, mentionedID) {
+			continue
+		}
+		if !matchesFilter(task, filter, now) {
+			continue
+		}
+
+		tasks = append(tasks, copyTask(task))
+	}
+
+	r.sortTasks(tasks, filter)
+	return tasks
+}
+
+// matchesFilter reports whether task satisfies every condition in filter,
+// as taskPredicate compiles them. A comparison with a missing due date
+// fails, so its negation matches.
+func matchesFilter(task *models.Task, filter map[string]interface{}, now time.Time) bool {
+	assignedToID := ""
+	if task.AssignedToID != nil {
+		assignedToID = *task.AssignedToID
+	}
+	if !matchesColumn(filter, "status", task.Status) ||
+		!matchesColumn(filter, "priority", task.Priority) ||
+		!matchesColumn(filter, "created_by_id", task.CreatedByID) ||
+		!matchesColumn(filter, "assigned_to_id", assignedToID) {
+		return false
+	}
+
+	if !matchesAny(filter, "status_in", task.Status) || !matchesAny(filter, "priority_in", task.Priority) {
+		return false
+	}
+
+	if before, ok := filter["due_before"].(time.Time); ok && (task.DueDate == nil || !task.DueDate.Before(before)) {
+		return false
+	}
+	if after, ok := filter["due_after"].(time.Time); ok && (task.DueDate == nil || !task.DueDate.After(after)) {
+		return false
+	}
+	if after, ok := filter["created_after"].(time.Time); ok && !task.CreatedAt.After(after) {
+		return false
+	}
+	if since, ok := filter["updated_since"].(time.Time); ok && task.UpdatedAt.Before(since) {
+		return false
+	}
+
+	if overdue, ok := filter["overdue"].(bool); ok {
+		isOverdue := task.DueDate != nil && task.DueDate.Before(now) && task.Status != "DONE"
+		if isOverdue != overdue {
+			return false
+		}
+	}
+	if unassigned, ok := filter["unassigned"].(bool); ok && (task.AssignedToID == nil) != unassigned {
+		return false
+	}
+
+	if title, ok := filter["title_contains"].(string); ok && title != "" &&
+		!strings.Contains(strings.ToLower(task.Title), strings.ToLower(title)) {
+		return false
+	}
+
+	if subs, ok := filter["and"].([]map[string]interface{}); ok {
+		for _, sub := range subs {
+			if !matchesFilter(task, sub, now) {
+				return false
+			}
+		}
+	}
+	if subs, ok := filter["or"].([]map[string]interface{}); ok && len(subs) > 0 {
+		matched := false
+		for _, sub := range subs {
+			if matchesFilter(task, sub, now) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if not, ok := filter["not"].(map[string]interface{}); ok && matchesFilter(task, not, now) {
+		return false
+	}
+
+	return true
+}
+
+// matchesColumn reports whether value satisfies the equality filter on
+// column. Missing and empty filters match everything.
+func matchesColumn(filter map[string]interface{}, column, value string) bool {
+	want, ok := filter[column].(string)
+	return !ok || want == "" || want == value
+}
+
+// matchesAny reports whether value is one of the values in the set filter
+// under key. Missing and empty sets match everything.
+func matchesAny(filter map[string]interface{}, key, value string) bool {
+	values, ok := filter[key].([]string)
+	if !ok || len(values) == 0 {
+		return true
+	}
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+var priorityRanks = map[string]int{"LOW": 1, "MEDIUM": 2, "HIGH": 3, "URGENT": 4}
+
+// sortTasks orders tasks like taskOrder: by filter["sort"] with missing
+// values last and ties broken by ID, or newest first by default
+func (r *TaskStore) sortTasks(tasks []*models.Task, filter map[string]interface{}) {
+	field, _ := filter["sort"].(string)
+	desc, _ := filter["sort_desc"].(bool)
+
+	switch field {
+	case "created_at", "updated_at", "due_date", "priority", "title":
+	default:
+		sort.Slice(tasks, func(i, j int) bool {
+			return r.s.newer(tasks[i].ID, tasks[i].CreatedAt, tasks[j].ID, tasks[j].CreatedAt)
+		})
+		return
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if field == "due_date" && (a.DueDate == nil) != (b.DueDate == nil) {
+			return a.DueDate != nil
+		}
+		if c := compareTasks(field, a, b); c != 0 {
+			return (c < 0) != desc
+		}
+		return a.ID < b.ID
+	})
+}
+
+// compareTasks returns a negative number, zero or a positive number as a
+// sorts before, with or after b on field in ascending order
+func compareTasks(field string, a, b *models.Task) int {
+	switch field {
+	case "created_at":
+		return compareTimes(a.CreatedAt, b.CreatedAt)
+	case "updated_at":
+		return compareTimes(a.UpdatedAt, b.UpdatedAt)
+	case "due_date":
+		if a.DueDate == nil || b.DueDate == nil {
+			return 0
+		}
+		return compareTimes(*a.DueDate, *b.DueDate)
+	case "priority":
+		return priorityRanks[a.Priority] - priorityRanks[b.Priority]
+	case "title":
+		return strings.Compare(strings.ToLower(a.Title), strings.ToLower(b.Title))
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+func (r *TaskStore) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	updated, err := r.update(task, updates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update task: %w", err)
+	}
+
+	return updated, nil
+}
+
+// update applies updates the way buildTaskUpdate and the completed_at trigger
+// do, and returns a copy of the result; s.mu must be held
+func (r *TaskStore) update(task *models.Task, updates map[string]interface{}) (*models.Task, error) {
+	next := copyTask(task)
+
+	if title, ok := updates["title"].(string); ok {
+		next.Title = title
+	}
+	if description, ok := updates["description"]; ok {
+		next.Description = stringPtr(description)
+	}
+	if status, ok := updates["status"].(string); ok {
+		next.Status = status
+		// Reopening an archived task brings it back into task lists
+		if status != "DONE" {
+			next.ArchivedAt = nil
+		}
+	}
+	if priority, ok := updates["priority"].(string); ok {
+		next.Priority = priority
+	}
+	if assignedToID, ok := updates["assigned_to_id"]; ok {
+		next.AssignedToID = stringPtr(assignedToID)
+	}
+	if dueDate, ok := updates["due_date"]; ok {
+		next.DueDate = timePtr(dueDate)
+	}
+	if storyPoints, ok := updates["story_points"]; ok {
+		next.StoryPoints = intPtr(storyPoints)
+	}
+	if estimatedHours, ok := updates["estimated_hours"]; ok {
+		next.EstimatedHours = floatPtr(estimatedHours)
+	}
+	if rule, ok := updates["recurrence_rule"]; ok {
+		// A task that starts recurring becomes the head of its own series
+		next.RecurrenceRule = stringPtr(rule)
+		if next.SeriesID == nil {
+			next.SeriesID = copyString(&next.ID)
+		}
+	}
+
+	if err := r.validate(next); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	next.UpdatedAt = now
+	if next.Status == "DONE" && task.Status != "DONE" {
+		next.CompletedAt = &now
+	} else if next.Status != "DONE" {
+		next.CompletedAt = nil
+	}
+
+	r.s.tasks[next.ID] = next
+	if next.AssignedToID != nil && (task.AssignedToID == nil || *task.AssignedToID != *next.AssignedToID) {
+		r.s.watch(next.ID, *next.AssignedToID)
+	}
+	return copyTask(next), nil
+}
+
+// Delete moves a task to the trash
+func (r *TaskStore) Delete(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return fmt.Errorf("task not found")
+	}
+
+	r.softDelete(task)
+	return nil
+}
+
+// softDelete sets deleted_at on a stored task; s.mu must be held
+func (r *TaskStore) softDelete(task *models.Task) {
+	now := time.Now()
+	next := copyTask(task)
+	next.DeletedAt = &now
+	next.UpdatedAt = now
+	r.s.tasks[next.ID] = next
+}
+
+// BulkUpdate updates tasks as one unit. prepare receives each task and
+// returns the updates to apply to it or an error to reject it. Unless atomic
+// is set, rejected or failing tasks do not stop the others.
+func (r *TaskStore) BulkUpdate(ctx context.Context, ids []string, atomic bool, prepare func(*models.Task) (map[string]interface{}, error)) ([]*repository.BulkResult, error) {
+	return r.bulk(ids, atomic, func(task *models.Task) (*models.Task, error) {
+		updates, err := prepare(task)
+		if err != nil {
+			return nil, err
+		}
+
+		updated, err := r.update(task, updates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+		return updated, nil
+	})
+}
+
+// BulkDelete moves tasks to the trash as one unit. check receives each task
+// and returns an error to reject it.
+func (r *TaskStore) BulkDelete(ctx context.Context, ids []string, atomic bool, check func(*models.Task) error) ([]*repository.BulkResult, error) {
+	return r.bulk(ids, atomic, func(task *models.Task) (*models.Task, error) {
+		if err := check(task); err != nil {
+			return nil, err
+		}
+
+		r.softDelete(task)
+		return nil, nil
+	})
+}
+
+// bulk applies fn to each distinct task in ids while holding the lock. A
+// failing task's changes are undone on their own; in atomic mode any failure
+// undoes the whole batch.
+func (r *TaskStore) bulk(ids []string, atomic bool, fn func(*models.Task) (*models.Task, error)) ([]*repository.BulkResult, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	batch := r.s.snapshot()
+	var results []*repository.BulkResult
+	seen := make(map[string]bool, len(ids))
+	failed := false
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		result := &repository.BulkResult{ID: id}
+		results = append(results, result)
+
+		task, ok := r.s.tasks[id]
+		if !ok || task.DeletedAt != nil {
+			result.Err = fmt.Errorf("task not found")
+			failed = true
+			continue
+		}
+		result.Before = copyTask(task)
+
+		item := r.s.snapshot()
+		result.Task, result.Err = fn(copyTask(task))
+		if result.Err != nil {
+			r.s.restore(item)
+			result.Task = nil
+			failed = true
+		}
+	}
+
+	if atomic && failed {
+		r.s.restore(batch)
+		for _, result := range results {
+			if result.Err == nil {
+				result.Task, result.Err = nil, repository.ErrBulkRolledBack
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// GetDeleted returns a task that is in the trash
+func (r *TaskStore) GetDeleted(ctx context.Context, id string) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt == nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return copyTask(task), nil
+}
+
+// ListDeleted returns the tasks created by userID that are in the trash,
+// most recently deleted first
+func (r *TaskStore) ListDeleted(ctx context.Context, userID string) ([]*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var tasks []*models.Task
+	for _, task := range r.s.tasks {
+		if task.CreatedByID == userID && task.DeletedAt != nil {
+			tasks = append(tasks, copyTask(task))
+		}
+	}
+
+	sort.SliceStable(tasks, func(i, j int) bool {
+		return tasks[i].DeletedAt.After(*tasks[j].DeletedAt)
+	})
+
+	return tasks, nil
+}
+
+// Restore takes a task out of the trash
+func (r *TaskStore) Restore(ctx context.Context, id string) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt == nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	next := copyTask(task)
+	next.DeletedAt = nil
+	next.UpdatedAt = time.Now()
+	r.s.tasks[id] = next
+
+	return copyTask(next), nil
+}
+
+// Archive hides a task from task lists without deleting it
+func (r *TaskStore) Archive(ctx context.Context, id string) (*models.Task, error) {
+	return r.setArchived(id, true)
+}
+
+// Unarchive brings an archived task back into task lists
+func (r *TaskStore) Unarchive(ctx context.Context, id string) (*models.Task, error) {
+	return r.setArchived(id, false)
+}
+
+func (r *TaskStore) setArchived(id string, archived bool) (*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	now := time.Now()
+	next := copyTask(task)
+	next.UpdatedAt = now
+	if !archived {
+		next.ArchivedAt = nil
+	} else if next.ArchivedAt == nil {
+		next.ArchivedAt = &now
+	}
+	r.s.tasks[id] = next
+
+	return copyTask(next), nil
+}
+
+// ArchiveCompleted archives every task completed before cutoff and returns
+// how many were archived
+func (r *TaskStore) ArchiveCompleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	var archived int64
+	for id, task := range r.s.tasks {
+		if task.Status != "DONE" || task.CompletedAt == nil || !task.CompletedAt.Before(cutoff) ||
+			task.ArchivedAt != nil || task.DeletedAt != nil {
+			continue
+		}
+		next := copyTask(task)
+		next.ArchivedAt = &now
+		next.UpdatedAt = now
+		r.s.tasks[id] = next
+		archived++
+	}
+
+	return archived, nil
+}
+
+// PurgeDeleted permanently removes the tasks deleted before cutoff, with their
+// watchers and mentions, and returns how many were removed. The store keeps
+// no attachments, so there are never storage keys to return.
+func (r *TaskStore) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, []string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var purged int64
+	for id, task := range r.s.tasks {
+		if task.DeletedAt == nil || !task.DeletedAt.Before(cutoff) {
+			continue
+		}
+		delete(r.s.tasks, id)
+		delete(r.s.watchers, id)
+		delete(r.s.mentions, id)
+		purged++
+	}
+
+	return purged, nil, nil
+}
+
+func (r *TaskStore) GetByUserID(ctx context.Context, userID string) ([]*models.Task, error) {
+	return r.List(ctx, map[string]interface{}{
+		"created_by_id": userID,
+	})
+}
+
+func (r *TaskStore) GetAssignedToUser(ctx context.Context, userID string) ([]*models.Task, error) {
+	return r.List(ctx, map[string]interface{}{
+		"assigned_to_id": userID,
+	})
+}
+
+// SpawnNextOccurrence creates the task following task in its recurrence series,
+// due at the first occurrence of the series rule that is still in the future.
+// It returns nil when the series has ended or a later occurrence already
+// exists, even in the trash.
+func (r *TaskStore) SpawnNextOccurrence(ctx context.Context, task *models.Task) (*models.Task, error) {
+	if task.RecurrenceRule == nil || task.SeriesID == nil || task.DueDate == nil {
+		return nil, nil
+	}
+
+	rule, err := recurrence.Parse(*task.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, occurrence, ok := rule.NextAfter(*task.DueDate, task.Occurrence, time.Now())
+	if !ok {
+		return nil, nil
+	}
+
+	next := &models.Task{
+		ID:             uuid.New().String(),
+		Title:          task.Title,
+		Description:    copyString(task.Description),
+		Status:         "TODO",
+		Priority:       task.Priority,
+		CreatedByID:    task.CreatedByID,
+		AssignedToID:   copyString(task.AssignedToID),
+		DueDate:        &dueDate,
+		RecurrenceRule: copyString(task.RecurrenceRule),
+		SeriesID:       copyString(task.SeriesID),
+		Occurrence:     occurrence,
+		StoryPoints:    copyInt(task.StoryPoints),
+		EstimatedHours: copyFloat(task.EstimatedHours),
+	}
+
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if r.hasLaterOccurrence(*task.SeriesID, task.Occurrence) {
+		return nil, nil
+	}
+
+	err = r.insert(next)
+	if err == errDuplicateOccurrence {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	return next, nil
+}
+
+// StopRecurrence ends a series. Existing occurrences are kept but no further
+// ones will be generated.
+func (r *TaskStore) StopRecurrence(ctx context.Context, seriesID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := time.Now()
+	for id, task := range r.s.tasks {
+		if task.SeriesID != nil && *task.SeriesID == seriesID {
+			next := copyTask(task)
+			next.RecurrenceRule = nil
+			next.UpdatedAt = now
+			r.s.tasks[id] = next
+		}
+	}
+
+	return nil
+}
+
+// ListRecurrenceDue returns the latest occurrence of every active series whose
+// due date has passed, i.e. the tasks whose successor should now be scheduled
+func (r *TaskStore) ListRecurrenceDue(ctx context.Context, now time.Time) ([]*models.Task, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var due []*models.Task
+	for _, task := range r.s.tasks {
+		if task.RecurrenceRule == nil || task.DeletedAt != nil || task.DueDate == nil || task.DueDate.After(now) {
+			continue
+		}
+		if task.SeriesID != nil && r.hasLaterOccurrence(*task.SeriesID, task.Occurrence) {
+			continue
+		}
+		due = append(due, copyTask(task))
+	}
+
+	return due, nil
+}
+
+// hasLaterOccurrence reports whether the series has an occurrence after the
+// given one, even in the trash; s.mu must be held
+func (r *TaskStore) hasLaterOccurrence(seriesID string, occurrence int) bool {
+	for _, task := range r.s.tasks {
+		if task.SeriesID != nil && *task.SeriesID == seriesID && task.Occurrence > occurrence {
+			return true
+		}
+	}
+	return false
+}
+
+func copyTask(task *models.Task) *models.Task {
+	copied := *task
+	copied.Description = copyString(task.Description)
+	copied.AssignedToID = copyString(task.AssignedToID)
+	copied.DueDate = copyTime(task.DueDate)
+	copied.RecurrenceRule = copyString(task.RecurrenceRule)
+	copied.SeriesID = copyString(task.SeriesID)
+	copied.StoryPoints = copyInt(task.StoryPoints)
+	copied.EstimatedHours = copyFloat(task.EstimatedHours)
+	copied.CompletedAt = copyTime(task.CompletedAt)
+	copied.ArchivedAt = copyTime(task.ArchivedAt)
+	copied.DeletedAt = copyTime(task.DeletedAt)
+	return &copied
+}
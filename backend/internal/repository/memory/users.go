@@ -0,0 +1,259 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/mentions"
+	"taskboard/internal/models"
+)
+
+// UserStore is the in-memory counterpart of repository.UserRepository
+type UserStore struct {
+	s *Store
+}
+
+func (r *UserStore) Create(ctx context.Context, user *models.User) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.users {
+		if existing.Email == user.Email {
+			return nil, fmt.Errorf("failed to create user: email %s is already registered", user.Email)
+		}
+	}
+
+	user.ID = uuid.New().String()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+	// Avatars are only ever set by Update, and addresses only verified by
+	// MarkEmailVerified
+	user.Avatar = nil
+	user.EmailVerifiedAt = nil
+
+	stored := *user
+	r.s.users[user.ID] = &stored
+	r.s.created(user.ID)
+
+	return user, nil
+}
+
+func (r *UserStore) GetByID(ctx context.Context, id string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	return copyUser(user), nil
+}
+
+func (r *UserStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+
+	return nil, fmt.Errorf("user not found")
+}
+
+func (r *UserStore) List(ctx context.Context) ([]*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var users []*models.User
+	for _, user := range r.s.users {
+		users = append(users, copyUser(user))
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return r.s.newer(users[i].ID, users[i].CreatedAt, users[j].ID, users[j].CreatedAt)
+	})
+
+	return users, nil
+}
+
+// ListPage returns one page of users ordered like List
+func (r *UserStore) ListPage(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	users, err := r.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if offset >= len(users) {
+		return nil, nil
+	}
+	users = users[offset:]
+	if limit < len(users) {
+		users = users[:limit]
+	}
+
+	return users, nil
+}
+
+func (r *UserStore) Count(ctx context.Context) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return len(r.s.users), nil
+}
+
+func (r *UserStore) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return nil, fmt.Errorf("user not found")
+	}
+
+	next := *user
+	if name, ok := updates["name"].(string); ok {
+		next.Name = name
+	}
+	if avatar, ok := updates["avatar"]; ok {
+		next.Avatar = stringPtr(avatar)
+	}
+	next.UpdatedAt = time.Now()
+
+	r.s.users[id] = &next
+	return copyUser(&next), nil
+}
+
+func (r *UserStore) EmailExists(ctx context.Context, email string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, user := range r.s.users {
+		if user.Email == email {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// IDsByEmail returns the IDs of the users whose lowercased email is one of
+// emails, keyed by lowercased email
+func (r *UserStore) IDsByEmail(ctx context.Context, emails []string) (map[string]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	wanted := make(map[string]bool, len(emails))
+	for _, email := range emails {
+		wanted[email] = true
+	}
+
+	ids := make(map[string]string)
+	for _, user := range r.s.users {
+		if email := strings.ToLower(user.Email); wanted[email] {
+			ids[email] = user.ID
+		}
+	}
+
+	return ids, nil
+}
+
+// ListByMention returns the users whose lowercased email or handle is one of
+// names
+func (r *UserStore) ListByMention(ctx context.Context, names []string) ([]*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	var users []*models.User
+	for _, user := range r.s.users {
+		if wanted[strings.ToLower(user.Email)] || wanted[mentions.Handle(user.Email)] {
+			copied := *user
+			users = append(users, &copied)
+		}
+	}
+
+	return users, nil
+}
+
+// SetCalendarTokenHash replaces the user's calendar feed token
+func (r *UserStore) SetCalendarTokenHash(ctx context.Context, id, tokenHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[id]; !ok {
+		return fmt.Errorf("user not found")
+	}
+
+	r.s.calendarTokens[id] = tokenHash
+	return nil
+}
+
+// GetByCalendarTokenHash returns the owner of a calendar feed token, or nil if
+// no user has it
+func (r *UserStore) GetByCalendarTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for id, hash := range r.s.calendarTokens {
+		if hash == tokenHash {
+			return copyUser(r.s.users[id]), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// MarkEmailVerified records that userID confirmed they receive mail at email,
+// reporting false when it already was or is no longer the user's
+func (r *UserStore) MarkEmailVerified(ctx context.Context, id, email string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok || user.Email != email || user.EmailVerifiedAt != nil {
+		return false, nil
+	}
+
+	next := *user
+	now := time.Now()
+	next.EmailVerifiedAt = &now
+	next.UpdatedAt = now
+
+	r.s.users[id] = &next
+	return true, nil
+}
+
+// SetPasswordHash replaces the user's password hash
+func (r *UserStore) SetPasswordHash(ctx context.Context, id, passwordHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	user, ok := r.s.users[id]
+	if !ok {
+		return fmt.Errorf("user not found")
+	}
+
+	next := *user
+	next.PasswordHash = passwordHash
+	next.UpdatedAt = time.Now()
+
+	r.s.users[id] = &next
+	return nil
+}
+
+func copyUser(user *models.User) *models.User {
+	copied := *user
+	copied.Avatar = copyString(user.Avatar)
+	return &copied
+}
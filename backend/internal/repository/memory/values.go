@@ -0,0 +1,79 @@
+package memory
+
+import "time"
+
+// The update maps accepted by the repositories hold plain values, pointers or
+// nil for a column, as pgx does. These helpers turn any of them into the
+// pointer stored on the model.
+
+func stringPtr(value interface{}) *string {
+	switch v := value.(type) {
+	case string:
+		return &v
+	case *string:
+		return copyString(v)
+	}
+	return nil
+}
+
+func timePtr(value interface{}) *time.Time {
+	switch v := value.(type) {
+	case time.Time:
+		return &v
+	case *time.Time:
+		return copyTime(v)
+	}
+	return nil
+}
+
+func intPtr(value interface{}) *int {
+	switch v := value.(type) {
+	case int:
+		return &v
+	case *int:
+		return copyInt(v)
+	}
+	return nil
+}
+
+func floatPtr(value interface{}) *float64 {
+	switch v := value.(type) {
+	case float64:
+		return &v
+	case *float64:
+		return copyFloat(v)
+	}
+	return nil
+}
+
+func copyString(v *string) *string {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
+
+func copyTime(v *time.Time) *time.Time {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
+
+func copyInt(v *int) *int {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
+
+func copyFloat(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	copied := *v
+	return &copied
+}
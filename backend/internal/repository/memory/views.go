@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// ViewStore is the in-memory counterpart of repository.ViewRepository
+type ViewStore struct {
+	s *Store
+}
+
+func (r *ViewStore) Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.users[view.OwnerID]; !ok {
+		return nil, fmt.Errorf("failed to create view: owner %s does not exist", view.OwnerID)
+	}
+	if r.nameTaken(view.OwnerID, view.Name, "") {
+		return nil, repository.ErrViewNameTaken
+	}
+
+	view.ID = uuid.New().String()
+	view.CreatedAt = time.Now()
+	view.UpdatedAt = view.CreatedAt
+
+	r.s.views[view.ID] = copyView(view)
+	r.s.created(view.ID)
+
+	return view, nil
+}
+
+func (r *ViewStore) GetByID(ctx context.Context, id string) (*models.SavedView, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	view, ok := r.s.views[id]
+	if !ok {
+		return nil, fmt.Errorf("view not found")
+	}
+
+	return copyView(view), nil
+}
+
+// ListVisible returns the views userID owns and those other users share,
+// pinned views first and then by name
+func (r *ViewStore) ListVisible(ctx context.Context, userID string) ([]*models.SavedView, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var views []*models.SavedView
+	for _, view := range r.s.views {
+		if view.OwnerID == userID || view.Shared {
+			views = append(views, copyView(view))
+		}
+	}
+
+	sort.Slice(views, func(i, j int) bool {
+		a, b := views[i], views[j]
+		if a.Pinned != b.Pinned {
+			return a.Pinned
+		}
+		if c := strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name)); c != 0 {
+			return c < 0
+		}
+		return a.ID < b.ID
+	})
+
+	return views, nil
+}
+
+func (r *ViewStore) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.SavedView, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	view, ok := r.s.views[id]
+	if !ok {
+		return nil, fmt.Errorf("view not found")
+	}
+
+	next := copyView(view)
+	if name, ok := updates["name"].(string); ok {
+		if r.nameTaken(next.OwnerID, name, id) {
+			return nil, repository.ErrViewNameTaken
+		}
+		next.Name = name
+	}
+	if query, ok := updates["query"].([]byte); ok {
+		next.Query = append([]byte(nil), query...)
+	}
+	if shared, ok := updates["shared"].(bool); ok {
+		next.Shared = shared
+	}
+	if pinned, ok := updates["pinned"].(bool); ok {
+		next.Pinned = pinned
+	}
+	next.UpdatedAt = time.Now()
+
+	r.s.views[id] = next
+	return copyView(next), nil
+}
+
+func (r *ViewStore) Delete(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if _, ok := r.s.views[id]; !ok {
+		return fmt.Errorf("view not found")
+	}
+
+	delete(r.s.views, id)
+	return nil
+}
+
+// nameTaken applies the case-insensitive unique index on owner and name,
+// ignoring the view with ID except; s.mu must be held
+func (r *ViewStore) nameTaken(ownerID, name, except string) bool {
+	for id, view := range r.s.views {
+		if id != except && view.OwnerID == ownerID && strings.EqualFold(view.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyView(view *models.SavedView) *models.SavedView {
+	copied := *view
+	copied.Query = append([]byte(nil), view.Query...)
+	return &copied
+}
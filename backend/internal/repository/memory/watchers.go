@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+
+	"taskboard/internal/models"
+)
+
+// WatcherStore is the in-memory counterpart of repository.WatcherRepository.
+// TaskStore adds creators and assignees as the Postgres trigger does.
+type WatcherStore struct {
+	s *Store
+}
+
+func (r *WatcherStore) Watch(ctx context.Context, taskID, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	task, ok := r.s.tasks[taskID]
+	if !ok || task.DeletedAt != nil {
+		return fmt.Errorf("task not found")
+	}
+	if _, ok := r.s.users[userID]; !ok {
+		return fmt.Errorf("failed to watch task: user %s does not exist", userID)
+	}
+
+	r.s.watch(taskID, userID)
+	return nil
+}
+
+func (r *WatcherStore) Unwatch(ctx context.Context, taskID, userID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var remaining []string
+	for _, id := range r.s.watchers[taskID] {
+		if id != userID {
+			remaining = append(remaining, id)
+		}
+	}
+	r.s.watchers[taskID] = remaining
+
+	return nil
+}
+
+// ListWatchers returns the users watching taskID in the order they started
+func (r *WatcherStore) ListWatchers(ctx context.Context, taskID string) ([]*models.User, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var users []*models.User
+	for _, id := range r.s.watchers[taskID] {
+		user := *r.s.users[id]
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+// WatcherIDs returns the IDs of the users watching taskID
+func (r *WatcherStore) WatcherIDs(ctx context.Context, taskID string) ([]string, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	return append([]string(nil), r.s.watchers[taskID]...), nil
+}
+
+// watch adds userID to the watchers of taskID unless it already watches it.
+// The slice is replaced rather than appended to in place, so snapshots are
+// unaffected. s.mu must be held.
+func (s *Store) watch(taskID, userID string) {
+	if s.watching(taskID, userID) {
+		return
+	}
+
+	current := s.watchers[taskID]
+	next := make([]string, len(current), len(current)+1)
+	copy(next, current)
+	s.watchers[taskID] = append(next, userID)
+}
+
+// watching reports whether userID watches taskID; s.mu must be held
+func (s *Store) watching(taskID, userID string) bool {
+	for _, id := range s.watchers[taskID] {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
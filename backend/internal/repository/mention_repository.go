@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+type MentionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMentionRepository(db *pgxpool.Pool) *MentionRepository {
+	return &MentionRepository{db: db}
+}
+
+// Sync makes userIDs the users taskID mentions. Mentions that remain keep
+// their author and time; mentions no longer present are removed. It returns
+// the IDs of the users newly mentioned, whose mentions are attributed to
+// mentionedByID.
+func (r *MentionRepository) Sync(ctx context.Context, taskID, mentionedByID string, userIDs []string) ([]string, error) {
+	// A nil slice would be sent as NULL, which matches no rows to remove
+	if userIDs == nil {
+		userIDs = []string{}
+	}
+
+	var added []string
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		_, err := conn(ctx, r.db).Exec(ctx,
+			"DELETE FROM task_mentions WHERE task_id = $1 AND NOT (user_id = ANY($2::uuid[]))", taskID, userIDs,
+		)
+		if err != nil {
+			return err
+		}
+
+		rows, err := conn(ctx, r.db).Query(ctx, `
+			INSERT INTO task_mentions (task_id, user_id, mentioned_by_id)
+			SELECT $1, user_id, $3 FROM unnest($2::uuid[]) AS user_id
+			ON CONFLICT DO NOTHING
+			RETURNING user_id
+		`, taskID, userIDs, mentionedByID)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var id string
+			if err := rows.Scan(&id); err != nil {
+				return err
+			}
+			added = append(added, id)
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save mentions: %w", err)
+	}
+
+	return added, nil
+}
+
+// ListForTask returns the mentions in taskID's description, oldest first
+func (r *MentionRepository) ListForTask(ctx context.Context, taskID string) ([]*models.Mention, error) {
+	query := `
+		SELECT task_id, user_id, mentioned_by_id, created_at
+		FROM task_mentions
+		WHERE task_id = $1
+		ORDER BY created_at, user_id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list mentions: %w", err)
+	}
+	defer rows.Close()
+
+	var mentions []*models.Mention
+	for rows.Next() {
+		var mention models.Mention
+		if err := rows.Scan(&mention.TaskID, &mention.UserID, &mention.MentionedByID, &mention.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan mention: %w", err)
+		}
+		mentions = append(mentions, &mention)
+	}
+
+	return mentions, rows.Err()
+}
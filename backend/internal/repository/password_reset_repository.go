@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+// ErrPasswordResetInvalid is returned for a reset token that is unknown,
+// expired or already used
+var ErrPasswordResetInvalid = errors.New("invalid or expired password reset link")
+
+// PasswordResetRepository stores password reset tokens by hash
+type PasswordResetRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewPasswordResetRepository(db *pgxpool.Pool) *PasswordResetRepository {
+	return &PasswordResetRepository{db: db}
+}
+
+func (r *PasswordResetRepository) Create(ctx context.Context, reset *models.PasswordReset) (*models.PasswordReset, error) {
+	reset.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO password_resets (id, user_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		reset.ID, reset.UserID, reset.TokenHash, reset.ExpiresAt,
+	).Scan(&reset.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create password reset: %w", err)
+	}
+
+	return reset, nil
+}
+
+// Use spends the token with tokenHash, along with every other unused token of
+// the same user, and returns it
+func (r *PasswordResetRepository) Use(ctx context.Context, tokenHash string) (*models.PasswordReset, error) {
+	var reset models.PasswordReset
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		err := conn(ctx, r.db).QueryRow(ctx, `
+			UPDATE password_resets SET used_at = NOW()
+			WHERE token_hash = $1 AND used_at IS NULL AND expires_at > NOW()
+			RETURNING id, user_id, token_hash, expires_at, used_at, created_at
+		`, tokenHash).Scan(
+			&reset.ID, &reset.UserID, &reset.TokenHash,
+			&reset.ExpiresAt, &reset.UsedAt, &reset.CreatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = conn(ctx, r.db).Exec(ctx,
+			"UPDATE password_resets SET used_at = NOW() WHERE user_id = $1 AND used_at IS NULL", reset.UserID,
+		)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, ErrPasswordResetInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to use password reset: %w", err)
+	}
+
+	return &reset, nil
+}
+
+// PurgeExpired removes the tokens that expired before cutoff and returns how
+// many were removed
+func (r *PasswordResetRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM password_resets WHERE expires_at < $1", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge password resets: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
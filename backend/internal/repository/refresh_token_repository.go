@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+var (
+	// ErrRefreshTokenInvalid is returned for a refresh token that is unknown,
+	// expired or revoked
+	ErrRefreshTokenInvalid = errors.New("invalid refresh token")
+	// ErrRefreshTokenReused is returned when a refresh token that was already
+	// exchanged is presented again. Its family has been revoked by then.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+)
+
+// RefreshTokenRepository stores issued refresh tokens by hash, so a token can
+// be exchanged once and whole families can be revoked
+type RefreshTokenRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewRefreshTokenRepository(db *pgxpool.Pool) *RefreshTokenRepository {
+	return &RefreshTokenRepository{db: db}
+}
+
+// Create stores token in the family of the session it was issued for
+func (r *RefreshTokenRepository) Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error) {
+	token.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO refresh_tokens (id, user_id, family_id, token_hash, expires_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING created_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		token.ID, token.UserID, token.FamilyID, token.TokenHash, token.ExpiresAt,
+	).Scan(&token.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetByHash returns the token with tokenHash, whatever its state
+func (r *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		SELECT id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+		FROM refresh_tokens
+		WHERE token_hash = $1
+	`
+
+	token, err := scanRefreshToken(conn(ctx, r.db).QueryRow(ctx, query, tokenHash))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("refresh token not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	return token, nil
+}
+
+// Use marks the token with tokenHash as exchanged and returns it. Each token
+// can be used once: presenting a used token again revokes every token in its
+// family and returns ErrRefreshTokenReused. The revocation must outlive the
+// caller's error handling, so Use should not run inside a transaction that
+// rolls back on error.
+func (r *RefreshTokenRepository) Use(ctx context.Context, tokenHash string) (*models.RefreshToken, error) {
+	query := `
+		UPDATE refresh_tokens SET used_at = NOW()
+		WHERE token_hash = $1 AND used_at IS NULL AND revoked_at IS NULL AND expires_at > NOW()
+		RETURNING id, user_id, family_id, token_hash, expires_at, used_at, revoked_at, created_at
+	`
+
+	token, err := scanRefreshToken(conn(ctx, r.db).QueryRow(ctx, query, tokenHash))
+	if err == nil {
+		return token, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+
+	var familyID string
+	var used bool
+	err = conn(ctx, r.db).QueryRow(ctx,
+		"SELECT family_id, used_at IS NOT NULL FROM refresh_tokens WHERE token_hash = $1", tokenHash,
+	).Scan(&familyID, &used)
+	if errors.Is(err, pgx.ErrNoRows) || (err == nil && !used) {
+		return nil, ErrRefreshTokenInvalid
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to use refresh token: %w", err)
+	}
+
+	if err := r.RevokeFamily(ctx, familyID); err != nil {
+		return nil, err
+	}
+	return nil, ErrRefreshTokenReused
+}
+
+// RevokeFamily revokes every token descending from the same login
+func (r *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = NOW() WHERE family_id = $1 AND revoked_at IS NULL", familyID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser revokes every refresh token userID holds
+func (r *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		"UPDATE refresh_tokens SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL", userID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	return nil
+}
+
+// PurgeExpired removes the tokens that expired before cutoff and returns how
+// many were removed. Reuse of a purged token is no longer detected, but it
+// would be rejected as expired anyway.
+func (r *RefreshTokenRepository) PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM refresh_tokens WHERE expires_at < $1", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge refresh tokens: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func scanRefreshToken(row pgx.Row) (*models.RefreshToken, error) {
+	var token models.RefreshToken
+	err := row.Scan(
+		&token.ID, &token.UserID, &token.FamilyID, &token.TokenHash,
+		&token.ExpiresAt, &token.UsedAt, &token.RevokedAt, &token.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+// SessionRepository stores logins. Revoking a session here only marks it;
+// its refresh tokens are revoked through RefreshTokenRepository.
+type SessionRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionRepository(db *pgxpool.Pool) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *models.Session) (*models.Session, error) {
+	session.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO sessions (id, user_id, user_agent, ip_address)
+		VALUES ($1, $2, $3, $4)
+		RETURNING created_at, last_seen_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		session.ID, session.UserID, session.UserAgent, session.IPAddress,
+	).Scan(&session.CreatedAt, &session.LastSeenAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session: %w", err)
+	}
+
+	return session, nil
+}
+
+func (r *SessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE id = $1
+	`
+
+	session, err := scanSession(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if errors.Is(err, pgx.ErrNoRows) || isInvalidUUID(err) {
+		return nil, fmt.Errorf("session not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	return session, nil
+}
+
+// ListActive returns userID's sessions that are not revoked and were seen
+// since seenSince, most recently seen first
+func (r *SessionRepository) ListActive(ctx context.Context, userID string, seenSince time.Time) ([]*models.Session, error) {
+	query := `
+		SELECT id, user_id, user_agent, ip_address, created_at, last_seen_at, revoked_at
+		FROM sessions
+		WHERE user_id = $1 AND revoked_at IS NULL AND last_seen_at >= $2
+		ORDER BY last_seen_at DESC, id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID, seenSince)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var sessions []*models.Session
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan session: %w", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+// Touch records that the session was used just now, from userAgent and
+// ipAddress
+func (r *SessionRepository) Touch(ctx context.Context, id, userAgent, ipAddress string) error {
+	_, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE sessions SET last_seen_at = NOW(), user_agent = $2, ip_address = $3
+		WHERE id = $1
+	`, id, userAgent, ipAddress)
+	if err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+
+	return nil
+}
+
+// Revoke ends the session. Revoking it again is not an error.
+func (r *SessionRepository) Revoke(ctx context.Context, id string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		"UPDATE sessions SET revoked_at = NOW() WHERE id = $1 AND revoked_at IS NULL", id,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+
+	return nil
+}
+
+// RevokeAllForUser ends every session of userID and returns the IDs of the
+// sessions it ended
+func (r *SessionRepository) RevokeAllForUser(ctx context.Context, userID string) ([]string, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		"UPDATE sessions SET revoked_at = NOW() WHERE user_id = $1 AND revoked_at IS NULL RETURNING id", userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to revoke sessions: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+// PurgeInactive removes the sessions last seen before cutoff, with their
+// refresh tokens, and returns how many were removed
+func (r *SessionRepository) PurgeInactive(ctx context.Context, cutoff time.Time) (int64, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM sessions WHERE last_seen_at < $1", cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge sessions: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+func scanSession(row pgx.Row) (*models.Session, error) {
+	var session models.Session
+	err := row.Scan(
+		&session.ID, &session.UserID, &session.UserAgent, &session.IPAddress,
+		&session.CreatedAt, &session.LastSeenAt, &session.RevokedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
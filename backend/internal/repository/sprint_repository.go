@@ -0,0 +1,289 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+var (
+	ErrTaskAlreadyInSprint = errors.New("task is already in the sprint")
+	ErrTaskNotInSprint     = errors.New("task is not in the sprint")
+)
+
+const sprintColumns = `id, name, goal, start_date, end_date, created_by_id, created_at, updated_at`
+
+type SprintRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSprintRepository(db *pgxpool.Pool) *SprintRepository {
+	return &SprintRepository{db: db}
+}
+
+func (r *SprintRepository) Create(ctx context.Context, sprint *models.Sprint) (*models.Sprint, error) {
+	sprint.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO sprints (id, name, goal, start_date, end_date, created_by_id)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		sprint.ID, sprint.Name, sprint.Goal, sprint.StartDate, sprint.EndDate, sprint.CreatedByID,
+	).Scan(&sprint.CreatedAt, &sprint.UpdatedAt)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+func (r *SprintRepository) GetByID(ctx context.Context, id string) (*models.Sprint, error) {
+	query := `SELECT ` + sprintColumns + ` FROM sprints WHERE id = $1`
+
+	sprint, err := scanSprint(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("sprint not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sprint: %w", err)
+	}
+
+	return sprint, nil
+}
+
+func (r *SprintRepository) List(ctx context.Context) ([]*models.Sprint, error) {
+	query := `SELECT ` + sprintColumns + ` FROM sprints ORDER BY start_date DESC`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sprints: %w", err)
+	}
+	defer rows.Close()
+
+	var sprints []*models.Sprint
+	for rows.Next() {
+		sprint, err := scanSprint(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan sprint: %w", err)
+		}
+		sprints = append(sprints, sprint)
+	}
+
+	return sprints, rows.Err()
+}
+
+func (r *SprintRepository) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.Sprint, error) {
+	query := "UPDATE sprints SET updated_at = NOW()"
+	args := []interface{}{}
+	argPos := 1
+
+	for _, column := range []string{"name", "goal", "start_date", "end_date"} {
+		if value, ok := updates[column]; ok {
+			query += fmt.Sprintf(", %s = $%d", column, argPos)
+			args = append(args, value)
+			argPos++
+		}
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d", argPos)
+	args = append(args, id)
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update sprint: %w", err)
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *SprintRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM sprints WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete sprint: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("sprint not found")
+	}
+
+	return nil
+}
+
+// AddTask puts a task in the sprint's scope
+func (r *SprintRepository) AddTask(ctx context.Context, sprintID, taskID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		"INSERT INTO sprint_tasks (sprint_id, task_id) VALUES ($1, $2)",
+		sprintID, taskID,
+	)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrTaskAlreadyInSprint
+	}
+	if err != nil {
+		return fmt.Errorf("failed to add task to sprint: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveTask takes a task out of the sprint's scope, keeping the history row
+func (r *SprintRepository) RemoveTask(ctx context.Context, sprintID, taskID string) error {
+	result, err := conn(ctx, r.db).Exec(ctx,
+		"UPDATE sprint_tasks SET removed_at = NOW() WHERE sprint_id = $1 AND task_id = $2 AND removed_at IS NULL",
+		sprintID, taskID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to remove task from sprint: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrTaskNotInSprint
+	}
+
+	return nil
+}
+
+// ListTasks returns the tasks currently in the sprint
+func (r *SprintRepository) ListTasks(ctx context.Context, sprintID string) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id IN (SELECT task_id FROM sprint_tasks WHERE sprint_id = $1 AND removed_at IS NULL)
+		  AND deleted_at IS NULL
+		ORDER BY created_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sprint tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return collectTasks(rows)
+}
+
+// Totals sums estimates over the sprint's current scope
+func (r *SprintRepository) Totals(ctx context.Context, sprintID string) (*models.SprintTotals, error) {
+	query := `
+		SELECT COALESCE(SUM(t.story_points), 0),
+		       COALESCE(SUM(t.story_points) FILTER (WHERE t.status = 'DONE'), 0),
+		       COALESCE(SUM(t.estimated_hours), 0)::float8,
+		       COALESCE(SUM(t.estimated_hours) FILTER (WHERE t.status = 'DONE'), 0)::float8
+		FROM sprint_tasks st
+		JOIN tasks t ON t.id = st.task_id AND t.deleted_at IS NULL
+		WHERE st.sprint_id = $1 AND st.removed_at IS NULL
+	`
+
+	var totals models.SprintTotals
+	err := conn(ctx, r.db).QueryRow(ctx, query, sprintID).Scan(
+		&totals.ScopePoints, &totals.CompletedPoints, &totals.ScopeHours, &totals.CompletedHours,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to total sprint: %w", err)
+	}
+
+	return &totals, nil
+}
+
+// ScopeChanges lists every addition and removal of a task, oldest first
+func (r *SprintRepository) ScopeChanges(ctx context.Context, sprintID string) ([]*models.SprintScopeChange, error) {
+	query := `
+		SELECT task_id, 'ADDED', added_at FROM sprint_tasks WHERE sprint_id = $1
+		UNION ALL
+		SELECT task_id, 'REMOVED', removed_at FROM sprint_tasks WHERE sprint_id = $1 AND removed_at IS NOT NULL
+		ORDER BY 3
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scope changes: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []*models.SprintScopeChange
+	for rows.Next() {
+		var change models.SprintScopeChange
+		if err := rows.Scan(&change.TaskID, &change.Change, &change.At); err != nil {
+			return nil, fmt.Errorf("failed to scan scope change: %w", err)
+		}
+		changes = append(changes, &change)
+	}
+
+	return changes, rows.Err()
+}
+
+// Burndown replays the sprint day by day, from its start until its end or
+// today, whichever comes first. A task counts towards a day's scope if it was
+// in the sprint at the end of that day, and as completed if it was also done by then.
+func (r *SprintRepository) Burndown(ctx context.Context, sprintID string) ([]*models.BurndownPoint, error) {
+	query := `
+		WITH sprint AS (
+		    SELECT start_date, end_date FROM sprints WHERE id = $1
+		),
+		days AS (
+		    SELECT d AS day, d + INTERVAL '1 day' AS day_end
+		    FROM sprint, generate_series(
+		        date_trunc('day', sprint.start_date),
+		        LEAST(sprint.end_date, NOW()),
+		        INTERVAL '1 day'
+		    ) AS d
+		)
+		SELECT days.day,
+		       COALESCE(SUM(t.story_points), 0),
+		       COALESCE(SUM(t.story_points) FILTER (WHERE t.completed_at < days.day_end), 0),
+		       COALESCE(SUM(t.estimated_hours), 0)::float8,
+		       COALESCE(SUM(t.estimated_hours) FILTER (WHERE t.completed_at < days.day_end), 0)::float8
+		FROM days
+		LEFT JOIN sprint_tasks st
+		       ON st.sprint_id = $1
+		      AND st.added_at < days.day_end
+		      AND (st.removed_at IS NULL OR st.removed_at >= days.day_end)
+		LEFT JOIN tasks t ON t.id = st.task_id AND t.deleted_at IS NULL
+		GROUP BY days.day
+		ORDER BY days.day
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, sprintID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute burndown: %w", err)
+	}
+	defer rows.Close()
+
+	var points []*models.BurndownPoint
+	for rows.Next() {
+		var point models.BurndownPoint
+		err := rows.Scan(
+			&point.Date, &point.ScopePoints, &point.CompletedPoints,
+			&point.ScopeHours, &point.CompletedHours,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan burndown: %w", err)
+		}
+		points = append(points, &point)
+	}
+
+	return points, rows.Err()
+}
+
+func scanSprint(row pgx.Row) (*models.Sprint, error) {
+	var sprint models.Sprint
+	err := row.Scan(
+		&sprint.ID, &sprint.Name, &sprint.Goal, &sprint.StartDate, &sprint.EndDate,
+		&sprint.CreatedByID, &sprint.CreatedAt, &sprint.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &sprint, nil
+}
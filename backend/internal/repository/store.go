@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"taskboard/internal/models"
+)
+
+// UserStore is the user persistence the GraphQL resolvers, the REST API and
+// the calendar feed depend on. UserRepository implements it on Postgres;
+// package memory implements it for tests.
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	List(ctx context.Context) ([]*models.User, error)
+	ListPage(ctx context.Context, limit, offset int) ([]*models.User, error)
+	Count(ctx context.Context) (int, error)
+	Update(ctx context.Context, id string, updates map[string]interface{}) (*models.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	IDsByEmail(ctx context.Context, emails []string) (map[string]string, error)
+	ListByMention(ctx context.Context, names []string) ([]*models.User, error)
+	SetCalendarTokenHash(ctx context.Context, id, tokenHash string) error
+	GetByCalendarTokenHash(ctx context.Context, tokenHash string) (*models.User, error)
+	MarkEmailVerified(ctx context.Context, id, email string) (bool, error)
+	SetPasswordHash(ctx context.Context, id, passwordHash string) error
+}
+
+// TaskStore is the task persistence the GraphQL resolvers, the REST API, the
+// calendar feed and the background jobs depend on. TaskRepository implements
+// it on Postgres; package memory implements it for tests.
+type TaskStore interface {
+	Create(ctx context.Context, task *models.Task) (*models.Task, error)
+	BulkCreate(ctx context.Context, tasks []*models.Task) (int64, error)
+	GetByID(ctx context.Context, id string) (*models.Task, error)
+	List(ctx context.Context, filter map[string]interface{}) ([]*models.Task, error)
+	Count(ctx context.Context, filter map[string]interface{}) (int, error)
+	Export(ctx context.Context, filter map[string]interface{}, fn func(*models.TaskExport) error) error
+	Update(ctx context.Context, id string, updates map[string]interface{}) (*models.Task, error)
+	Delete(ctx context.Context, id string) error
+	GetByUserID(ctx context.Context, userID string) ([]*models.Task, error)
+	GetAssignedToUser(ctx context.Context, userID string) ([]*models.Task, error)
+
+	GetDeleted(ctx context.Context, id string) (*models.Task, error)
+	ListDeleted(ctx context.Context, userID string) ([]*models.Task, error)
+	Restore(ctx context.Context, id string) (*models.Task, error)
+	Archive(ctx context.Context, id string) (*models.Task, error)
+	Unarchive(ctx context.Context, id string) (*models.Task, error)
+	ArchiveCompleted(ctx context.Context, cutoff time.Time) (int64, error)
+	PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, []string, error)
+
+	BulkUpdate(ctx context.Context, ids []string, atomic bool, prepare func(*models.Task) (map[string]interface{}, error)) ([]*BulkResult, error)
+	BulkDelete(ctx context.Context, ids []string, atomic bool, check func(*models.Task) error) ([]*BulkResult, error)
+
+	SpawnNextOccurrence(ctx context.Context, task *models.Task) (*models.Task, error)
+	StopRecurrence(ctx context.Context, seriesID string) error
+	ListRecurrenceDue(ctx context.Context, now time.Time) ([]*models.Task, error)
+}
+
+// ViewStore is the saved view persistence the GraphQL resolvers depend on
+type ViewStore interface {
+	Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error)
+	GetByID(ctx context.Context, id string) (*models.SavedView, error)
+	ListVisible(ctx context.Context, userID string) ([]*models.SavedView, error)
+	Update(ctx context.Context, id string, updates map[string]interface{}) (*models.SavedView, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// WatcherStore is the task watcher persistence the GraphQL resolvers depend
+// on. Creators and assignees are made watchers when a task is created or
+// assigned.
+type WatcherStore interface {
+	Watch(ctx context.Context, taskID, userID string) error
+	Unwatch(ctx context.Context, taskID, userID string) error
+	ListWatchers(ctx context.Context, taskID string) ([]*models.User, error)
+	WatcherIDs(ctx context.Context, taskID string) ([]string, error)
+}
+
+// MentionStore is the task mention persistence the GraphQL resolvers and the
+// REST API depend on
+type MentionStore interface {
+	Sync(ctx context.Context, taskID, mentionedByID string, userIDs []string) ([]string, error)
+	ListForTask(ctx context.Context, taskID string) ([]*models.Mention, error)
+}
+
+// RefreshTokenStore is the refresh token persistence the GraphQL resolvers
+// and the background jobs depend on
+type RefreshTokenStore interface {
+	Create(ctx context.Context, token *models.RefreshToken) (*models.RefreshToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	Use(ctx context.Context, tokenHash string) (*models.RefreshToken, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID string) error
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// SessionStore is the session persistence the GraphQL resolvers, the auth
+// middleware and the background jobs depend on
+type SessionStore interface {
+	Create(ctx context.Context, session *models.Session) (*models.Session, error)
+	GetByID(ctx context.Context, id string) (*models.Session, error)
+	ListActive(ctx context.Context, userID string, seenSince time.Time) ([]*models.Session, error)
+	Touch(ctx context.Context, id, userAgent, ipAddress string) error
+	Revoke(ctx context.Context, id string) error
+	RevokeAllForUser(ctx context.Context, userID string) ([]string, error)
+	PurgeInactive(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// PasswordResetStore is the password reset persistence the password reset
+// service and the background jobs depend on
+type PasswordResetStore interface {
+	Create(ctx context.Context, reset *models.PasswordReset) (*models.PasswordReset, error)
+	Use(ctx context.Context, tokenHash string) (*models.PasswordReset, error)
+	PurgeExpired(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// TxRunner runs a unit of work spanning several stores atomically
+type TxRunner interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+var (
+	_ UserStore          = (*UserRepository)(nil)
+	_ TaskStore          = (*TaskRepository)(nil)
+	_ ViewStore          = (*ViewRepository)(nil)
+	_ WatcherStore       = (*WatcherRepository)(nil)
+	_ MentionStore       = (*MentionRepository)(nil)
+	_ RefreshTokenStore  = (*RefreshTokenRepository)(nil)
+	_ SessionStore       = (*SessionRepository)(nil)
+	_ PasswordResetStore = (*PasswordResetRepository)(nil)
+	_ TxRunner           = (*Transactor)(nil)
+)
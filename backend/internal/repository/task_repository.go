@@ -2,14 +2,25 @@ package repository
 
 import (
 	"context"
-	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"taskboard/internal/models"
+	"taskboard/internal/recurrence"
 )
 
+// taskColumns lists the columns read by scanTask, in order
+const taskColumns = `id, title, description, status, priority, created_by_id,
+		       assigned_to_id, due_date, created_at, updated_at,
+		       recurrence_rule, series_id, occurrence,
+		       story_points, estimated_hours, completed_at, archived_at, deleted_at`
+
 type TaskRepository struct {
 	db *pgxpool.Pool
 }
@@ -21,16 +32,26 @@ func NewTaskRepository(db *pgxpool.Pool) *TaskRepository {
 func (r *TaskRepository) Create(ctx context.Context, task *models.Task) (*models.Task, error) {
 	task.ID = uuid.New().String()
 	
+	if task.RecurrenceRule != nil && task.SeriesID == nil {
+		task.SeriesID = &task.ID
+	}
+	if task.Occurrence == 0 {
+		task.Occurrence = 1
+	}
+
 	query := `
-		INSERT INTO tasks (id, title, description, status, priority, created_by_id, assigned_to_id, due_date)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-		RETURNING created_at, updated_at
+		INSERT INTO tasks (id, title, description, status, priority, created_by_id, assigned_to_id, due_date,
+		                   recurrence_rule, series_id, occurrence, story_points, estimated_hours)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING created_at, updated_at, completed_at
 	`
 	
-	err := r.db.QueryRow(ctx, query,
+	err := conn(ctx, r.db).QueryRow(ctx, query,
 		task.ID, task.Title, task.Description, task.Status, task.Priority,
 		task.CreatedByID, task.AssignedToID, task.DueDate,
-	).Scan(&task.CreatedAt, &task.UpdatedAt)
+		task.RecurrenceRule, task.SeriesID, task.Occurrence,
+		task.StoryPoints, task.EstimatedHours,
+	).Scan(&task.CreatedAt, &task.UpdatedAt, &task.CompletedAt)
 	
 	if err != nil {
 		return nil, fmt.Errorf("failed to create task: %w", err)
@@ -40,92 +61,304 @@ func (r *TaskRepository) Create(ctx context.Context, task *models.Task) (*models
 }
 
 func (r *TaskRepository) GetByID(ctx context.Context, id string) (*models.Task, error) {
-	var task models.Task
-	
 	query := `
-		SELECT id, title, description, status, priority, created_by_id, 
-		       assigned_to_id, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 	
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority,
-		&task.CreatedByID, &task.AssignedToID, &task.DueDate,
-		&task.CreatedAt, &task.UpdatedAt,
-	)
+	task, err := scanTask(conn(ctx, r.db).QueryRow(ctx, query, id))
 	
-	if err == sql.ErrNoRows {
+	if err == pgx.ErrNoRows || isInvalidUUID(err) {
 		return nil, fmt.Errorf("task not found")
 	}
 	if err != nil {
 		return nil, fmt.Errorf("failed to get task: %w", err)
 	}
 	
-	return &task, nil
+	return task, nil
 }
 
 func (r *TaskRepository) List(ctx context.Context, filter map[string]interface{}) ([]*models.Task, error) {
 	query := `
-		SELECT id, title, description, status, priority, created_by_id,
-		       assigned_to_id, due_date, created_at, updated_at
+		SELECT ` + taskColumns + `
 		FROM tasks
 		WHERE 1=1
 	`
 	
-	args := []interface{}{}
-	argPos := 1
+	where, args := buildTaskFilter(filter)
+	query += where + taskOrder(filter)
 	
-	if status, ok := filter["status"].(string); ok && status != "" {
-		query += fmt.Sprintf(" AND status = $%d", argPos)
-		args = append(args, status)
-		argPos++
+	if limit, ok := filter["limit"].(int); ok && limit > 0 {
+		args = append(args, limit)
+		query += fmt.Sprintf(" LIMIT $%d", len(args))
 	}
 	
-	if priority, ok := filter["priority"].(string); ok && priority != "" {
-		query += fmt.Sprintf(" AND priority = $%d", argPos)
-		args = append(args, priority)
-		argPos++
+	if offset, ok := filter["offset"].(int); ok && offset > 0 {
+		args = append(args, offset)
+		query += fmt.Sprintf(" OFFSET $%d", len(args))
 	}
 	
-	if assignedToID, ok := filter["assigned_to_id"].(string); ok && assignedToID != "" {
-		query += fmt.Sprintf(" AND assigned_to_id = $%d", argPos)
-		args = append(args, assignedToID)
-		argPos++
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
 	}
+	defer rows.Close()
 	
-	if createdByID, ok := filter["created_by_id"].(string); ok && createdByID != "" {
-		query += fmt.Sprintf(" AND created_by_id = $%d", argPos)
-		args = append(args, createdByID)
-		argPos++
+	return collectTasks(rows)
+}
+
+// Count returns the number of tasks matching filter, ignoring limit and offset
+func (r *TaskRepository) Count(ctx context.Context, filter map[string]interface{}) (int, error) {
+	where, args := buildTaskFilter(filter)
+
+	var count int
+	err := conn(ctx, r.db).QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE 1=1"+where, args...).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
 	}
-	
-	query += " ORDER BY created_at DESC"
-	
-	rows, err := r.db.Query(ctx, query, args...)
+
+	return count, nil
+}
+
+// BulkCreate inserts tasks with a single COPY, assigning their IDs. Either
+// every task is inserted or none is.
+func (r *TaskRepository) BulkCreate(ctx context.Context, tasks []*models.Task) (int64, error) {
+	rows := make([][]interface{}, len(tasks))
+	for i, task := range tasks {
+		task.ID = uuid.New().String()
+		if task.Occurrence == 0 {
+			task.Occurrence = 1
+		}
+		rows[i] = []interface{}{
+			task.ID, task.Title, task.Description, task.Status, task.Priority,
+			task.CreatedByID, task.AssignedToID, task.DueDate, task.Occurrence,
+		}
+	}
+
+	count, err := conn(ctx, r.db).CopyFrom(ctx,
+		pgx.Identifier{"tasks"},
+		[]string{"id", "title", "description", "status", "priority", "created_by_id", "assigned_to_id", "due_date", "occurrence"},
+		pgx.CopyFromRows(rows),
+	)
 	if err != nil {
-		return nil, fmt.Errorf("failed to list tasks: %w", err)
+		return 0, fmt.Errorf("failed to copy tasks: %w", err)
+	}
+
+	return count, nil
+}
+
+// Export calls fn for every task matching filter, newest first, with creator
+// and assignee resolved. Rows are handed to fn as they are read from the
+// connection rather than collected, so exports of any size use constant memory.
+func (r *TaskRepository) Export(ctx context.Context, filter map[string]interface{}, fn func(*models.TaskExport) error) error {
+	where, args := buildTaskFilter(filter)
+
+	query := `
+		SELECT t.id, t.title, t.description, t.status, t.priority,
+		       c.name, c.email, a.name, a.email,
+		       t.due_date, t.created_at, t.updated_at, t.completed_at
+		FROM (SELECT * FROM tasks WHERE 1=1` + where + `) t
+		JOIN users c ON c.id = t.created_by_id
+		LEFT JOIN users a ON a.id = t.assigned_to_id
+		ORDER BY t.created_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to export tasks: %w", err)
 	}
 	defer rows.Close()
-	
-	var tasks []*models.Task
+
 	for rows.Next() {
-		var task models.Task
+		var row models.TaskExport
 		err := rows.Scan(
-			&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority,
-			&task.CreatedByID, &task.AssignedToID, &task.DueDate,
-			&task.CreatedAt, &task.UpdatedAt,
+			&row.ID, &row.Title, &row.Description, &row.Status, &row.Priority,
+			&row.CreatorName, &row.CreatorEmail, &row.AssigneeName, &row.AssigneeEmail,
+			&row.DueDate, &row.CreatedAt, &row.UpdatedAt, &row.CompletedAt,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan task: %w", err)
+			return fmt.Errorf("failed to scan task export: %w", err)
+		}
+
+		if err := fn(&row); err != nil {
+			return err
 		}
-		tasks = append(tasks, &task)
 	}
-	
-	return tasks, nil
+
+	return rows.Err()
+}
+
+// buildTaskFilter turns the filters shared by List, Count and Export into an
+// AND clause and its positional arguments. Deleted tasks never match;
+// archived tasks only match when include_archived is true. watched_by and
+// mentioned limit the tasks to those a user watches or is mentioned in.
+func buildTaskFilter(filter map[string]interface{}) (string, []interface{}) {
+	where := " AND deleted_at IS NULL"
+	args := []interface{}{}
+
+	if includeArchived, _ := filter["include_archived"].(bool); !includeArchived {
+		where += " AND archived_at IS NULL"
+	}
+
+	if userID, ok := filter["watched_by"].(string); ok {
+		args = append(args, userID)
+		where += fmt.Sprintf(" AND id IN (SELECT task_id FROM task_watchers WHERE user_id = $%d)", len(args))
+	}
+	if userID, ok := filter["mentioned"].(string); ok {
+		args = append(args, userID)
+		where += fmt.Sprintf(" AND id IN (SELECT task_id FROM task_mentions WHERE user_id = $%d)", len(args))
+	}
+
+	if predicate := taskPredicate(filter, &args); predicate != "" {
+		where += " AND " + predicate
+	}
+
+	return where, args
+}
+
+// taskPredicate compiles the conditions in filter, including nested "and",
+// "or" and "not" filters, into a boolean SQL expression. Values are only
+// ever appended to args and referenced by position. It returns "" when
+// filter has no conditions.
+func taskPredicate(filter map[string]interface{}, args *[]interface{}) string {
+	var conditions []string
+	param := func(value interface{}) string {
+		*args = append(*args, value)
+		return fmt.Sprintf("$%d", len(*args))
+	}
+
+	for _, column := range []string{"status", "priority", "assigned_to_id", "created_by_id"} {
+		if value, ok := filter[column].(string); ok && value != "" {
+			conditions = append(conditions, column+" = "+param(value))
+		}
+	}
+
+	for _, column := range []string{"status", "priority"} {
+		if values, ok := filter[column+"_in"].([]string); ok && len(values) > 0 {
+			conditions = append(conditions, column+" = ANY("+param(values)+")")
+		}
+	}
+
+	for _, r := range []struct{ key, condition string }{
+		{"due_before", "due_date < "},
+		{"due_after", "due_date > "},
+		{"created_after", "created_at > "},
+		{"updated_since", "updated_at >= "},
+	} {
+		if value, ok := filter[r.key].(time.Time); ok {
+			conditions = append(conditions, r.condition+param(value))
+		}
+	}
+
+	if overdue, ok := filter["overdue"].(bool); ok {
+		// Tasks without a due date are never overdue
+		condition := "COALESCE(due_date < NOW() AND status <> 'DONE', FALSE)"
+		if !overdue {
+			condition = "NOT " + condition
+		}
+		conditions = append(conditions, condition)
+	}
+	if unassigned, ok := filter["unassigned"].(bool); ok {
+		if unassigned {
+			conditions = append(conditions, "assigned_to_id IS NULL")
+		} else {
+			conditions = append(conditions, "assigned_to_id IS NOT NULL")
+		}
+	}
+
+	if title, ok := filter["title_contains"].(string); ok && title != "" {
+		conditions = append(conditions, "title ILIKE "+param("%"+escapeLike(title)+"%"))
+	}
+
+	for _, op := range []string{"and", "or"} {
+		subs, _ := filter[op].([]map[string]interface{})
+		if len(subs) == 0 {
+			continue
+		}
+		parts := make([]string, len(subs))
+		for i, sub := range subs {
+			parts[i] = taskPredicate(sub, args)
+			if parts[i] == "" {
+				parts[i] = "TRUE"
+			}
+		}
+		conditions = append(conditions, "("+strings.Join(parts, " "+strings.ToUpper(op)+" ")+")")
+	}
+	if not, ok := filter["not"].(map[string]interface{}); ok {
+		predicate := taskPredicate(not, args)
+		if predicate == "" {
+			predicate = "TRUE"
+		}
+		// A condition that is unknown for a task, such as a comparison with
+		// a missing due date, counts as false, so its negation matches
+		conditions = append(conditions, "NOT COALESCE("+predicate+", FALSE)")
+	}
+
+	return strings.Join(conditions, " AND ")
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// taskSortColumns maps the sort fields List accepts to the expressions
+// they order by. Priorities sort by rank rather than alphabetically.
+var taskSortColumns = map[string]string{
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+	"due_date":   "due_date",
+	"priority":   "CASE priority WHEN 'LOW' THEN 1 WHEN 'MEDIUM' THEN 2 WHEN 'HIGH' THEN 3 ELSE 4 END",
+	"title":      "lower(title)",
+}
+
+// taskOrder returns the ORDER BY clause for filter["sort"] and
+// filter["sort_desc"], newest first by default. Tasks without a value come
+// last either way, and ties are broken by ID so pages are stable; bulk
+// imports give many tasks the same created_at.
+func taskOrder(filter map[string]interface{}) string {
+	field, _ := filter["sort"].(string)
+	column, ok := taskSortColumns[field]
+	if !ok {
+		return " ORDER BY created_at DESC, id DESC"
+	}
+
+	direction := "ASC"
+	if desc, _ := filter["sort_desc"].(bool); desc {
+		direction = "DESC"
+	}
+	return fmt.Sprintf(" ORDER BY %s %s NULLS LAST, id", column, direction)
 }
 
 func (r *TaskRepository) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.Task, error) {
+	query, args := buildTaskUpdate(id, updates)
+
+	// The updated row stays locked until commit, so the task read back is
+	// exactly what this update wrote
+	var task *models.Task
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		result, err := conn(ctx, r.db).Exec(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return fmt.Errorf("task not found")
+		}
+
+		task, err = r.GetByID(ctx, id)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// buildTaskUpdate returns the UPDATE statement applying updates to the task
+// with the given id, and its positional arguments
+func buildTaskUpdate(id string, updates map[string]interface{}) (string, []interface{}) {
 	query := "UPDATE tasks SET updated_at = NOW()"
 	args := []interface{}{}
 	argPos := 1
@@ -146,6 +379,11 @@ func (r *TaskRepository) Update(ctx context.Context, id string, updates map[stri
 		query += fmt.Sprintf(", status = $%d", argPos)
 		args = append(args, status)
 		argPos++
+
+		// Reopening an archived task brings it back into task lists
+		if status != "DONE" {
+			query += ", archived_at = NULL"
+		}
 	}
 	
 	if priority, ok := updates["priority"].(string); ok {
@@ -166,21 +404,37 @@ func (r *TaskRepository) Update(ctx context.Context, id string, updates map[stri
 		argPos++
 	}
 	
-	query += fmt.Sprintf(" WHERE id = $%d", argPos)
-	args = append(args, id)
-	
-	_, err := r.db.Exec(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update task: %w", err)
+	if storyPoints, ok := updates["story_points"]; ok {
+		query += fmt.Sprintf(", story_points = $%d", argPos)
+		args = append(args, storyPoints)
+		argPos++
+	}
+
+	if estimatedHours, ok := updates["estimated_hours"]; ok {
+		query += fmt.Sprintf(", estimated_hours = $%d", argPos)
+		args = append(args, estimatedHours)
+		argPos++
+	}
+
+	if rule, ok := updates["recurrence_rule"]; ok {
+		// A task that starts recurring becomes the head of its own series
+		query += fmt.Sprintf(", recurrence_rule = $%d, series_id = COALESCE(series_id, id)", argPos)
+		args = append(args, rule)
+		argPos++
 	}
+
+	query += fmt.Sprintf(" WHERE id = $%d AND deleted_at IS NULL", argPos)
+	args = append(args, id)
 	
-	return r.GetByID(ctx, id)
+	return query, args
 }
 
+// Delete moves a task to the trash. It disappears from every read until it
+// is restored, and is removed for good by PurgeDeleted.
 func (r *TaskRepository) Delete(ctx context.Context, id string) error {
-	query := "DELETE FROM tasks WHERE id = $1"
+	query := "UPDATE tasks SET deleted_at = NOW() WHERE id = $1 AND deleted_at IS NULL"
 	
-	result, err := r.db.Exec(ctx, query, id)
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
@@ -192,6 +446,271 @@ func (r *TaskRepository) Delete(ctx context.Context, id string) error {
 	return nil
 }
 
+// MaxBulkTasks is the most tasks a single bulk operation may change
+const MaxBulkTasks = 100
+
+// ErrBulkRolledBack is reported for tasks whose change was undone because
+// another task in an all-or-nothing batch failed
+var ErrBulkRolledBack = errors.New("not applied because another task in the batch failed")
+
+// BulkResult is the outcome of a bulk operation for one task
+type BulkResult struct {
+	ID string
+	// Before is the task as it was when locked, if it exists
+	Before *models.Task
+	// Task is the task after the change; nil if it failed or was deleted
+	Task *models.Task
+	Err  error
+}
+
+// BulkUpdate updates tasks in one transaction. prepare receives each task,
+// locked, and returns the updates to apply to it or an error to reject it.
+// Unless atomic is set, rejected or failing tasks do not stop the others.
+func (r *TaskRepository) BulkUpdate(ctx context.Context, ids []string, atomic bool, prepare func(*models.Task) (map[string]interface{}, error)) ([]*BulkResult, error) {
+	return r.bulk(ctx, ids, atomic, func(ctx context.Context, task *models.Task) (*models.Task, error) {
+		updates, err := prepare(task)
+		if err != nil {
+			return nil, err
+		}
+
+		query, args := buildTaskUpdate(task.ID, updates)
+		updated, err := scanTask(conn(ctx, r.db).QueryRow(ctx, query+" RETURNING "+taskColumns, args...))
+		if err != nil {
+			return nil, fmt.Errorf("failed to update task: %w", err)
+		}
+		return updated, nil
+	})
+}
+
+// BulkDelete moves tasks to the trash in one transaction. check receives
+// each task, locked, and returns an error to reject it.
+func (r *TaskRepository) BulkDelete(ctx context.Context, ids []string, atomic bool, check func(*models.Task) error) ([]*BulkResult, error) {
+	return r.bulk(ctx, ids, atomic, func(ctx context.Context, task *models.Task) (*models.Task, error) {
+		if err := check(task); err != nil {
+			return nil, err
+		}
+
+		if _, err := conn(ctx, r.db).Exec(ctx, "UPDATE tasks SET deleted_at = NOW() WHERE id = $1", task.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete task: %w", err)
+		}
+		return nil, nil
+	})
+}
+
+// errBulkAborted rolls back an all-or-nothing batch once a task has failed
+var errBulkAborted = errors.New("bulk operation aborted")
+
+// bulk locks and applies fn to each distinct task in ids within a single
+// transaction. Every task gets its own savepoint, so a failing task can be
+// undone on its own; in atomic mode any failure rolls back the whole batch.
+// The returned error is only set when the transaction itself fails.
+func (r *TaskRepository) bulk(ctx context.Context, ids []string, atomic bool, fn func(context.Context, *models.Task) (*models.Task, error)) ([]*BulkResult, error) {
+	var results []*BulkResult
+
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		seen := make(map[string]bool, len(ids))
+		failed := false
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			result := &BulkResult{ID: id}
+			results = append(results, result)
+
+			err := withTx(ctx, r.db, func(ctx context.Context) error {
+				result.Err = r.bulkItem(ctx, result, fn)
+				return result.Err
+			})
+			if result.Err != nil {
+				result.Task = nil
+				failed = true
+			} else if err != nil {
+				return err
+			}
+		}
+
+		if atomic && failed {
+			return errBulkAborted
+		}
+		return nil
+	})
+
+	if err == errBulkAborted {
+		for _, result := range results {
+			if result.Err == nil {
+				result.Task, result.Err = nil, ErrBulkRolledBack
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// bulkItem locks one task of a bulk operation and applies fn to it
+func (r *TaskRepository) bulkItem(ctx context.Context, result *BulkResult, fn func(context.Context, *models.Task) (*models.Task, error)) error {
+	query := "SELECT " + taskColumns + " FROM tasks WHERE id = $1 AND deleted_at IS NULL FOR UPDATE"
+
+	task, err := scanTask(conn(ctx, r.db).QueryRow(ctx, query, result.ID))
+	if err == pgx.ErrNoRows || isInvalidUUID(err) {
+		return fmt.Errorf("task not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get task: %w", err)
+	}
+	result.Before = task
+
+	result.Task, err = fn(ctx, task)
+	return err
+}
+
+// isInvalidUUID reports whether err is Postgres rejecting a malformed UUID
+func isInvalidUUID(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "22P02"
+}
+
+// GetDeleted returns a task that is in the trash
+func (r *TaskRepository) GetDeleted(ctx context.Context, id string) (*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NOT NULL
+	`
+
+	task, err := scanTask(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows || isInvalidUUID(err) {
+		return nil, fmt.Errorf("task not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get task: %w", err)
+	}
+
+	return task, nil
+}
+
+// ListDeleted returns the tasks created by userID that are in the trash,
+// most recently deleted first
+func (r *TaskRepository) ListDeleted(ctx context.Context, userID string) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE created_by_id = $1 AND deleted_at IS NOT NULL
+		ORDER BY deleted_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deleted tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return collectTasks(rows)
+}
+
+// Restore takes a task out of the trash
+func (r *TaskRepository) Restore(ctx context.Context, id string) (*models.Task, error) {
+	query := "UPDATE tasks SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL"
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// Archive hides a task from task lists without deleting it
+func (r *TaskRepository) Archive(ctx context.Context, id string) (*models.Task, error) {
+	return r.setArchived(ctx, id, "archived_at = COALESCE(archived_at, NOW())")
+}
+
+// Unarchive brings an archived task back into task lists
+func (r *TaskRepository) Unarchive(ctx context.Context, id string) (*models.Task, error) {
+	return r.setArchived(ctx, id, "archived_at = NULL")
+}
+
+func (r *TaskRepository) setArchived(ctx context.Context, id, assignment string) (*models.Task, error) {
+	query := "UPDATE tasks SET " + assignment + " WHERE id = $1 AND deleted_at IS NULL"
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive task: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// ArchiveCompleted archives every task completed before cutoff and returns
+// how many were archived
+func (r *TaskRepository) ArchiveCompleted(ctx context.Context, cutoff time.Time) (int64, error) {
+	query := `
+		UPDATE tasks SET archived_at = NOW()
+		WHERE status = 'DONE' AND completed_at < $1
+		  AND archived_at IS NULL AND deleted_at IS NULL
+	`
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive completed tasks: %w", err)
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// PurgeDeleted permanently removes the tasks deleted before cutoff. It returns
+// how many were removed and the storage keys of their attachments, whose rows
+// go with the tasks but whose content the caller must delete.
+func (r *TaskRepository) PurgeDeleted(ctx context.Context, cutoff time.Time) (int64, []string, error) {
+	// The select sees the attachments as they were before the cascade
+	query := `
+		WITH purged AS (
+		    DELETE FROM tasks WHERE deleted_at < $1 RETURNING id
+		)
+		SELECT purged.id::text, a.storage_key
+		FROM purged
+		LEFT JOIN attachments a ON a.task_id = purged.id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, cutoff)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+	defer rows.Close()
+
+	purged := make(map[string]bool)
+	var keys []string
+	for rows.Next() {
+		var id string
+		var key *string
+		if err := rows.Scan(&id, &key); err != nil {
+			return 0, nil, fmt.Errorf("failed to scan purged task: %w", err)
+		}
+		purged[id] = true
+		if key != nil {
+			keys = append(keys, *key)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return 0, nil, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+
+	return int64(len(purged)), keys, nil
+}
+
 func (r *TaskRepository) GetByUserID(ctx context.Context, userID string) ([]*models.Task, error) {
 	return r.List(ctx, map[string]interface{}{
 		"created_by_id": userID,
@@ -202,4 +721,150 @@ func (r *TaskRepository) GetAssignedToUser(ctx context.Context, userID string) (
 	return r.List(ctx, map[string]interface{}{
 		"assigned_to_id": userID,
 	})
-}
\ No newline at end of file
+}
+
+// SpawnNextOccurrence creates the task following task in its recurrence series,
+// due at the first occurrence of the series rule that is still in the future.
+// Occurrences missed while the series was overdue are skipped rather than
+// created. It returns nil when the series has ended or was already continued
+// past task.
+func (r *TaskRepository) SpawnNextOccurrence(ctx context.Context, task *models.Task) (*models.Task, error) {
+	if task.RecurrenceRule == nil || task.SeriesID == nil || task.DueDate == nil {
+		return nil, nil
+	}
+
+	rule, err := recurrence.Parse(*task.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	dueDate, occurrence, ok := rule.NextAfter(*task.DueDate, task.Occurrence, time.Now())
+	if !ok {
+		return nil, nil
+	}
+
+	// Skipping makes the next occurrence's number depend on the time, so a
+	// later occurrence of any number means the series was already continued
+	var continued bool
+	err = conn(ctx, r.db).QueryRow(ctx,
+		"SELECT EXISTS (SELECT 1 FROM tasks WHERE series_id = $1 AND occurrence > $2)",
+		task.SeriesID, task.Occurrence,
+	).Scan(&continued)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check next occurrence: %w", err)
+	}
+	if continued {
+		return nil, nil
+	}
+
+	next := &models.Task{
+		ID:             uuid.New().String(),
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         "TODO",
+		Priority:       task.Priority,
+		CreatedByID:    task.CreatedByID,
+		AssignedToID:   task.AssignedToID,
+		DueDate:        &dueDate,
+		RecurrenceRule: task.RecurrenceRule,
+		SeriesID:       task.SeriesID,
+		Occurrence:     occurrence,
+		StoryPoints:    task.StoryPoints,
+		EstimatedHours: task.EstimatedHours,
+	}
+
+	// The unique (series_id, occurrence) index makes spawning idempotent when
+	// a task is completed twice or the scheduler races a completion
+	query := `
+		INSERT INTO tasks (id, title, description, status, priority, created_by_id, assigned_to_id, due_date,
+		                   recurrence_rule, series_id, occurrence, story_points, estimated_hours)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		ON CONFLICT (series_id, occurrence) DO NOTHING
+		RETURNING created_at, updated_at
+	`
+
+	err = conn(ctx, r.db).QueryRow(ctx, query,
+		next.ID, next.Title, next.Description, next.Status, next.Priority,
+		next.CreatedByID, next.AssignedToID, next.DueDate,
+		next.RecurrenceRule, next.SeriesID, next.Occurrence,
+		next.StoryPoints, next.EstimatedHours,
+	).Scan(&next.CreatedAt, &next.UpdatedAt)
+
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create next occurrence: %w", err)
+	}
+
+	return next, nil
+}
+
+// ListRecurrenceDue returns the latest occurrence of every active series whose
+// due date has passed, i.e. the tasks whose successor should now be scheduled
+func (r *TaskRepository) ListRecurrenceDue(ctx context.Context, now time.Time) ([]*models.Task, error) {
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks t
+		WHERE recurrence_rule IS NOT NULL
+		  AND deleted_at IS NULL
+		  AND due_date <= $1
+		  AND NOT EXISTS (
+		      SELECT 1 FROM tasks n
+		      WHERE n.series_id = t.series_id AND n.occurrence > t.occurrence
+		  )
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list recurring tasks: %w", err)
+	}
+	defer rows.Close()
+
+	return collectTasks(rows)
+}
+
+// StopRecurrence ends the series task belongs to. Existing occurrences are kept
+// but no further ones will be generated.
+func (r *TaskRepository) StopRecurrence(ctx context.Context, seriesID string) error {
+	query := "UPDATE tasks SET recurrence_rule = NULL WHERE series_id = $1"
+
+	_, err := conn(ctx, r.db).Exec(ctx, query, seriesID)
+	if err != nil {
+		return fmt.Errorf("failed to stop recurrence: %w", err)
+	}
+
+	return nil
+}
+
+func scanTask(row pgx.Row) (*models.Task, error) {
+	var task models.Task
+	err := row.Scan(
+		&task.ID, &task.Title, &task.Description, &task.Status, &task.Priority,
+		&task.CreatedByID, &task.AssignedToID, &task.DueDate,
+		&task.CreatedAt, &task.UpdatedAt,
+		&task.RecurrenceRule, &task.SeriesID, &task.Occurrence,
+		&task.StoryPoints, &task.EstimatedHours, &task.CompletedAt, &task.ArchivedAt, &task.DeletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+func collectTasks(rows pgx.Rows) ([]*models.Task, error) {
+	var tasks []*models.Task
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list tasks: %w", err)
+	}
+
+	return tasks, nil
+}
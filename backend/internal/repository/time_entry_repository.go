@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+var (
+	ErrTimerRunning   = errors.New("a timer is already running")
+	ErrNoRunningTimer = errors.New("no timer is running")
+)
+
+// Report groupings accepted by TimeEntryRepository.Report
+const (
+	GroupByTask = "TASK"
+	GroupByUser = "USER"
+	GroupByDay  = "DAY"
+)
+
+const timeEntryColumns = `id, task_id, user_id, started_at, ended_at, note, created_at`
+
+type TimeEntryRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewTimeEntryRepository(db *pgxpool.Pool) *TimeEntryRepository {
+	return &TimeEntryRepository{db: db}
+}
+
+// Create inserts a time entry. An entry without EndedAt is a running timer;
+// ErrTimerRunning is returned if the user already has one.
+func (r *TimeEntryRepository) Create(ctx context.Context, entry *models.TimeEntry) (*models.TimeEntry, error) {
+	entry.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO time_entries (id, task_id, user_id, started_at, ended_at, note)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		entry.ID, entry.TaskID, entry.UserID, entry.StartedAt, entry.EndedAt, entry.Note,
+	).Scan(&entry.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return nil, ErrTimerRunning
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *TimeEntryRepository) GetByID(ctx context.Context, id string) (*models.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries WHERE id = $1`
+
+	entry, err := scanTimeEntry(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("time entry not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time entry: %w", err)
+	}
+
+	return entry, nil
+}
+
+// GetRunning returns the user's running timer, or nil if there is none
+func (r *TimeEntryRepository) GetRunning(ctx context.Context, userID string) (*models.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM time_entries WHERE user_id = $1 AND ended_at IS NULL`
+
+	entry, err := scanTimeEntry(conn(ctx, r.db).QueryRow(ctx, query, userID))
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get running timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+// Stop ends the user's running timer at the given time
+func (r *TimeEntryRepository) Stop(ctx context.Context, userID string, at time.Time) (*models.TimeEntry, error) {
+	query := `
+		UPDATE time_entries SET ended_at = GREATEST($2, started_at)
+		WHERE user_id = $1 AND ended_at IS NULL
+		RETURNING ` + timeEntryColumns
+
+	entry, err := scanTimeEntry(conn(ctx, r.db).QueryRow(ctx, query, userID, at))
+	if err == pgx.ErrNoRows {
+		return nil, ErrNoRunningTimer
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	return entry, nil
+}
+
+func (r *TimeEntryRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM time_entries WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete time entry: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("time entry not found")
+	}
+
+	return nil
+}
+
+// ListByTask returns the time entries on a task that viewerID may see: their
+// own, or everyone's if they created the task
+func (r *TimeEntryRepository) ListByTask(ctx context.Context, viewerID, taskID string) ([]*models.TimeEntry, error) {
+	query := `
+		SELECT e.id, e.task_id, e.user_id, e.started_at, e.ended_at, e.note, e.created_at
+		FROM time_entries e
+		JOIN tasks t ON t.id = e.task_id
+		WHERE e.task_id = $1 AND (e.user_id = $2 OR t.created_by_id = $2)
+		ORDER BY e.started_at DESC
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID, viewerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*models.TimeEntry
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, rows.Err()
+}
+
+// TotalForTask returns the seconds tracked on a task, including running
+// timers, counting only the entries ListByTask would show viewerID
+func (r *TimeEntryRepository) TotalForTask(ctx context.Context, viewerID, taskID string) (int64, error) {
+	query := `
+		SELECT COALESCE(SUM(EXTRACT(EPOCH FROM (COALESCE(e.ended_at, NOW()) - e.started_at))), 0)::bigint
+		FROM time_entries e
+		JOIN tasks t ON t.id = e.task_id
+		WHERE e.task_id = $1 AND (e.user_id = $2 OR t.created_by_id = $2)
+	`
+
+	var total int64
+	if err := conn(ctx, r.db).QueryRow(ctx, query, taskID, viewerID).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to total time entries: %w", err)
+	}
+
+	return total, nil
+}
+
+// Report aggregates time tracked in [from, to) by task, user or day.
+// Entries overlapping the range are clipped to it; an entry is attributed to
+// the day it started. Only entries viewerID may see are included: their own
+// and everyone's on tasks they created. userID, when set, further restricts
+// the report to one user. Time on tasks in the trash is left out.
+func (r *TimeEntryRepository) Report(ctx context.Context, viewerID string, userID *string, from, to time.Time, groupBy string) ([]*models.TimeReportRow, error) {
+	var key, label, join string
+	switch groupBy {
+	case GroupByTask:
+		key, label = "t.id::text", "t.title"
+	case GroupByUser:
+		key, label, join = "u.id::text", "u.name", "JOIN users u ON u.id = e.user_id"
+	case GroupByDay:
+		key = "to_char(e.started_at AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+		label = key
+	default:
+		return nil, fmt.Errorf("unsupported grouping %q", groupBy)
+	}
+
+	query := `
+		SELECT ` + key + ` AS key, ` + label + ` AS label,
+		       SUM(EXTRACT(EPOCH FROM (
+		           LEAST(COALESCE(e.ended_at, NOW()), $2) - GREATEST(e.started_at, $1)
+		       )))::bigint AS seconds
+		FROM time_entries e
+		JOIN tasks t ON t.id = e.task_id AND t.deleted_at IS NULL
+		` + join + `
+		WHERE e.started_at < $2
+		  AND COALESCE(e.ended_at, NOW()) > $1
+		  AND (e.user_id = $3 OR t.created_by_id = $3)
+		  AND ($4::uuid IS NULL OR e.user_id = $4)
+		GROUP BY 1, 2
+		ORDER BY seconds DESC, key
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, from, to, viewerID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build time report: %w", err)
+	}
+	defer rows.Close()
+
+	var report []*models.TimeReportRow
+	for rows.Next() {
+		var row models.TimeReportRow
+		if err := rows.Scan(&row.Key, &row.Label, &row.Seconds); err != nil {
+			return nil, fmt.Errorf("failed to scan time report: %w", err)
+		}
+		report = append(report, &row)
+	}
+
+	return report, rows.Err()
+}
+
+func scanTimeEntry(row pgx.Row) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := row.Scan(
+		&entry.ID, &entry.TaskID, &entry.UserID,
+		&entry.StartedAt, &entry.EndedAt, &entry.Note, &entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Querier is the part of the pgx API the repositories use. Both
+// *pgxpool.Pool and pgx.Tx satisfy it; Begin on a pgx.Tx starts a savepoint.
+type Querier interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+	CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error)
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+var (
+	_ Querier = (*pgxpool.Pool)(nil)
+	_ Querier = (pgx.Tx)(nil)
+)
+
+type txKey struct{}
+
+// conn returns the transaction carried by ctx, if any, so that repositories
+// called inside Transactor.WithTx take part in it. Otherwise it returns db.
+func conn(ctx context.Context, db *pgxpool.Pool) Querier {
+	if tx, ok := ctx.Value(txKey{}).(pgx.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// Transactor runs units of work spanning several repositories
+type Transactor struct {
+	db *pgxpool.Pool
+}
+
+func NewTransactor(db *pgxpool.Pool) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithTx calls fn with a context carrying a transaction. Every repository
+// call made with that context runs in the transaction, which is committed if
+// fn returns nil and rolled back otherwise. Nested calls use a savepoint, so
+// an inner failure can be handled without losing the outer transaction.
+func (t *Transactor) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return withTx(ctx, t.db, fn)
+}
+
+func withTx(ctx context.Context, db *pgxpool.Pool, fn func(ctx context.Context) error) error {
+	tx, err := conn(ctx, db).Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
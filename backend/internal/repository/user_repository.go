@@ -2,10 +2,10 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"taskboard/internal/models"
 )
@@ -27,7 +27,7 @@ func (r *UserRepository) Create(ctx context.Context, user *models.User) (*models
 		RETURNING created_at, updated_at
 	`
 	
-	err := r.db.QueryRow(ctx, query,
+	err := conn(ctx, r.db).QueryRow(ctx, query,
 		user.ID, user.Email, user.PasswordHash, user.Name,
 	).Scan(&user.CreatedAt, &user.UpdatedAt)
 	
@@ -42,17 +42,17 @@ func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User,
 	var user models.User
 	
 	query := `
-		SELECT id, email, password_hash, name, avatar, created_at, updated_at
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE id = $1
 	`
 	
-	err := r.db.QueryRow(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+		&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 	
-	if err == sql.ErrNoRows {
+	if err == pgx.ErrNoRows || isInvalidUUID(err) {
 		return nil, fmt.Errorf("user not found")
 	}
 	if err != nil {
@@ -66,17 +66,17 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 	var user models.User
 	
 	query := `
-		SELECT id, email, password_hash, name, avatar, created_at, updated_at
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
 		FROM users
 		WHERE email = $1
 	`
 	
-	err := r.db.QueryRow(ctx, query, email).Scan(
+	err := conn(ctx, r.db).QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+		&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
 	)
 	
-	if err == sql.ErrNoRows {
+	if err == pgx.ErrNoRows {
 		return nil, fmt.Errorf("user not found")
 	}
 	if err != nil {
@@ -88,12 +88,12 @@ func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.
 
 func (r *UserRepository) List(ctx context.Context) ([]*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, avatar, created_at, updated_at
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
 		FROM users
 		ORDER BY created_at DESC
 	`
 	
-	rows, err := r.db.Query(ctx, query)
+	rows, err := conn(ctx, r.db).Query(ctx, query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list users: %w", err)
 	}
@@ -104,7 +104,7 @@ func (r *UserRepository) List(ctx context.Context) ([]*models.User, error) {
 		var user models.User
 		err := rows.Scan(
 			&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-			&user.Avatar, &user.CreatedAt, &user.UpdatedAt,
+			&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan user: %w", err)
@@ -115,6 +115,48 @@ func (r *UserRepository) List(ctx context.Context) ([]*models.User, error) {
 	return users, nil
 }
 
+// ListPage returns one page of users ordered like List
+func (r *UserRepository) ListPage(ctx context.Context, limit, offset int) ([]*models.User, error) {
+	query := `
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
+		FROM users
+		ORDER BY created_at DESC
+		LIMIT $1 OFFSET $2
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+			&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	return users, nil
+}
+
+func (r *UserRepository) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := conn(ctx, r.db).QueryRow(ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	return count, nil
+}
+
 func (r *UserRepository) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.User, error) {
 	query := "UPDATE users SET updated_at = NOW()"
 	args := []interface{}{}
@@ -135,22 +177,158 @@ func (r *UserRepository) Update(ctx context.Context, id string, updates map[stri
 	query += fmt.Sprintf(" WHERE id = $%d", argPos)
 	args = append(args, id)
 	
-	_, err := r.db.Exec(ctx, query, args...)
+	var user *models.User
+	err := withTx(ctx, r.db, func(ctx context.Context) error {
+		if _, err := conn(ctx, r.db).Exec(ctx, query, args...); err != nil {
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		var err error
+		user, err = r.GetByID(ctx, id)
+		return err
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to update user: %w", err)
+		return nil, err
 	}
 	
-	return r.GetByID(ctx, id)
+	return user, nil
 }
 
 func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)"
 	
-	err := r.db.QueryRow(ctx, query, email).Scan(&exists)
+	err := conn(ctx, r.db).QueryRow(ctx, query, email).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email: %w", err)
 	}
 	
 	return exists, nil
-}
\ No newline at end of file
+}
+
+// IDsByEmail returns the IDs of the users with the given emails, keyed by
+// lowercased email. Emails without a user are left out.
+func (r *UserRepository) IDsByEmail(ctx context.Context, emails []string) (map[string]string, error) {
+	query := "SELECT lower(email), id FROM users WHERE lower(email) = ANY($1)"
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up users: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var email, id string
+		if err := rows.Scan(&email, &id); err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		ids[email] = id
+	}
+
+	return ids, rows.Err()
+}
+
+// ListByMention returns the users whose lowercased email or handle, the
+// part of the email before the "@", is one of names
+func (r *UserRepository) ListByMention(ctx context.Context, names []string) ([]*models.User, error) {
+	query := `
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
+		FROM users
+		WHERE lower(email) = ANY($1) OR lower(split_part(email, '@', 1)) = ANY($1)
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, names)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+			&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+// SetCalendarTokenHash replaces the user's calendar feed token
+func (r *UserRepository) SetCalendarTokenHash(ctx context.Context, id, tokenHash string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "UPDATE users SET calendar_token_hash = $2 WHERE id = $1", id, tokenHash)
+	if err != nil {
+		return fmt.Errorf("failed to set calendar token: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// MarkEmailVerified records that userID confirmed they receive mail at email.
+// It reports false when the email is already verified or is no longer the
+// user's, so a verification link works once.
+func (r *UserRepository) MarkEmailVerified(ctx context.Context, id, email string) (bool, error) {
+	result, err := conn(ctx, r.db).Exec(ctx, `
+		UPDATE users SET email_verified_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND email = $2 AND email_verified_at IS NULL
+	`, id, email)
+	if isInvalidUUID(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to verify email: %w", err)
+	}
+
+	return result.RowsAffected() == 1, nil
+}
+
+// SetPasswordHash replaces the user's password hash
+func (r *UserRepository) SetPasswordHash(ctx context.Context, id, passwordHash string) error {
+	result, err := conn(ctx, r.db).Exec(ctx,
+		"UPDATE users SET password_hash = $2, updated_at = NOW() WHERE id = $1", id, passwordHash,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to set password: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found")
+	}
+
+	return nil
+}
+
+// GetByCalendarTokenHash returns the owner of a calendar feed token, or nil if
+// no user has it
+func (r *UserRepository) GetByCalendarTokenHash(ctx context.Context, tokenHash string) (*models.User, error) {
+	var user models.User
+
+	query := `
+		SELECT id, email, password_hash, name, avatar, email_verified_at, created_at, updated_at
+		FROM users
+		WHERE calendar_token_hash = $1
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query, tokenHash).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+		&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
+	)
+	if err == pgx.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	return &user, nil
+}
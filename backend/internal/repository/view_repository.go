@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+var ErrViewNameTaken = errors.New("you already have a view with that name")
+
+const viewColumns = `id, owner_id, name, query, shared, pinned, created_at, updated_at`
+
+type ViewRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewViewRepository(db *pgxpool.Pool) *ViewRepository {
+	return &ViewRepository{db: db}
+}
+
+func (r *ViewRepository) Create(ctx context.Context, view *models.SavedView) (*models.SavedView, error) {
+	view.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO saved_views (id, owner_id, name, query, shared, pinned)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING created_at, updated_at
+	`
+
+	err := conn(ctx, r.db).QueryRow(ctx, query,
+		view.ID, view.OwnerID, view.Name, view.Query, view.Shared, view.Pinned,
+	).Scan(&view.CreatedAt, &view.UpdatedAt)
+
+	if isUniqueViolation(err) {
+		return nil, ErrViewNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create view: %w", err)
+	}
+
+	return view, nil
+}
+
+func (r *ViewRepository) GetByID(ctx context.Context, id string) (*models.SavedView, error) {
+	query := `SELECT ` + viewColumns + ` FROM saved_views WHERE id = $1`
+
+	view, err := scanView(conn(ctx, r.db).QueryRow(ctx, query, id))
+	if err == pgx.ErrNoRows || isInvalidUUID(err) {
+		return nil, fmt.Errorf("view not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get view: %w", err)
+	}
+
+	return view, nil
+}
+
+// ListVisible returns the views userID owns and those other users share,
+// pinned views first and then by name
+func (r *ViewRepository) ListVisible(ctx context.Context, userID string) ([]*models.SavedView, error) {
+	query := `
+		SELECT ` + viewColumns + `
+		FROM saved_views
+		WHERE owner_id = $1 OR shared
+		ORDER BY pinned DESC, lower(name), id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list views: %w", err)
+	}
+	defer rows.Close()
+
+	var views []*models.SavedView
+	for rows.Next() {
+		view, err := scanView(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan view: %w", err)
+		}
+		views = append(views, view)
+	}
+
+	return views, rows.Err()
+}
+
+func (r *ViewRepository) Update(ctx context.Context, id string, updates map[string]interface{}) (*models.SavedView, error) {
+	query := "UPDATE saved_views SET updated_at = NOW()"
+	args := []interface{}{}
+	argPos := 1
+
+	for _, column := range []string{"name", "query", "shared", "pinned"} {
+		if value, ok := updates[column]; ok {
+			query += fmt.Sprintf(", %s = $%d", column, argPos)
+			args = append(args, value)
+			argPos++
+		}
+	}
+
+	query += fmt.Sprintf(" WHERE id = $%d", argPos)
+	args = append(args, id)
+
+	result, err := conn(ctx, r.db).Exec(ctx, query, args...)
+	if isUniqueViolation(err) {
+		return nil, ErrViewNameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to update view: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, fmt.Errorf("view not found")
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+func (r *ViewRepository) Delete(ctx context.Context, id string) error {
+	result, err := conn(ctx, r.db).Exec(ctx, "DELETE FROM saved_views WHERE id = $1", id)
+	if err != nil {
+		return fmt.Errorf("failed to delete view: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("view not found")
+	}
+
+	return nil
+}
+
+// isUniqueViolation reports whether err is Postgres rejecting a duplicate key
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
+func scanView(row pgx.Row) (*models.SavedView, error) {
+	var view models.SavedView
+	err := row.Scan(
+		&view.ID, &view.OwnerID, &view.Name, &view.Query,
+		&view.Shared, &view.Pinned, &view.CreatedAt, &view.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &view, nil
+}
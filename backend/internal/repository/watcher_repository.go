@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/internal/models"
+)
+
+// WatcherRepository stores who follows which task. Creators and assignees
+// are added by a trigger on tasks, so every path that creates or assigns a
+// task, including imports and bulk changes, keeps them watching.
+type WatcherRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewWatcherRepository(db *pgxpool.Pool) *WatcherRepository {
+	return &WatcherRepository{db: db}
+}
+
+// Watch makes userID a watcher of taskID. Watching a task twice is not an
+// error.
+func (r *WatcherRepository) Watch(ctx context.Context, taskID, userID string) error {
+	var exists bool
+	err := conn(ctx, r.db).QueryRow(ctx,
+		"SELECT EXISTS(SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", taskID,
+	).Scan(&exists)
+	if isInvalidUUID(err) || (err == nil && !exists) {
+		return fmt.Errorf("task not found")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to watch task: %w", err)
+	}
+
+	_, err = conn(ctx, r.db).Exec(ctx, `
+		INSERT INTO task_watchers (task_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`, taskID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to watch task: %w", err)
+	}
+
+	return nil
+}
+
+// Unwatch stops userID watching taskID. Unwatching a task that is not
+// watched is not an error.
+func (r *WatcherRepository) Unwatch(ctx context.Context, taskID, userID string) error {
+	_, err := conn(ctx, r.db).Exec(ctx,
+		"DELETE FROM task_watchers WHERE task_id = $1 AND user_id = $2", taskID, userID,
+	)
+	if err != nil && !isInvalidUUID(err) {
+		return fmt.Errorf("failed to unwatch task: %w", err)
+	}
+
+	return nil
+}
+
+// ListWatchers returns the users watching taskID in the order they started
+func (r *WatcherRepository) ListWatchers(ctx context.Context, taskID string) ([]*models.User, error) {
+	query := `
+		SELECT u.id, u.email, u.password_hash, u.name, u.avatar, u.email_verified_at, u.created_at, u.updated_at
+		FROM task_watchers w
+		JOIN users u ON u.id = w.user_id
+		WHERE w.task_id = $1
+		ORDER BY w.created_at, u.id
+	`
+
+	rows, err := conn(ctx, r.db).Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*models.User
+	for rows.Next() {
+		var user models.User
+		err := rows.Scan(
+			&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+			&user.Avatar, &user.EmailVerifiedAt, &user.CreatedAt, &user.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan user: %w", err)
+		}
+		users = append(users, &user)
+	}
+
+	return users, rows.Err()
+}
+
+// WatcherIDs returns the IDs of the users watching taskID. It is what
+// anything notifying users of a change to a task should target.
+func (r *WatcherRepository) WatcherIDs(ctx context.Context, taskID string) ([]string, error) {
+	rows, err := conn(ctx, r.db).Query(ctx,
+		"SELECT user_id FROM task_watchers WHERE task_id = $1 ORDER BY created_at, user_id", taskID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list watchers: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to scan watcher: %w", err)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
@@ -0,0 +1,21 @@
+// Package storage keeps uploaded files in a pluggable blob store.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var ErrNotFound = errors.New("blob not found")
+
+// Blob stores opaque files under slash-separated keys
+type Blob interface {
+	// Put stores the content of r under key, replacing any existing blob. size
+	// must be the exact length of the content.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the blob's content; ErrNotFound if there is none
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob. Deleting a missing blob is not an error.
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrURLExpired       = errors.New("download link has expired")
+	ErrInvalidSignature = errors.New("invalid download link")
+)
+
+// URLSigner makes expiring links to paths that need no other credentials
+type URLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewURLSigner(secret string, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: []byte(secret), ttl: ttl}
+}
+
+// Sign returns path with expires and signature query parameters
+func (s *URLSigner) Sign(path string, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", s.signature(path, expires))
+
+	return path + "?" + query.Encode()
+}
+
+// Verify checks the expires and signature parameters of a signed link to path
+func (s *URLSigner) Verify(path string, query url.Values, now time.Time) error {
+	expires := query.Get("expires")
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if expires == "" || err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected, _ := hex.DecodeString(s.signature(path, expires))
+	if !hmac.Equal(signature, expected) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if now.After(time.Unix(unix, 0)) {
+		return ErrURLExpired
+	}
+
+	return nil
+}
+
+func (s *URLSigner) signature(path, expires string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(path + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,314 @@
+// Package tasks validates and writes tasks for both the GraphQL resolvers and
+// the REST API, so the two agree on what a task may contain and on what
+// creating or changing one sets off.
+package tasks
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"taskboard/internal/mentions"
+	"taskboard/internal/models"
+	"taskboard/internal/recurrence"
+	"taskboard/internal/repository"
+)
+
+var (
+	Statuses   = []string{"TODO", "IN_PROGRESS", "REVIEW", "DONE"}
+	Priorities = []string{"LOW", "MEDIUM", "HIGH", "URGENT"}
+)
+
+// ValidationError reports input a task cannot be created or updated with
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(message string) error {
+	return &ValidationError{Message: message}
+}
+
+// CreateInput describes a new task. Status and priority default to TODO and
+// MEDIUM.
+type CreateInput struct {
+	Title          string
+	Description    *string
+	Status         *string
+	Priority       *string
+	AssignedToID   *string
+	DueDate        *time.Time
+	Recurrence     *string
+	StoryPoints    *int
+	EstimatedHours *float64
+}
+
+// UpdateInput describes a change to a task. Nil fields are left unchanged.
+type UpdateInput struct {
+	Title        *string
+	Description  *string
+	Status       *string
+	Priority     *string
+	AssignedToID *string
+	DueDate      *time.Time
+	// An empty string stops the task from recurring
+	Recurrence     *string
+	StoryPoints    *int
+	EstimatedHours *float64
+}
+
+// Service creates and updates tasks. Callers authorize the change and
+// invalidate cached tasks themselves.
+type Service struct {
+	users    repository.UserStore
+	tasks    repository.TaskStore
+	mentions repository.MentionStore
+	tx       repository.TxRunner
+}
+
+func NewService(users repository.UserStore, tasks repository.TaskStore, mentions repository.MentionStore, tx repository.TxRunner) *Service {
+	return &Service{users: users, tasks: tasks, mentions: mentions, tx: tx}
+}
+
+// Create validates input and stores it as a task created by creatorID,
+// recording who its description mentions
+func (s *Service) Create(ctx context.Context, creatorID string, input CreateInput) (*models.Task, error) {
+	if strings.TrimSpace(input.Title) == "" {
+		return nil, invalid("title is required")
+	}
+
+	task := &models.Task{
+		Title:          input.Title,
+		Description:    input.Description,
+		Status:         "TODO",
+		Priority:       "MEDIUM",
+		CreatedByID:    creatorID,
+		AssignedToID:   input.AssignedToID,
+		DueDate:        input.DueDate,
+		StoryPoints:    input.StoryPoints,
+		EstimatedHours: input.EstimatedHours,
+	}
+	if input.Status != nil {
+		task.Status = *input.Status
+	}
+	if input.Priority != nil {
+		task.Priority = *input.Priority
+	}
+	if err := s.validate(ctx, input.Status, input.Priority, input.AssignedToID, input.StoryPoints, input.EstimatedHours); err != nil {
+		return nil, err
+	}
+	if input.Recurrence != nil && *input.Recurrence != "" {
+		rule, err := normalizeRecurrence(*input.Recurrence, input.DueDate != nil)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = &rule
+	}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.tasks.Create(ctx, task)
+		if err != nil {
+			return fmt.Errorf("failed to create task: %w", err)
+		}
+		return s.syncMentions(ctx, task, creatorID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// Update validates input against existing and applies it on behalf of userID
+func (s *Service) Update(ctx context.Context, userID string, existing *models.Task, input UpdateInput) (*models.Task, error) {
+	updates, err := s.updates(ctx, existing, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.apply(ctx, userID, existing, updates)
+}
+
+// Assign makes assigneeID, or nobody when it is nil, responsible for existing
+func (s *Service) Assign(ctx context.Context, userID string, existing *models.Task, assigneeID *string) (*models.Task, error) {
+	if assigneeID != nil {
+		if _, err := s.users.GetByID(ctx, *assigneeID); err != nil {
+			return nil, invalid("user not found")
+		}
+	}
+
+	return s.apply(ctx, userID, existing, map[string]interface{}{"assigned_to_id": assigneeID})
+}
+
+// BulkUpdate applies input to each of ids that allow accepts, like Update,
+// in one transaction. With atomic set, one failure leaves every task as it was.
+func (s *Service) BulkUpdate(ctx context.Context, userID string, ids []string, atomic bool, input UpdateInput, allow func(task *models.Task) error) ([]*repository.BulkResult, error) {
+	var results []*repository.BulkResult
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = s.tasks.BulkUpdate(ctx, ids, atomic, func(task *models.Task) (map[string]interface{}, error) {
+			if err := allow(task); err != nil {
+				return nil, err
+			}
+			return s.updates(ctx, task, input)
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if result.Err != nil || result.Task == nil {
+				continue
+			}
+			if err := s.updated(ctx, userID, result.Before, result.Task, input.Description != nil); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tasks: %w", err)
+	}
+
+	return results, nil
+}
+
+// apply writes updates to existing, and what they set off, in one transaction
+func (s *Service) apply(ctx context.Context, userID string, existing *models.Task, updates map[string]interface{}) (*models.Task, error) {
+	var task *models.Task
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		task, err = s.tasks.Update(ctx, existing.ID, updates)
+		if err != nil {
+			return fmt.Errorf("failed to update task: %w", err)
+		}
+		_, described := updates["description"]
+		return s.updated(ctx, userID, existing, task, described)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// updated follows up an update of before to task: completing an occurrence
+// schedules the next one, so the series cannot end up without a successor,
+// and a new description updates who is mentioned
+func (s *Service) updated(ctx context.Context, userID string, before, task *models.Task, described bool) error {
+	if task.Status == "DONE" && before.Status != task.Status {
+		if _, err := s.tasks.SpawnNextOccurrence(ctx, task); err != nil {
+			return fmt.Errorf("failed to schedule next occurrence: %w", err)
+		}
+	}
+	if described {
+		return s.syncMentions(ctx, task, userID)
+	}
+	return nil
+}
+
+func (s *Service) syncMentions(ctx context.Context, task *models.Task, userID string) error {
+	if err := mentions.Sync(ctx, s.users, s.mentions, task, userID); err != nil {
+		return fmt.Errorf("failed to record mentions: %w", err)
+	}
+	return nil
+}
+
+// updates validates input against the task it will be applied to and
+// converts it for TaskStore.Update
+func (s *Service) updates(ctx context.Context, existing *models.Task, input UpdateInput) (map[string]interface{}, error) {
+	if input.Title != nil && strings.TrimSpace(*input.Title) == "" {
+		return nil, invalid("title cannot be empty")
+	}
+	if err := s.validate(ctx, input.Status, input.Priority, input.AssignedToID, input.StoryPoints, input.EstimatedHours); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if input.Title != nil {
+		updates["title"] = *input.Title
+	}
+	if input.Description != nil {
+		updates["description"] = input.Description
+	}
+	if input.Status != nil {
+		updates["status"] = *input.Status
+	}
+	if input.Priority != nil {
+		updates["priority"] = *input.Priority
+	}
+	if input.AssignedToID != nil {
+		updates["assigned_to_id"] = input.AssignedToID
+	}
+	if input.DueDate != nil {
+		updates["due_date"] = input.DueDate
+	}
+	if input.StoryPoints != nil {
+		updates["story_points"] = input.StoryPoints
+	}
+	if input.EstimatedHours != nil {
+		updates["estimated_hours"] = input.EstimatedHours
+	}
+	if input.Recurrence != nil {
+		if *input.Recurrence == "" {
+			updates["recurrence_rule"] = nil
+		} else {
+			rule, err := normalizeRecurrence(*input.Recurrence, input.DueDate != nil || existing.DueDate != nil)
+			if err != nil {
+				return nil, err
+			}
+			updates["recurrence_rule"] = &rule
+		}
+	}
+
+	return updates, nil
+}
+
+// validate checks the fields creating and updating a task have in common
+func (s *Service) validate(ctx context.Context, status, priority, assigneeID *string, storyPoints *int, estimatedHours *float64) error {
+	if status != nil && !contains(Statuses, *status) {
+		return invalid("invalid status")
+	}
+	if priority != nil && !contains(Priorities, *priority) {
+		return invalid("invalid priority")
+	}
+	if assigneeID != nil {
+		if _, err := s.users.GetByID(ctx, *assigneeID); err != nil {
+			return invalid("assignee not found")
+		}
+	}
+	if storyPoints != nil && *storyPoints < 0 {
+		return invalid("story points cannot be negative")
+	}
+	if estimatedHours != nil && *estimatedHours < 0 {
+		return invalid("estimated hours cannot be negative")
+	}
+	return nil
+}
+
+// normalizeRecurrence validates a recurrence rule for a task that has a due
+// date or not
+func normalizeRecurrence(rule string, hasDueDate bool) (string, error) {
+	if !hasDueDate {
+		return "", invalid("a recurring task needs a due date")
+	}
+	normalized, err := recurrence.Normalize(rule)
+	if err != nil {
+		return "", invalid(err.Error())
+	}
+	return normalized, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+// Package verification confirms that users receive mail at the address they
+// registered with. Links carry a signed token naming the user and address, so
+// nothing is stored until the link is followed; marking the address verified
+// is what makes a link single-use.
+package verification
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"taskboard/internal/mail"
+	"taskboard/internal/models"
+)
+
+var (
+	ErrInvalidToken = errors.New("invalid verification link")
+	ErrExpiredToken = errors.New("verification link has expired")
+	// ErrNotVerified rejects changes to tasks by unverified users when
+	// verification is required
+	ErrNotVerified = errors.New("verify your email address before changing tasks")
+)
+
+// Service mails verification links and checks the tokens in them
+type Service struct {
+	secret   []byte
+	ttl      time.Duration
+	mailer   mail.Sender
+	linkURL  string
+	required bool
+}
+
+// NewService returns a service whose links point at appURL's /verify-email
+// page and expire after ttl. With required set, users cannot change tasks
+// until they have verified their address.
+func NewService(secret string, ttl time.Duration, mailer mail.Sender, appURL string, required bool) *Service {
+	return &Service{
+		secret:   []byte(secret),
+		ttl:      ttl,
+		mailer:   mailer,
+		linkURL:  strings.TrimRight(appURL, "/") + "/verify-email",
+		required: required,
+	}
+}
+
+// Required reports whether unverified users are kept from changing tasks
+func (s *Service) Required() bool {
+	return s.required
+}
+
+// Send mails user a link to verify their address
+func (s *Service) Send(ctx context.Context, user *models.User) error {
+	link := s.linkURL + "?token=" + url.QueryEscape(s.Token(user.ID, user.Email, time.Now()))
+
+	return s.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen this link to confirm this is your email address:\n\n%s\n\n"+
+			"The link expires in %s. If you did not sign up, ignore this email.\n",
+			user.Name, link, s.ttl),
+	})
+}
+
+// Token returns a token proving its holder received mail for userID at email
+func (s *Service) Token(userID, email string, now time.Time) string {
+	payload := userID + "\n" + email + "\n" + strconv.FormatInt(now.Add(s.ttl).Unix(), 10)
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + s.signature(payload)
+}
+
+// Check returns the user and address token was issued for
+func (s *Service) Check(token string, now time.Time) (userID, email string, err error) {
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return "", "", ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	if !hmac.Equal([]byte(signature), []byte(s.signature(string(payload)))) {
+		return "", "", ErrInvalidToken
+	}
+
+	parts := strings.Split(string(payload), "\n")
+	if len(parts) != 3 {
+		return "", "", ErrInvalidToken
+	}
+	expires, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", "", ErrInvalidToken
+	}
+	if now.After(time.Unix(expires, 0)) {
+		return "", "", ErrExpiredToken
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func (s *Service) signature(payload string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte("verify-email\n" + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
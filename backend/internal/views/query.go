@@ -0,0 +1,224 @@
+// Package views defines how saved views store their task filter and sort.
+// Queries are serialized as JSON carrying a format version, so a view saved
+// today still loads after the filter grows new fields.
+package views
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Version is the query format written by Encode. Adding an optional field
+// does not change it: older documents simply lack the field. A change that
+// alters the meaning of existing documents must bump Version and teach Decode
+// to convert the older format.
+const Version = 1
+
+var ErrUnsupportedVersion = errors.New("saved view uses an unsupported format version")
+
+// Query is the filter and sort a saved view applies to the task list
+type Query struct {
+	Version int    `json:"version"`
+	Filter  Filter `json:"filter"`
+	// Sort is nil for the default order, newest first
+	Sort *Sort `json:"sort,omitempty"`
+}
+
+// Filter selects tasks. Empty fields match every task, and a task must
+// match every field that is set.
+type Filter struct {
+	Status       string `json:"status,omitempty"`
+	Priority     string `json:"priority,omitempty"`
+	AssignedToID string `json:"assigned_to_id,omitempty"`
+	CreatedByID  string `json:"created_by_id,omitempty"`
+	// IncludeArchived only has an effect on the outermost filter
+	IncludeArchived bool `json:"include_archived,omitempty"`
+
+	StatusIn   []string `json:"status_in,omitempty"`
+	PriorityIn []string `json:"priority_in,omitempty"`
+
+	DueBefore    *time.Time `json:"due_before,omitempty"`
+	DueAfter     *time.Time `json:"due_after,omitempty"`
+	CreatedAfter *time.Time `json:"created_after,omitempty"`
+	UpdatedSince *time.Time `json:"updated_since,omitempty"`
+
+	// Overdue selects tasks past their due date and not done, or when
+	// false, every other task
+	Overdue *bool `json:"overdue,omitempty"`
+	// Unassigned selects tasks without an assignee, or when false, with one
+	Unassigned *bool `json:"unassigned,omitempty"`
+
+	// TitleContains matches titles case-insensitively
+	TitleContains string `json:"title_contains,omitempty"`
+
+	And []Filter `json:"and,omitempty"`
+	Or  []Filter `json:"or,omitempty"`
+	Not *Filter  `json:"not,omitempty"`
+}
+
+// MaxDepth is how deeply and, or and not may be nested
+const MaxDepth = 5
+
+// Validate rejects filters that cannot be stored faithfully or are nested
+// too deeply
+func (f Filter) Validate() error {
+	return f.validate(0)
+}
+
+func (f Filter) validate(depth int) error {
+	if depth > MaxDepth {
+		return fmt.Errorf("filters may be nested at most %d levels deep", MaxDepth)
+	}
+	// An empty list would match nothing, but is dropped when stored
+	if f.StatusIn != nil && len(f.StatusIn) == 0 {
+		return fmt.Errorf("statusIn must not be empty")
+	}
+	if f.PriorityIn != nil && len(f.PriorityIn) == 0 {
+		return fmt.Errorf("priorityIn must not be empty")
+	}
+
+	for _, sub := range append(append([]Filter{}, f.And...), f.Or...) {
+		if err := sub.validate(depth + 1); err != nil {
+			return err
+		}
+	}
+	if f.Not != nil {
+		return f.Not.validate(depth + 1)
+	}
+	return nil
+}
+
+// Sort orders tasks by one of SortFields
+type Sort struct {
+	Field      string `json:"field"`
+	Descending bool   `json:"descending,omitempty"`
+}
+
+// SortFields are the task columns a view can be sorted by
+var SortFields = []string{"created_at", "updated_at", "due_date", "priority", "title"}
+
+// Encode serializes q in the current format
+func Encode(q Query) ([]byte, error) {
+	if err := q.Filter.Validate(); err != nil {
+		return nil, err
+	}
+	if q.Sort != nil && !isSortField(q.Sort.Field) {
+		return nil, fmt.Errorf("unknown sort field %q", q.Sort.Field)
+	}
+
+	q.Version = Version
+	data, err := json.Marshal(q)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode view: %w", err)
+	}
+	return data, nil
+}
+
+// Decode parses a query written by Encode in this or an earlier version.
+// Unknown fields are ignored.
+func Decode(data []byte) (Query, error) {
+	var q Query
+	if err := json.Unmarshal(data, &q); err != nil {
+		return Query{}, fmt.Errorf("failed to decode view: %w", err)
+	}
+
+	if q.Version < 1 || q.Version > Version {
+		return Query{}, fmt.Errorf("%w: %d", ErrUnsupportedVersion, q.Version)
+	}
+	if q.Sort != nil && !isSortField(q.Sort.Field) {
+		q.Sort = nil
+	}
+
+	return q, nil
+}
+
+// Map returns the filter in the form TaskRepository.List accepts
+func (f Filter) Map() map[string]interface{} {
+	filter := f.conditions()
+	if f.IncludeArchived {
+		filter["include_archived"] = true
+	}
+	return filter
+}
+
+// conditions returns the filter's conditions, leaving out IncludeArchived
+func (f Filter) conditions() map[string]interface{} {
+	filter := make(map[string]interface{})
+
+	for column, value := range map[string]string{
+		"status":         f.Status,
+		"priority":       f.Priority,
+		"assigned_to_id": f.AssignedToID,
+		"created_by_id":  f.CreatedByID,
+	} {
+		if value != "" {
+			filter[column] = value
+		}
+	}
+
+	if len(f.StatusIn) > 0 {
+		filter["status_in"] = f.StatusIn
+	}
+	if len(f.PriorityIn) > 0 {
+		filter["priority_in"] = f.PriorityIn
+	}
+
+	for key, value := range map[string]*time.Time{
+		"due_before":    f.DueBefore,
+		"due_after":     f.DueAfter,
+		"created_after": f.CreatedAfter,
+		"updated_since": f.UpdatedSince,
+	} {
+		if value != nil {
+			filter[key] = *value
+		}
+	}
+
+	if f.Overdue != nil {
+		filter["overdue"] = *f.Overdue
+	}
+	if f.Unassigned != nil {
+		filter["unassigned"] = *f.Unassigned
+	}
+	if f.TitleContains != "" {
+		filter["title_contains"] = f.TitleContains
+	}
+
+	for key, subs := range map[string][]Filter{"and": f.And, "or": f.Or} {
+		if len(subs) == 0 {
+			continue
+		}
+		conditions := make([]map[string]interface{}, len(subs))
+		for i, sub := range subs {
+			conditions[i] = sub.conditions()
+		}
+		filter[key] = conditions
+	}
+	if f.Not != nil {
+		filter["not"] = f.Not.conditions()
+	}
+
+	return filter
+}
+
+// TaskFilter returns the filter and sort in the form TaskRepository.List
+// accepts
+func (q Query) TaskFilter() map[string]interface{} {
+	filter := q.Filter.Map()
+	if q.Sort != nil {
+		filter["sort"] = q.Sort.Field
+		filter["sort_desc"] = q.Sort.Descending
+	}
+	return filter
+}
+
+func isSortField(field string) bool {
+	for _, f := range SortFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,211 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+// exec runs sql against the fixture's database, for setting up rows the
+// repositories have no way to write, such as past timestamps
+func (f *postgresFixture) exec(t *testing.T, sql string, args ...interface{}) {
+	if _, err := f.db.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatalf("Exec() error = %v", err)
+	}
+}
+
+func TestAnalyticsRepository_Stats(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewAnalyticsRepository(f.db)
+
+	overdue := f.createTask(t, f.alice, "Overdue")
+	f.exec(t, `UPDATE tasks SET priority = 'HIGH', assigned_to_id = $2, due_date = NOW() - INTERVAL '1 day' WHERE id = $1`, overdue.ID, f.alice.ID)
+	doneLate := f.createTask(t, f.alice, "Done late")
+	f.exec(t, `UPDATE tasks SET status = 'DONE', assigned_to_id = $2, due_date = NOW() - INTERVAL '1 day' WHERE id = $1`, doneLate.ID, f.alice.ID)
+	review := f.createTask(t, f.alice, "Review")
+	f.exec(t, `UPDATE tasks SET status = 'IN_PROGRESS', assigned_to_id = $2 WHERE id = $1`, review.ID, f.bob.ID)
+	f.createTask(t, f.alice, "Unassigned")
+	deleted := f.createTask(t, f.alice, "Deleted")
+	f.exec(t, `UPDATE tasks SET assigned_to_id = $2, deleted_at = NOW() WHERE id = $1`, deleted.ID, f.bob.ID)
+
+	stats, err := repo.Stats(context.Background())
+	if err != nil {
+		t.Fatalf("Stats() error = %v", err)
+	}
+
+	if stats.Total != 4 {
+		t.Errorf("Expected 4 tasks, got %d", stats.Total)
+	}
+	if stats.Overdue != 1 {
+		t.Errorf("Expected 1 overdue task, got %d", stats.Overdue)
+	}
+
+	tests := []struct {
+		name    string
+		buckets []*models.CountBucket
+		want    []models.CountBucket
+	}{
+		{"status", stats.ByStatus, []models.CountBucket{
+			{Key: "TODO", Label: "TODO", Count: 2},
+			{Key: "DONE", Label: "DONE", Count: 1},
+			{Key: "IN_PROGRESS", Label: "IN_PROGRESS", Count: 1},
+		}},
+		{"priority", stats.ByPriority, []models.CountBucket{
+			{Key: "MEDIUM", Label: "MEDIUM", Count: 3},
+			{Key: "HIGH", Label: "HIGH", Count: 1},
+		}},
+		{"assignee", stats.ByAssignee, []models.CountBucket{
+			{Key: f.alice.ID, Label: "alice", Count: 2},
+			{Key: "", Label: "Unassigned", Count: 1},
+			{Key: f.bob.ID, Label: "bob", Count: 1},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if len(tc.buckets) != len(tc.want) {
+				t.Fatalf("Expected %d buckets, got %d", len(tc.want), len(tc.buckets))
+			}
+			for i, bucket := range tc.buckets {
+				if *bucket != tc.want[i] {
+					t.Errorf("Bucket %d = %+v, want %+v", i, *bucket, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyticsRepository_Throughput(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewAnalyticsRepository(f.db)
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+	}
+	for _, task := range []struct {
+		title     string
+		created   time.Time
+		completed *time.Time
+		deleted   bool
+	}{
+		{"Before the range", at(0, 12), nil, false},
+		{"Created day 1", at(1, 9), nil, false},
+		{"Created and completed day 1", at(1, 10), timePtr(at(1, 18)), false},
+		{"Completed day 3", at(0, 10), timePtr(at(3, 8)), false},
+		{"Deleted", at(2, 10), timePtr(at(2, 11)), true},
+	} {
+		created := f.createTask(t, f.alice, task.title)
+		f.exec(t, `UPDATE tasks SET created_at = $2, completed_at = $3 WHERE id = $1`, created.ID, task.created, task.completed)
+		if task.deleted {
+			f.exec(t, `UPDATE tasks SET deleted_at = NOW() WHERE id = $1`, created.ID)
+		}
+	}
+
+	// from is rounded down to midnight
+	days, err := repo.Throughput(context.Background(), at(1, 15), at(4, 0))
+	if err != nil {
+		t.Fatalf("Throughput() error = %v", err)
+	}
+
+	want := []struct {
+		day                                                        time.Time
+		created, completed, cumulativeCreated, cumulativeCompleted int
+	}{
+		{at(1, 0), 2, 1, 2, 1},
+		{at(2, 0), 0, 0, 2, 1},
+		{at(3, 0), 0, 1, 2, 2},
+	}
+	if len(days) != len(want) {
+		t.Fatalf("Expected %d days, got %d", len(want), len(days))
+	}
+	for i, day := range days {
+		w := want[i]
+		if !day.Date.Equal(w.day) || day.Created != w.created || day.Completed != w.completed ||
+			day.CumulativeCreated != w.cumulativeCreated || day.CumulativeCompleted != w.cumulativeCompleted {
+			t.Errorf("Day %d = %+v, want %+v", i, *day, w)
+		}
+	}
+}
+
+func TestAnalyticsRepository_CycleTimes(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewAnalyticsRepository(f.db)
+
+	at := func(day, hour int) time.Time {
+		return time.Date(2024, time.March, day, hour, 0, 0, 0, time.UTC)
+	}
+	complete := func(title string, assignee *models.User, created, completed time.Time, started ...time.Time) {
+		task := f.createTask(t, f.alice, title)
+		f.exec(t, `UPDATE tasks SET status = 'DONE', assigned_to_id = $2, created_at = $3, completed_at = $4 WHERE id = $1`,
+			task.ID, assignee.ID, created, completed)
+		for _, s := range started {
+			f.exec(t, `INSERT INTO task_status_history (task_id, status, changed_at) VALUES ($1, 'IN_PROGRESS', $2)`, task.ID, s)
+		}
+	}
+
+	// Cycle time runs from the first time the task entered IN_PROGRESS
+	complete("Reopened", f.alice, at(1, 0), at(1, 10), at(1, 2), at(1, 6))
+	complete("Quick", f.alice, at(1, 0), at(1, 4), at(1, 2))
+	// Straight to DONE only counts towards lead time
+	complete("Straight to done", f.bob, at(1, 0), at(1, 6))
+	complete("Outside the range", f.bob, at(1, 0), at(9, 0), at(1, 1))
+
+	cycleTimes, err := repo.CycleTimes(context.Background(), at(1, 0), at(5, 0))
+	if err != nil {
+		t.Fatalf("CycleTimes() error = %v", err)
+	}
+	if len(cycleTimes) != 2 {
+		t.Fatalf("Expected 2 assignees, got %d", len(cycleTimes))
+	}
+
+	alice := cycleTimes[0]
+	if alice.UserID == nil || *alice.UserID != f.alice.ID || alice.CompletedCount != 2 {
+		t.Fatalf("Expected alice's 2 tasks first, got %+v", alice)
+	}
+	if alice.AverageCycleTime == nil || *alice.AverageCycleTime != 5*3600 {
+		t.Errorf("Expected an average cycle time of 5h, got %v", alice.AverageCycleTime)
+	}
+	if alice.AverageLeadTime != 7*3600 {
+		t.Errorf("Expected an average lead time of 7h, got %v", alice.AverageLeadTime)
+	}
+
+	bob := cycleTimes[1]
+	if bob.CompletedCount != 1 || bob.AverageCycleTime != nil || bob.AverageLeadTime != 6*3600 {
+		t.Errorf("Expected bob's task to count towards lead time only, got %+v", bob)
+	}
+}
+
+// The Postgres fixture has no cache, so these also cover running without Redis
+func TestResolver_AnalyticsWithoutCache(t *testing.T) {
+	f := newPostgresFixture(t)
+	f.createTask(t, f.alice, "Write report")
+	from, to := time.Now().Add(-24*time.Hour), time.Now().Add(time.Hour)
+
+	stats, err := f.resolver.Query().TaskStats(as(f.alice))
+	if err != nil {
+		t.Fatalf("TaskStats() error = %v", err)
+	}
+	if stats.Total != 1 {
+		t.Errorf("Expected 1 task, got %d", stats.Total)
+	}
+
+	days, err := f.resolver.Query().TaskThroughput(as(f.alice), from, to)
+	if err != nil {
+		t.Fatalf("TaskThroughput() error = %v", err)
+	}
+	if len(days) == 0 || days[len(days)-1].CumulativeCreated != 1 {
+		t.Errorf("Expected the task to be counted as created, got %d days", len(days))
+	}
+
+	if _, err := f.resolver.Query().CycleTimes(as(f.alice), from, to); err != nil {
+		t.Fatalf("CycleTimes() error = %v", err)
+	}
+	if _, err := f.resolver.Query().CycleTimes(as(f.alice), to, from); err == nil {
+		t.Error("Expected an empty range to be rejected")
+	}
+	if _, err := f.resolver.Query().TaskStats(context.Background()); err == nil {
+		t.Error("Expected taskStats to require authentication")
+	}
+}
@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image/color"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"taskboard/internal/api"
+	"taskboard/internal/avatars"
+	"taskboard/internal/repository"
+	"taskboard/internal/storage"
+	"taskboard/internal/tasks"
+)
+
+func TestAPI_OpenAPIDocument(t *testing.T) {
+	handler := api.NewHandler(nil, nil, nil, nil, nil, false)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+
+	var doc struct {
+		OpenAPI    string                            `json:"openapi"`
+		Paths      map[string]map[string]interface{} `json:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `json:"schemas"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to decode document: %v", err)
+	}
+
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		t.Errorf("Expected OpenAPI 3 document, got %q", doc.OpenAPI)
+	}
+	if _, ok := doc.Paths["/api/v1/tasks/{id}"]["patch"]; !ok {
+		t.Error("Expected PATCH /api/v1/tasks/{id} to be documented")
+	}
+	for _, name := range []string{"TaskWithRelations", "User", "CreateTaskRequest", "Error"} {
+		if _, ok := doc.Components.Schemas[name]; !ok {
+			t.Errorf("Expected schema %s to be generated", name)
+		}
+	}
+
+	// A matching If-None-Match yields 304
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/openapi.json", nil)
+	req.Header.Set("If-None-Match", w.Header().Get("ETag"))
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+}
+
+func TestAPI_StatusCodes(t *testing.T) {
+	handler := api.NewHandler(nil, nil, nil, nil, nil, false)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{"Create without auth", http.MethodPost, "/api/v1/tasks", `{"title":"x"}`, http.StatusUnauthorized},
+		{"Update without auth", http.MethodPatch, "/api/v1/tasks/123", `{}`, http.StatusUnauthorized},
+		{"Me without auth", http.MethodGet, "/api/v1/users/me", "", http.StatusUnauthorized},
+		{"Unsupported method", http.MethodPut, "/api/v1/tasks", "", http.StatusMethodNotAllowed},
+		{"Invalid limit", http.MethodGet, "/api/v1/tasks?limit=0", "", http.StatusBadRequest},
+		{"Invalid include_archived", http.MethodGet, "/api/v1/tasks?include_archived=maybe", "", http.StatusBadRequest},
+		{"Unknown route", http.MethodGet, "/api/v1/nothing", "", http.StatusNotFound},
+		{"Export without auth", http.MethodGet, "/api/v1/exports/tasks", "", http.StatusUnauthorized},
+		{"Unknown export", http.MethodGet, "/api/v1/exports/abc123", "", http.StatusNotFound},
+		{"Import without auth", http.MethodPost, "/api/v1/imports/tasks", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("Expected status %d, got %d", tt.want, w.Code)
+			}
+		})
+	}
+}
+
+func TestAPI_UpdateMeDeletesOldAvatar(t *testing.T) {
+	f := newPostgresFixture(t)
+	ctx := context.Background()
+	blob, err := storage.NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+	service := avatars.NewService(blob, 1<<20)
+	userRepo := repository.NewUserRepository(f.db)
+	handler := api.NewHandler(userRepo, repository.NewTaskRepository(f.db), nil, service, nil, false)
+
+	avatar, err := service.Upload(ctx, f.alice.ID, bytes.NewReader(encodeTestPNG(t, 200, 200, color.Black)))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if _, err := userRepo.Update(ctx, f.alice.ID, map[string]interface{}{"avatar": avatar}); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/users/me", strings.NewReader(`{"avatar":""}`)).WithContext(as(f.alice))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+
+	// Every thumbnail of the cleared avatar is gone
+	serve := avatars.NewHandler(service)
+	for _, size := range avatars.Sizes {
+		url, _ := avatars.URL(avatar, size)
+		w := httptest.NewRecorder()
+		serve.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+		if w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for %s, got %d", url, w.Code)
+		}
+	}
+}
+
+func TestAPI_TaskMentions(t *testing.T) {
+	f := newPostgresFixture(t)
+	mentionRepo := repository.NewMentionRepository(f.db)
+	userRepo, taskRepo := repository.NewUserRepository(f.db), repository.NewTaskRepository(f.db)
+	taskService := tasks.NewService(userRepo, taskRepo, mentionRepo, repository.NewTransactor(f.db))
+	handler := api.NewHandler(userRepo, taskRepo, taskService, nil, nil, false)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(as(f.alice))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	mentioned := func(taskID string) []string {
+		list, err := mentionRepo.ListForTask(context.Background(), taskID)
+		if err != nil {
+			t.Fatalf("ListForTask() error = %v", err)
+		}
+		var ids []string
+		for _, mention := range list {
+			ids = append(ids, mention.UserID)
+		}
+		return ids
+	}
+
+	w := send(http.MethodPost, "/api/v1/tasks", `{"title":"Review","description":"@bob please review"}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body)
+	}
+	var task struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &task); err != nil {
+		t.Fatalf("Failed to decode task: %v", err)
+	}
+	if got := mentioned(task.ID); len(got) != 1 || got[0] != f.bob.ID {
+		t.Errorf("Expected bob to be mentioned, got %v", got)
+	}
+
+	// Changes that leave the description alone keep the mentions
+	if w := send(http.MethodPatch, "/api/v1/tasks/"+task.ID, `{"title":"Review again"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	if got := mentioned(task.ID); len(got) != 1 {
+		t.Errorf("Expected the mention to be kept, got %v", got)
+	}
+
+	if w := send(http.MethodPatch, "/api/v1/tasks/"+task.ID, `{"description":"@alice, over to you"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	if got := mentioned(task.ID); len(got) != 1 || got[0] != f.alice.ID {
+		t.Errorf("Expected only alice to be mentioned, got %v", got)
+	}
+}
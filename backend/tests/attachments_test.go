@@ -0,0 +1,114 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"taskboard/internal/attachments"
+)
+
+var errStopUpload = errors.New("stop upload")
+
+// recordingBlob records what Put is given and then fails, so uploads stop
+// before their metadata would be saved
+type recordingBlob struct {
+	puts    int
+	size    int64
+	content string
+}
+
+func (b *recordingBlob) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	content, _ := io.ReadAll(r)
+	b.puts++
+	b.size = size
+	b.content = string(content)
+	return errStopUpload
+}
+
+func (b *recordingBlob) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (b *recordingBlob) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestAttachments_CheckType(t *testing.T) {
+	service := attachments.NewService(nil, nil, nil, attachments.Limits{
+		MaxBytes:     1 << 20,
+		AllowedTypes: []string{"image/png", "text/plain"},
+	})
+
+	tests := []struct {
+		name    string
+		head    []byte
+		want    string
+		wantErr bool
+	}{
+		{"PNG", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"), "image/png", false},
+		{"Log file", []byte("2026-03-01 12:00:00 ERROR something failed\n"), "text/plain", false},
+		{"HTML", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), "", true},
+		{"PDF not allowed", []byte("%PDF-1.7\n"), "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.CheckType(tt.head)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CheckType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, attachments.ErrTypeNotAllowed) {
+				t.Errorf("Expected ErrTypeNotAllowed, got %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CheckType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAttachments_UploadIgnoresDeclaredSize(t *testing.T) {
+	ctx := context.Background()
+	blob := &recordingBlob{}
+	service := attachments.NewService(nil, blob, nil, attachments.Limits{
+		MaxBytes:     16,
+		AllowedTypes: []string{"text/plain"},
+	})
+
+	tests := []struct {
+		name     string
+		content  string
+		declared int64
+	}{
+		{"Declared too large", "short note", 16},
+		{"Declared too small", "short note", 2},
+		{"Empty", "", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			*blob = recordingBlob{}
+			_, err := service.Upload(ctx, "task", "user", "notes.txt", strings.NewReader(tt.content), tt.declared)
+			if !errors.Is(err, errStopUpload) {
+				t.Fatalf("Upload() error = %v, want the blob's error", err)
+			}
+			if blob.size != int64(len(tt.content)) || blob.content != tt.content {
+				t.Errorf("Expected Put to get %d bytes %q, got size %d and %q", len(tt.content), tt.content, blob.size, blob.content)
+			}
+		})
+	}
+
+	t.Run("Over the limit despite its declared size", func(t *testing.T) {
+		*blob = recordingBlob{}
+		_, err := service.Upload(ctx, "task", "user", "notes.txt", strings.NewReader(strings.Repeat("a", 17)), 5)
+		if !errors.Is(err, attachments.ErrTooLarge) {
+			t.Fatalf("Upload() error = %v, want ErrTooLarge", err)
+		}
+		if blob.puts != 0 {
+			t.Error("Expected nothing to be stored")
+		}
+	})
+}
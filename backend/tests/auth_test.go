@@ -2,7 +2,6 @@ package tests
 
 import (
 	"testing"
-	"time"
 
 	"taskboard/internal/auth"
 )
@@ -12,8 +11,9 @@ func TestJWTManager_GenerateAndValidateAccessToken(t *testing.T) {
 	
 	userID := "test-user-123"
 	email := "test@example.com"
+	sessionID := "test-session-456"
 	
-	token, err := manager.GenerateAccessToken(userID, email)
+	token, err := manager.GenerateAccessToken(userID, email, sessionID)
 	if err != nil {
 		t.Fatalf("Failed to generate access token: %v", err)
 	}
@@ -30,6 +30,10 @@ func TestJWTManager_GenerateAndValidateAccessToken(t *testing.T) {
 	if claims.Email != email {
 		t.Errorf("Expected email %s, got %s", email, claims.Email)
 	}
+
+	if claims.SessionID != sessionID {
+		t.Errorf("Expected session ID %s, got %s", sessionID, claims.SessionID)
+	}
 }
 
 func TestJWTManager_ExpiredToken(t *testing.T) {
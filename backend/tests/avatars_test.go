@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"taskboard/internal/avatars"
+	"taskboard/internal/storage"
+)
+
+const avatarUserID = "7f2c1c6e-3b8a-4d55-9a52-0d6c2b1e9f10"
+
+func encodeTestPNG(t *testing.T, width, height int, fill color.Color) []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("Failed to encode test image: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestAvatars_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		content []byte
+		want    error
+	}{
+		{"PNG", encodeTestPNG(t, 40, 20, color.White), nil},
+		{"Not an image", []byte("<svg onload=alert(1)></svg>"), avatars.ErrNotImage},
+		{"Oversized file", encodeTestPNG(t, 300, 300, color.White), avatars.ErrTooLarge},
+		{"Too many pixels", encodeTestPNG(t, 5000, 1, color.White), avatars.ErrTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			maxBytes := int64(64 << 10)
+			if tt.name == "Oversized file" {
+				maxBytes = 100
+			}
+
+			_, err := avatars.Decode(bytes.NewReader(tt.content), maxBytes)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected error %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestAvatars_Thumbnail(t *testing.T) {
+	// A wide image whose outer thirds are red and middle third is blue; the
+	// centre crop must keep only the blue part
+	src := image.NewNRGBA(image.Rect(0, 0, 300, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 300; x++ {
+			c := color.NRGBA{R: 255, A: 255}
+			if x >= 100 && x < 200 {
+				c = color.NRGBA{B: 255, A: 255}
+			}
+			src.Set(x, y, c)
+		}
+	}
+
+	for _, size := range []int{32, 256} {
+		thumb := avatars.Thumbnail(src, size)
+		if thumb.Bounds().Dx() != size || thumb.Bounds().Dy() != size {
+			t.Errorf("Expected %dx%d thumbnail, got %v", size, size, thumb.Bounds())
+		}
+		for _, pt := range []image.Point{{0, 0}, {size - 1, size - 1}, {size / 2, size / 2}} {
+			if got := thumb.NRGBAAt(pt.X, pt.Y); got != (color.NRGBA{B: 255, A: 255}) {
+				t.Errorf("Expected blue at %v of %dpx thumbnail, got %v", pt, size, got)
+			}
+		}
+	}
+
+	// Transparent pixels must not darken the colour they are averaged with
+	half := image.NewNRGBA(image.Rect(0, 0, 2, 2))
+	half.Set(0, 0, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+	thumb := avatars.Thumbnail(half, 1)
+	if got := thumb.NRGBAAt(0, 0); got.R != 255 || got.A != 64 {
+		t.Errorf("Expected white at quarter opacity, got %v", got)
+	}
+}
+
+func TestAvatars_URL(t *testing.T) {
+	avatar := avatars.BasePath + avatarUserID + "/0b0e5c8a-3f43-4a4b-8f1e-63a2f0d4a111/128.png"
+
+	tests := []struct {
+		size int
+		want string
+	}{
+		{16, "/32.png"},
+		{64, "/64.png"},
+		{100, "/128.png"},
+		{1024, "/256.png"},
+	}
+
+	for _, tt := range tests {
+		got, ok := avatars.URL(avatar, tt.size)
+		if !ok || !strings.HasSuffix(got, tt.want) {
+			t.Errorf("Expected URL for size %d to end in %s, got %q", tt.size, tt.want, got)
+		}
+	}
+
+	for _, external := range []string{
+		"https://tracker.example.com/pixel.gif",
+		"/avatars/../attachments/x/y.png",
+		avatars.BasePath + avatarUserID + "/0b0e5c8a-3f43-4a4b-8f1e-63a2f0d4a111/100.png",
+	} {
+		if _, ok := avatars.URL(external, 64); ok {
+			t.Errorf("Expected %q to be rejected", external)
+		}
+	}
+
+	if !avatars.OwnedBy(avatar, avatarUserID) {
+		t.Error("Expected avatar to belong to its uploader")
+	}
+	if avatars.OwnedBy(avatar, "1d5b7e0c-2a3f-4c6d-8e9f-0a1b2c3d4e5f") {
+		t.Error("Expected avatar not to belong to another user")
+	}
+}
+
+func TestAvatars_UploadAndServe(t *testing.T) {
+	ctx := context.Background()
+	blob, err := storage.NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+	service := avatars.NewService(blob, 1<<20)
+
+	avatar, err := service.Upload(ctx, avatarUserID, bytes.NewReader(encodeTestPNG(t, 500, 400, color.Black)))
+	if err != nil {
+		t.Fatalf("Upload() error = %v", err)
+	}
+	if !avatars.OwnedBy(avatar, avatarUserID) || !strings.HasSuffix(avatar, "/128.png") {
+		t.Fatalf("Expected default-size avatar URL, got %q", avatar)
+	}
+
+	handler := avatars.NewHandler(service)
+	for _, size := range avatars.Sizes {
+		url, _ := avatars.URL(avatar, size)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, url, nil))
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", url, w.Code)
+		}
+		img, err := png.Decode(w.Body)
+		if err != nil {
+			t.Fatalf("Expected PNG for %s: %v", url, err)
+		}
+		if img.Bounds().Dx() != size {
+			t.Errorf("Expected %dpx thumbnail, got %dpx", size, img.Bounds().Dx())
+		}
+	}
+
+	service.Delete(ctx, avatar)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, avatar, nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after delete, got %d", w.Code)
+	}
+}
@@ -0,0 +1,196 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"taskboard/graph/model"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+func TestResolver_BulkOperations(t *testing.T) {
+	done := model.TaskStatusDone
+
+	tests := []struct {
+		name string
+		run  func(f *resolverFixture, ids []string, atomic bool) (*model.BulkTaskPayload, error)
+		// applied reports whether the change reached the task
+		applied func(f *resolverFixture, id string) bool
+	}{
+		{
+			name: "update",
+			run: func(f *resolverFixture, ids []string, atomic bool) (*model.BulkTaskPayload, error) {
+				return f.resolver.Mutation().BulkUpdateTasks(as(f.alice), ids, model.UpdateTaskInput{Status: &done}, atomic)
+			},
+			applied: func(f *resolverFixture, id string) bool {
+				task, err := f.store.Tasks().GetByID(context.Background(), id)
+				return err == nil && task.Status == "DONE"
+			},
+		},
+		{
+			name: "delete",
+			run: func(f *resolverFixture, ids []string, atomic bool) (*model.BulkTaskPayload, error) {
+				return f.resolver.Mutation().BulkDeleteTasks(as(f.alice), ids, atomic)
+			},
+			applied: func(f *resolverFixture, id string) bool {
+				_, err := f.store.Tasks().GetByID(context.Background(), id)
+				return err != nil
+			},
+		},
+		{
+			name: "assign",
+			run: func(f *resolverFixture, ids []string, atomic bool) (*model.BulkTaskPayload, error) {
+				return f.resolver.Mutation().BulkAssignTasks(as(f.alice), ids, &f.bob.ID, atomic)
+			},
+			applied: func(f *resolverFixture, id string) bool {
+				task, err := f.store.Tasks().GetByID(context.Background(), id)
+				return err == nil && task.AssignedToID != nil && *task.AssignedToID == f.bob.ID
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name+" all or nothing", func(t *testing.T) {
+			f := newResolverFixture(t)
+			mine := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Mine"})
+			theirs := f.createTask(t, f.bob, model.CreateTaskInput{Title: "Theirs"})
+
+			payload, err := tc.run(f, []string{mine.ID, theirs.ID, "missing"}, true)
+			if err != nil {
+				t.Fatalf("Bulk operation error = %v", err)
+			}
+			if payload.Succeeded != 0 || payload.Failed != 3 {
+				t.Errorf("Expected the whole batch to fail, got %d succeeded and %d failed", payload.Succeeded, payload.Failed)
+			}
+			if payload.Results[0].Error == nil || *payload.Results[0].Error != repository.ErrBulkRolledBack.Error() {
+				t.Errorf("Expected the allowed task to be rolled back, got %v", payload.Results[0].Error)
+			}
+			if tc.applied(f, mine.ID) {
+				t.Error("Expected the rolled back change to be undone")
+			}
+		})
+
+		t.Run(tc.name+" per task", func(t *testing.T) {
+			f := newResolverFixture(t)
+			mine := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Mine"})
+			theirs := f.createTask(t, f.bob, model.CreateTaskInput{Title: "Theirs"})
+
+			// Duplicates are applied once
+			payload, err := tc.run(f, []string{mine.ID, theirs.ID, mine.ID, "missing"}, false)
+			if err != nil {
+				t.Fatalf("Bulk operation error = %v", err)
+			}
+			if len(payload.Results) != 3 || payload.Succeeded != 1 || payload.Failed != 2 {
+				t.Fatalf("Expected one success and two failures, got %d results, %d succeeded and %d failed",
+					len(payload.Results), payload.Succeeded, payload.Failed)
+			}
+			if payload.Results[0].Error != nil || !tc.applied(f, mine.ID) {
+				t.Errorf("Expected the allowed task to be changed, got %v", payload.Results[0].Error)
+			}
+			if payload.Results[1].Error == nil || tc.applied(f, theirs.ID) {
+				t.Error("Expected another user's task to be left alone")
+			}
+			if payload.Results[2].Error == nil || *payload.Results[2].Error != "task not found" {
+				t.Errorf("Expected an unknown task to be reported, got %v", payload.Results[2].Error)
+			}
+		})
+
+		t.Run(tc.name+" limit", func(t *testing.T) {
+			f := newResolverFixture(t)
+			task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Mine"})
+
+			ids := make([]string, repository.MaxBulkTasks+1)
+			for i := range ids {
+				ids[i] = task.ID
+			}
+			if _, err := tc.run(f, ids, false); err == nil {
+				t.Errorf("Expected more than %d tasks to be rejected", repository.MaxBulkTasks)
+			}
+			if tc.applied(f, task.ID) {
+				t.Error("Expected a rejected batch to change nothing")
+			}
+			if _, err := tc.run(f, ids[:repository.MaxBulkTasks], false); err != nil {
+				t.Errorf("Expected %d tasks to be allowed, got %v", repository.MaxBulkTasks, err)
+			}
+		})
+	}
+}
+
+func TestTaskRepository_BulkSavepoints(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTaskRepository(f.db)
+	ctx := context.Background()
+
+	var ids []string
+	for i := 0; i < 3; i++ {
+		ids = append(ids, f.createTask(t, f.alice, fmt.Sprintf("Task %d", i)).ID)
+	}
+	// The middle task fails in the database, after the first was updated
+	prepare := func(task *models.Task) (map[string]interface{}, error) {
+		if task.ID == ids[1] {
+			return map[string]interface{}{"status": "BOGUS"}, nil
+		}
+		return map[string]interface{}{"status": "REVIEW"}, nil
+	}
+	status := func(id string) string {
+		task, err := repo.GetByID(ctx, id)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		return task.Status
+	}
+
+	results, err := repo.BulkUpdate(ctx, ids, true, prepare)
+	if err != nil {
+		t.Fatalf("BulkUpdate() error = %v", err)
+	}
+	for i, result := range results {
+		if result.Err == nil || result.Task != nil {
+			t.Errorf("Result %d = %+v, want a failure", i, result)
+		}
+	}
+	for _, id := range ids {
+		if got := status(id); got != "TODO" {
+			t.Errorf("Expected the batch to be rolled back, got %s", got)
+		}
+	}
+
+	// Without atomic, the failed statement is undone by its savepoint and the
+	// transaction carries on
+	results, err = repo.BulkUpdate(ctx, ids, false, prepare)
+	if err != nil {
+		t.Fatalf("BulkUpdate() error = %v", err)
+	}
+	if results[1].Err == nil || results[0].Err != nil || results[2].Err != nil {
+		t.Fatalf("Expected only the middle task to fail, got %v, %v, %v", results[0].Err, results[1].Err, results[2].Err)
+	}
+	if results[0].Before.Status != "TODO" || results[0].Task.Status != "REVIEW" {
+		t.Errorf("Expected before and after, got %s and %s", results[0].Before.Status, results[0].Task.Status)
+	}
+	for i, want := range []string{"REVIEW", "TODO", "REVIEW"} {
+		if got := status(ids[i]); got != want {
+			t.Errorf("Task %d has status %s, want %s", i, got, want)
+		}
+	}
+
+	// A rejected task in an atomic delete keeps the others out of the trash
+	results, err = repo.BulkDelete(ctx, ids, true, func(task *models.Task) error {
+		if task.ID == ids[2] {
+			return fmt.Errorf("unauthorized")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("BulkDelete() error = %v", err)
+	}
+	if results[0].Err != repository.ErrBulkRolledBack {
+		t.Errorf("Expected the first delete to be rolled back, got %v", results[0].Err)
+	}
+	for _, id := range ids {
+		if _, err := repo.GetByID(ctx, id); err != nil {
+			t.Errorf("Expected %s to stay out of the trash, got %v", id, err)
+		}
+	}
+}
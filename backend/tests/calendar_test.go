@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/internal/calendar"
+	"taskboard/internal/models"
+)
+
+func calendarFixture() []*models.Task {
+	due := time.Date(2026, time.March, 1, 17, 0, 0, 0, time.FixedZone("CET", 3600))
+	created := time.Date(2026, time.February, 1, 9, 30, 0, 0, time.UTC)
+	description := "Agenda; budget, hiring\nand a very long line that goes on and on so that the description has to be folded ✓✓✓"
+
+	return []*models.Task{
+		{ID: "t1", Title: "Quarterly review", Description: &description, Status: "IN_PROGRESS", Priority: "URGENT",
+			DueDate: &due, CreatedAt: created, UpdatedAt: created},
+		{ID: "t2", Title: "No due date", Status: "TODO", Priority: "LOW", CreatedAt: created, UpdatedAt: created},
+	}
+}
+
+func TestCalendar_WriteEvents(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2026, time.February, 2, 0, 0, 0, 0, time.UTC)
+	if err := calendar.Write(&buf, "TaskBoard", calendar.ComponentEvent, calendarFixture(), now); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("Line longer than 75 octets: %q", line)
+		}
+	}
+
+	// Unfold before checking content
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"UID:task-t1@taskboard\r\n",
+		"DTSTART:20260301T160000Z\r\n",
+		"PRIORITY:1\r\n",
+		`DESCRIPTION:Agenda\; budget\, hiring\nand a very long line`,
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+	if !strings.Contains(unfolded, "folded ✓✓✓\r\n") {
+		t.Error("Expected folding to keep multi-byte characters intact")
+	}
+	if strings.Contains(unfolded, "DTEND") {
+		t.Error("Expected events to have no DTEND, which must be later than DTSTART")
+	}
+	if strings.Count(out, "BEGIN:VEVENT") != 1 {
+		t.Error("Expected tasks without a due date to be left out")
+	}
+}
+
+func TestCalendar_WriteTodos(t *testing.T) {
+	var buf bytes.Buffer
+	if err := calendar.Write(&buf, "TaskBoard", calendar.ComponentTodo, calendarFixture(), time.Now()); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{"BEGIN:VTODO\r\n", "DUE:20260301T160000Z\r\n", "STATUS:IN-PROCESS\r\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/internal/export"
+	"taskboard/internal/models"
+	"taskboard/internal/views"
+)
+
+func exportFixture() []*models.TaskExport {
+	description := "Line one, with a comma\nand a second line"
+	assignee, email := "Jane Smith", "jane@example.com"
+	due := time.Date(2026, time.March, 1, 17, 0, 0, 0, time.FixedZone("CET", 3600))
+	created := time.Date(2026, time.February, 1, 9, 30, 0, 0, time.UTC)
+
+	return []*models.TaskExport{
+		{
+			ID: "1", Title: "Write report", Description: &description, Status: "TODO", Priority: "HIGH",
+			CreatorName: "John Doe", CreatorEmail: "john@example.com",
+			AssigneeName: &assignee, AssigneeEmail: &email,
+			DueDate: &due, CreatedAt: created, UpdatedAt: created,
+		},
+		{
+			ID: "2", Title: "Unassigned", Status: "DONE", Priority: "LOW",
+			CreatorName: "John Doe", CreatorEmail: "john@example.com",
+			CreatedAt: created, UpdatedAt: created, CompletedAt: &created,
+		},
+	}
+}
+
+func TestExport_CSV(t *testing.T) {
+	var buf bytes.Buffer
+	writer := export.NewWriter(&buf, export.FormatCSV)
+	for _, task := range exportFixture() {
+		if err := writer.Write(task); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d records", len(records))
+	}
+	if records[0][0] != "id" || records[0][7] != "assignee_name" {
+		t.Errorf("Unexpected header %v", records[0])
+	}
+	if records[1][2] != "Line one, with a comma\nand a second line" {
+		t.Errorf("Description was not round-tripped: %q", records[1][2])
+	}
+	if records[1][9] != "2026-03-01T16:00:00Z" {
+		t.Errorf("Expected due date in UTC RFC 3339, got %q", records[1][9])
+	}
+	if records[2][7] != "" || records[2][9] != "" {
+		t.Errorf("Expected empty assignee and due date, got %q and %q", records[2][7], records[2][9])
+	}
+}
+
+func TestExport_CSVEscapesFormulas(t *testing.T) {
+	description := "@SUM(A1:A2)"
+	name, email := "+1 555 0100", "\tjane@example.com"
+	var buf bytes.Buffer
+	writer := export.NewWriter(&buf, export.FormatCSV)
+	err := writer.Write(&models.TaskExport{
+		ID: "1", Title: `=HYPERLINK("http://evil.test","Open")`, Description: &description, Status: "TODO", Priority: "LOW",
+		CreatorName: "-2+3", CreatorEmail: "john@example.com", AssigneeName: &name, AssigneeEmail: &email,
+		CreatedAt: time.Now(), UpdatedAt: time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	row := records[1]
+	for column, want := range map[int]string{
+		1: `'=HYPERLINK("http://evil.test","Open")`,
+		2: "'@SUM(A1:A2)",
+		5: "'-2+3",
+		6: "john@example.com",
+		7: "'+1 555 0100",
+		8: "'\tjane@example.com",
+	} {
+		if row[column] != want {
+			t.Errorf("Column %s = %q, want %q", records[0][column], row[column], want)
+		}
+	}
+}
+
+func TestExport_CSVHeaderOnly(t *testing.T) {
+	var buf bytes.Buffer
+	if err := export.NewWriter(&buf, export.FormatCSV).Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "id,title,") {
+		t.Errorf("Expected a header row for an empty export, got %q", buf.String())
+	}
+}
+
+func TestExport_JSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	writer := export.NewWriter(&buf, export.FormatJSONL)
+	for _, task := range exportFixture() {
+		if err := writer.Write(task); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines, got %d", len(lines))
+	}
+
+	var first, second map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("Failed to decode line: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("Failed to decode line: %v", err)
+	}
+
+	if first["assignee_email"] != "jane@example.com" {
+		t.Errorf("Expected assignee email, got %v", first["assignee_email"])
+	}
+	if first["due_date"] != "2026-03-01T16:00:00Z" {
+		t.Errorf("Expected due date in UTC RFC 3339, got %v", first["due_date"])
+	}
+	if second["due_date"] != nil || second["assignee_name"] != nil {
+		t.Errorf("Expected null due date and assignee, got %v and %v", second["due_date"], second["assignee_name"])
+	}
+}
+
+func TestExport_ParseFormat(t *testing.T) {
+	if format, err := export.ParseFormat("jsonl"); err != nil || format != export.FormatJSONL {
+		t.Errorf("ParseFormat(jsonl) = %q, %v", format, err)
+	}
+	if _, err := export.ParseFormat("xlsx"); err == nil {
+		t.Error("Expected an error for an unsupported format")
+	}
+}
+
+func TestExport_RequestTaskFilter(t *testing.T) {
+	req := &export.Request{Filter: views.Filter{Status: "DONE", IncludeArchived: true}}
+
+	filter := req.TaskFilter()
+	if filter["status"] != "DONE" {
+		t.Errorf("Expected status DONE, got %v", filter["status"])
+	}
+	if filter["include_archived"] != true {
+		t.Errorf("Expected include_archived to be restored as a bool, got %#v", filter["include_archived"])
+	}
+}
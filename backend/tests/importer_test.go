@@ -0,0 +1,261 @@
+package tests
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"taskboard/internal/importer"
+	"taskboard/internal/repository"
+)
+
+func resolveFixture(ctx context.Context, emails []string) (map[string]string, error) {
+	known := map[string]string{"jane@example.com": "user-jane"}
+	ids := make(map[string]string)
+	for _, email := range emails {
+		if id, ok := known[email]; ok {
+			ids[email] = id
+		}
+	}
+	return ids, nil
+}
+
+func TestImporter_ParseCSV(t *testing.T) {
+	csv := `Summary,Details,State,Priority,Owner,Due
+Write report,"Quarterly, with charts",In progress,high,Jane@Example.com,2026-03-01
+Plan offsite,,TODO,,,
+,missing title,DONE,LOW,,
+Fix bug,,BLOCKED,LOW,,not a date
+Review,,REVIEW,URGENT,nobody@example.com,2026-03-01T09:00:00Z
+`
+	mapping := importer.Mapping{
+		"title":          "Summary",
+		"description":    "Details",
+		"status":         "State",
+		"assignee_email": "Owner",
+		"due_date":       "Due",
+	}
+
+	tasks, report, err := importer.ParseCSV(context.Background(), strings.NewReader(csv), mapping, "creator", resolveFixture)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+
+	if report.Rows != 5 {
+		t.Errorf("Expected 5 rows, got %d", report.Rows)
+	}
+
+	type want struct {
+		row   int
+		field string
+	}
+	wantErrors := []want{{4, "title"}, {5, "status"}, {5, "due_date"}, {6, "assignee_email"}}
+	if len(report.Errors) != len(wantErrors) {
+		t.Fatalf("Expected %d errors, got %+v", len(wantErrors), report.Errors)
+	}
+	for i, w := range wantErrors {
+		if report.Errors[i].Row != w.row || report.Errors[i].Field != w.field {
+			t.Errorf("Error %d = %+v, want row %d field %s", i, report.Errors[i], w.row, w.field)
+		}
+	}
+
+	first := tasks[0]
+	if first.Status != "IN_PROGRESS" || first.Priority != "HIGH" {
+		t.Errorf("Expected IN_PROGRESS/HIGH, got %s/%s", first.Status, first.Priority)
+	}
+	if first.AssignedToID == nil || *first.AssignedToID != "user-jane" {
+		t.Errorf("Expected assignee to be resolved case-insensitively, got %v", first.AssignedToID)
+	}
+	if first.DueDate == nil || first.DueDate.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("Expected due date 2026-03-01, got %v", first.DueDate)
+	}
+	if first.CreatedByID != "creator" {
+		t.Errorf("Expected creator to be set, got %q", first.CreatedByID)
+	}
+
+	second := tasks[1]
+	if second.Priority != "MEDIUM" || second.AssignedToID != nil || second.Description != nil {
+		t.Errorf("Expected defaults for empty cells, got %+v", second)
+	}
+}
+
+func TestImporter_ColumnMapping(t *testing.T) {
+	tests := []struct {
+		name    string
+		csv     string
+		mapping importer.Mapping
+		wantErr bool
+	}{
+		{"Default column names", "title,priority\nA,LOW\n", nil, false},
+		{"Mapped column missing", "title\nA\n", importer.Mapping{"status": "State"}, true},
+		{"Unknown field", "title\nA\n", importer.Mapping{"owner": "title"}, true},
+		{"No title column", "name\nA\n", nil, true},
+		{"Empty file", "", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := importer.ParseCSV(context.Background(), strings.NewReader(tt.csv), tt.mapping, "creator", resolveFixture)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCSV() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestImporter_TitleLengthCountsCharacters(t *testing.T) {
+	// The title column holds 500 characters, however many bytes they take
+	valid, long := strings.Repeat("é", 500), strings.Repeat("é", 501)
+	csv := "title\n" + valid + "\n" + long + "\n"
+
+	tasks, report, err := importer.ParseCSV(context.Background(), strings.NewReader(csv), nil, "creator", resolveFixture)
+	if err != nil {
+		t.Fatalf("ParseCSV() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].Title != valid {
+		t.Errorf("Expected the 500 character title to be accepted, got %d tasks", len(tasks))
+	}
+	if len(report.Errors) != 1 || report.Errors[0].Row != 3 || report.Errors[0].Field != "title" {
+		t.Errorf("Expected the 501 character title to be rejected, got %+v", report.Errors)
+	}
+
+	board := `{"lists": [{"id": "l1", "name": "To Do"}], "cards": [{"id": "c1", "name": "` + long + `", "idList": "l1"}]}`
+	items, err := importer.ParseTrello(strings.NewReader(board))
+	if err != nil {
+		t.Fatalf("ParseTrello() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Title != valid {
+		t.Errorf("Expected the title to be cut to 500 characters, got %d", utf8.RuneCountInString(items[0].Title))
+	}
+}
+
+func TestImporter_ParseTrello(t *testing.T) {
+	board := `{
+		"lists": [{"id": "l1", "name": "To Do"}, {"id": "l2", "name": "Doing"}, {"id": "l3", "name": "Done"}],
+		"members": [{"id": "m1", "fullName": "Jane Smith", "username": "jane"}],
+		"cards": [
+			{"id": "c1", "name": "Design", "desc": "Mockups", "idList": "l2", "idMembers": ["m1"],
+			 "due": "2026-03-01T12:00:00.000Z", "labels": [{"name": "Urgent"}]},
+			{"id": "c2", "name": "Ship", "desc": "", "idList": "l1", "idMembers": [], "due": null,
+			 "dueComplete": true, "labels": []}
+		],
+		"actions": [{"type": "createCard", "idMemberCreator": "m1", "data": {"card": {"id": "c2"}}}]
+	}`
+
+	items, err := importer.ParseTrello(strings.NewReader(board))
+	if err != nil {
+		t.Fatalf("ParseTrello() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	design, ship := items[0], items[1]
+	if design.Status != "IN_PROGRESS" || design.Priority != "URGENT" {
+		t.Errorf("Expected IN_PROGRESS/URGENT, got %s/%s", design.Status, design.Priority)
+	}
+	if design.Assignee == nil || design.Assignee.Name != "Jane Smith" {
+		t.Errorf("Expected Jane Smith as assignee, got %+v", design.Assignee)
+	}
+	if design.DueDate == nil || design.Description == nil || *design.Description != "Mockups" {
+		t.Errorf("Expected due date and description, got %v and %v", design.DueDate, design.Description)
+	}
+	if ship.Status != "DONE" || ship.Priority != "MEDIUM" || ship.Description != nil {
+		t.Errorf("Expected completed card with defaults, got %+v", ship)
+	}
+	if ship.Creator == nil || ship.Creator.ExternalID != "m1" {
+		t.Errorf("Expected creator from createCard action, got %+v", ship.Creator)
+	}
+}
+
+func TestImporter_ParseJira(t *testing.T) {
+	export := `{"issues": [
+		{"id": "10001", "key": "WEB-1", "fields": {
+			"summary": "Login page",
+			"description": "Plain text",
+			"status": {"name": "Code Review", "statusCategory": {"key": "indeterminate"}},
+			"priority": {"name": "Highest"},
+			"assignee": {"accountId": "a1", "displayName": "Jane", "emailAddress": "jane@example.com"},
+			"reporter": {"accountId": "a2", "displayName": "John"},
+			"duedate": "2026-03-01"
+		}},
+		{"id": "10002", "key": "WEB-2", "fields": {
+			"summary": "Logout",
+			"description": {"type": "doc", "content": [
+				{"type": "paragraph", "content": [{"type": "text", "text": "First"}]},
+				{"type": "paragraph", "content": [{"type": "text", "text": "Second"}]}
+			]},
+			"status": {"name": "Closed", "statusCategory": {"key": "done"}},
+			"priority": {"name": "Lowest"},
+			"assignee": null,
+			"reporter": null
+		}}
+	]}`
+
+	items, err := importer.ParseJira(strings.NewReader(export))
+	if err != nil {
+		t.Fatalf("ParseJira() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 items, got %d", len(items))
+	}
+
+	login, logout := items[0], items[1]
+	if login.Status != "REVIEW" || login.Priority != "URGENT" {
+		t.Errorf("Expected REVIEW/URGENT, got %s/%s", login.Status, login.Priority)
+	}
+	if login.Assignee == nil || login.Assignee.Email != "jane@example.com" {
+		t.Errorf("Expected assignee email, got %+v", login.Assignee)
+	}
+	if login.Creator == nil || login.Creator.ExternalID != "a2" {
+		t.Errorf("Expected reporter as creator, got %+v", login.Creator)
+	}
+	if login.DueDate == nil || login.DueDate.Format("2006-01-02") != "2026-03-01" {
+		t.Errorf("Expected due date 2026-03-01, got %v", login.DueDate)
+	}
+	if logout.Status != "DONE" || logout.Priority != "LOW" || logout.Assignee != nil {
+		t.Errorf("Expected DONE/LOW unassigned, got %+v", logout)
+	}
+	if logout.Description == nil || *logout.Description != "First\nSecond" {
+		t.Errorf("Expected flattened ADF description, got %v", logout.Description)
+	}
+}
+
+func TestImporter_RunIsIdempotentPerItem(t *testing.T) {
+	f := newPostgresFixture(t)
+	ctx := context.Background()
+	userRepo := repository.NewUserRepository(f.db)
+	importRepo := repository.NewImportRepository(f.db)
+	imp := importer.NewImporter(userRepo, repository.NewTaskRepository(f.db), importRepo, repository.NewTransactor(f.db))
+
+	items := []*importer.Item{
+		{ExternalID: "card-1", Title: "Plan offsite", Status: "TODO", Priority: "MEDIUM"},
+		// Rejected by the database, after its assignee was created
+		{ExternalID: "card-2", Title: "Broken", Status: "BOGUS", Priority: "MEDIUM",
+			Assignee: &importer.Person{ExternalID: "member-2", Name: "Dana", Email: "dana@example.com"}},
+	}
+	if _, err := imp.Run(ctx, importer.SourceTrello, items, f.alice.ID, false); err == nil {
+		t.Fatal("Expected an invalid item to fail the import")
+	}
+
+	// Items before the failure stay imported; the failed one leaves nothing behind
+	if id, err := importRepo.Lookup(ctx, importer.SourceTrello, repository.ImportKindTask, "card-1"); err != nil || id == "" {
+		t.Errorf("Expected card-1 to stay imported, got %q, %v", id, err)
+	}
+	if id, err := importRepo.Lookup(ctx, importer.SourceTrello, repository.ImportKindUser, "member-2"); err != nil || id != "" {
+		t.Errorf("Expected member-2's mapping to be rolled back, got %q, %v", id, err)
+	}
+	if ids, err := userRepo.IDsByEmail(ctx, []string{"dana@example.com"}); err != nil || len(ids) != 0 {
+		t.Errorf("Expected the placeholder user to be rolled back, got %v, %v", ids, err)
+	}
+
+	items[1].Status = "DONE"
+	result, err := imp.Run(ctx, importer.SourceTrello, items, f.alice.ID, false)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Created != 1 || result.Skipped != 1 || result.UsersCreated != 1 {
+		t.Errorf("Expected the re-run to import only card-2, got %+v", result)
+	}
+}
@@ -0,0 +1,73 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+
+	"taskboard/graph/model"
+	"taskboard/internal/cache"
+	"taskboard/internal/markdown"
+)
+
+func TestMarkdown_Render(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    []string
+		notWant []string
+	}{
+		{"Emphasis", "Some **bold** text", []string{"<strong>bold</strong>"}, nil},
+		{"Task list", "- [x] done\n- [ ] todo", []string{`<input checked="" disabled="" type="checkbox"`, `<input disabled="" type="checkbox"`}, nil},
+		{"Code block", "```go\nfmt.Println(\"<hi>\")\n```", []string{`<code class="language-go">`, "&lt;hi&gt;"}, nil},
+		{"Link", "[docs](https://example.com)", []string{`href="https://example.com"`, `rel="nofollow noreferrer noopener"`, `target="_blank"`}, nil},
+		{"Raw HTML", "<script>alert(1)</script><img src=x onerror=alert(1)>", nil, []string{"<script", "onerror"}},
+		{"Script link", "[click](javascript:alert(1))", nil, []string{"javascript:"}},
+		{"Unsafe class", "```\" onclick=\"x\ncode\n```", nil, []string{"onclick"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			html := markdown.Render(tt.text)
+			for _, want := range tt.want {
+				if !strings.Contains(html, want) {
+					t.Errorf("Expected %q in %s", want, html)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(html, notWant) {
+					t.Errorf("Expected no %q in %s", notWant, html)
+				}
+			}
+		})
+	}
+}
+
+func TestResolver_DescriptionHTML(t *testing.T) {
+	f := newResolverFixture(t)
+	description := "Fix *this*"
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Rendered", Description: &description})
+
+	html, err := f.resolver.Task().DescriptionHTML(as(f.alice), task)
+	if err != nil || html == nil || *html != "<p>Fix <em>this</em></p>\n" {
+		t.Fatalf("Expected rendered description, got %v (%v)", html, err)
+	}
+
+	preview, err := f.resolver.Query().RenderMarkdown(as(f.alice), description)
+	if err != nil || preview != *html {
+		t.Errorf("Expected the preview to match the saved description, got %q (%v)", preview, err)
+	}
+
+	var cached string
+	if err := f.cache.Get(as(f.alice), cache.MarkdownKey(markdown.Hash(description)), &cached); err != nil || cached != *html {
+		t.Errorf("Expected the HTML to be cached by content hash, got %q (%v)", cached, err)
+	}
+
+	if _, err := f.resolver.Query().RenderMarkdown(as(f.alice), strings.Repeat("a", markdown.MaxLength+1)); err == nil {
+		t.Error("Expected text over the limit to be rejected")
+	}
+
+	untitled := f.createTask(t, f.alice, model.CreateTaskInput{Title: "No description"})
+	if html, _ := f.resolver.Task().DescriptionHTML(as(f.alice), untitled); html != nil {
+		t.Errorf("Expected no HTML without a description, got %q", *html)
+	}
+}
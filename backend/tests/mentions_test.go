@@ -0,0 +1,97 @@
+package tests
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/graph/model"
+	"taskboard/internal/mentions"
+	"taskboard/internal/models"
+)
+
+func TestMentions_Parse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"Handle", "@alice please review", "alice"},
+		{"Email", "cc @Bob@Example.com.", "bob@example.com"},
+		{"Several, once each", "@alice, @bob and @ALICE again", "alice,bob"},
+		{"Trailing punctuation", "Thanks (@carol.smith).", "carol.smith"},
+		{"Plain email address", "mail dave@example.com", ""},
+		{"Lone at sign", "meet @ noon", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := strings.Join(mentions.Parse(tt.text), ","); got != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMentions_Resolve(t *testing.T) {
+	users := []*models.User{
+		{ID: "1", Email: "alice@example.com"},
+		{ID: "2", Email: "dave@example.com"},
+		{ID: "3", Email: "dave@example.org"},
+	}
+
+	got := mentions.Resolve([]string{"dave", "alice", "dave@example.org", "alice@example.com", "nobody"}, users)
+	if strings.Join(got, ",") != "1,3" {
+		t.Errorf("Expected only unambiguous handles and known emails to resolve, got %v", got)
+	}
+}
+
+func TestResolver_Mentions(t *testing.T) {
+	f := newResolverFixture(t)
+	carol := createTestUser(t, f.store, "carol")
+
+	description := "@bob please review"
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Review", Description: &description})
+
+	mentioned, err := f.resolver.Query().MentionedTasks(as(f.bob))
+	if err != nil {
+		t.Fatalf("MentionedTasks() error = %v", err)
+	}
+	if got := taskIDs(mentioned); len(got) != 1 || got[0] != task.ID {
+		t.Errorf("Expected Bob to be mentioned in the task, got %v", got)
+	}
+
+	before, _ := f.resolver.Task().Mentions(as(f.alice), task)
+	if len(before) != 1 || before[0].UserID != f.bob.ID || before[0].MentionedByID != f.alice.ID {
+		t.Fatalf("Expected one mention of Bob by Alice, got %+v", before)
+	}
+
+	time.Sleep(time.Millisecond)
+	description = "@bob please review, cc @carol@example.com"
+	if _, err := f.resolver.Mutation().UpdateTask(as(f.alice), task.ID, model.UpdateTaskInput{Description: &description}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	after, _ := f.resolver.Task().Mentions(as(f.alice), task)
+	if len(after) != 2 || after[1].UserID != carol.ID {
+		t.Fatalf("Expected Carol to be added after Bob, got %+v", after)
+	}
+	if !after[0].CreatedAt.Equal(before[0].CreatedAt) {
+		t.Error("Expected Bob's existing mention to be kept rather than added again")
+	}
+
+	user, err := f.resolver.Mention().User(as(f.alice), after[1])
+	if err != nil || user.ID != carol.ID {
+		t.Errorf("Expected the mentioned user to be Carol, got %+v (%v)", user, err)
+	}
+
+	description = "cc @carol"
+	if _, err := f.resolver.Mutation().UpdateTask(as(f.alice), task.ID, model.UpdateTaskInput{Description: &description}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if mentioned, _ := f.resolver.Query().MentionedTasks(as(f.bob)); len(mentioned) != 0 {
+		t.Errorf("Expected Bob's mention to be removed, got %v", taskIDs(mentioned))
+	}
+	if mentioned, _ := f.resolver.Query().MentionedTasks(as(carol)); len(mentioned) != 1 {
+		t.Errorf("Expected Carol to stay mentioned, got %v", taskIDs(mentioned))
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/graph/model"
+	"taskboard/internal/auth"
+	"taskboard/internal/models"
+	"taskboard/internal/passwordreset"
+	"taskboard/internal/repository"
+)
+
+func TestResolver_PasswordReset(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	payload, err := f.resolver.Mutation().Register(ctx, model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	// Unknown addresses get the same answer, and no mail
+	if ok, err := f.resolver.Mutation().RequestPasswordReset(ctx, "nobody@example.com"); err != nil || !ok {
+		t.Fatalf("RequestPasswordReset() for an unknown address = %v, %v", ok, err)
+	}
+	f.passwordResets.Wait()
+	if links := sentLinks(t, f.mailDir, "reset-password"); len(links) != 0 {
+		t.Fatalf("Expected no reset mail for an unknown address, got %d", len(links))
+	}
+
+	if ok, err := f.resolver.Mutation().RequestPasswordReset(ctx, "carol@example.com"); err != nil || !ok {
+		t.Fatalf("RequestPasswordReset() = %v, %v", ok, err)
+	}
+	f.passwordResets.Wait()
+	links := sentLinks(t, f.mailDir, "reset-password")
+	if len(links) != 1 || links[0].To != "carol@example.com" {
+		t.Fatalf("Expected one reset mail to carol, got %+v", links)
+	}
+	token := links[0].Token
+
+	// A password that fails validation leaves the token usable
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, token, "short"); !errors.Is(err, auth.ErrPasswordTooShort) {
+		t.Fatalf("Expected a weak password to be rejected, got %v", err)
+	}
+
+	if ok, err := f.resolver.Mutation().ResetPassword(ctx, token, "NewSecurePass456!"); err != nil || !ok {
+		t.Fatalf("ResetPassword() = %v, %v", ok, err)
+	}
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, token, "OtherSecurePass789!"); !errors.Is(err, repository.ErrPasswordResetInvalid) {
+		t.Errorf("Expected a reset link to work once, got %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().Login(ctx, model.LoginInput{Email: "carol@example.com", Password: "SecurePass123!"}); err == nil {
+		t.Error("Expected the old password to stop working")
+	}
+	if _, err := f.resolver.Mutation().Login(ctx, model.LoginInput{Email: "carol@example.com", Password: "NewSecurePass456!"}); err != nil {
+		t.Errorf("Expected the new password to work, got %v", err)
+	}
+
+	// Sessions from before the reset are over
+	if _, err := f.resolver.Mutation().RefreshToken(ctx, payload.RefreshToken); err == nil {
+		t.Error("Expected refresh tokens issued before the reset to be revoked")
+	}
+	claims := authenticated(t, payload).Value(auth.UserContextKey).(*auth.Claims)
+	session, err := f.store.Sessions().GetByID(ctx, claims.SessionID)
+	if err != nil {
+		t.Fatalf("GetByID() error = %v", err)
+	}
+	if session.RevokedAt == nil {
+		t.Error("Expected sessions from before the reset to be revoked")
+	}
+}
+
+func TestResolver_PasswordResetSpendsOtherLinks(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := f.resolver.Mutation().RequestPasswordReset(ctx, f.alice.Email); err != nil {
+			t.Fatalf("RequestPasswordReset() error = %v", err)
+		}
+	}
+	f.passwordResets.Wait()
+	links := sentLinks(t, f.mailDir, "reset-password")
+	if len(links) != 2 {
+		t.Fatalf("Expected two reset mails, got %d", len(links))
+	}
+
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, links[0].Token, "NewSecurePass456!"); err != nil {
+		t.Fatalf("ResetPassword() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, links[1].Token, "OtherSecurePass789!"); !errors.Is(err, repository.ErrPasswordResetInvalid) {
+		t.Errorf("Expected earlier links to stop working after a reset, got %v", err)
+	}
+}
+
+func TestResolver_PasswordResetThrottled(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	// Addresses without an account are limited the same way
+	for _, email := range []string{f.alice.Email, "nobody@example.com"} {
+		for i := 0; i < passwordreset.MaxRequestsPerEmail; i++ {
+			if _, err := f.resolver.Mutation().RequestPasswordReset(ctx, email); err != nil {
+				t.Fatalf("RequestPasswordReset() error = %v", err)
+			}
+		}
+		if _, err := f.resolver.Mutation().RequestPasswordReset(ctx, strings.ToUpper(email)); !errors.Is(err, passwordreset.ErrTooManyRequests) {
+			t.Errorf("Expected too many requests for %s to be rejected, got %v", email, err)
+		}
+	}
+	f.passwordResets.Wait()
+	if links := sentLinks(t, f.mailDir, "reset-password"); len(links) != passwordreset.MaxRequestsPerEmail {
+		t.Errorf("Expected %d reset mails, got %d", passwordreset.MaxRequestsPerEmail, len(links))
+	}
+
+	// One client asking for many addresses is limited too
+	client := auth.WithClient(ctx, auth.Client{IP: "203.0.113.7"})
+	for i := 0; i < passwordreset.MaxRequestsPerIP; i++ {
+		if _, err := f.resolver.Mutation().RequestPasswordReset(client, fmt.Sprintf("user%d@example.com", i)); err != nil {
+			t.Fatalf("RequestPasswordReset() error = %v", err)
+		}
+	}
+	if _, err := f.resolver.Mutation().RequestPasswordReset(client, "another@example.com"); !errors.Is(err, passwordreset.ErrTooManyRequests) {
+		t.Errorf("Expected too many requests from one client to be rejected, got %v", err)
+	}
+	other := auth.WithClient(ctx, auth.Client{IP: "198.51.100.1"})
+	if _, err := f.resolver.Mutation().RequestPasswordReset(other, "another@example.com"); err != nil {
+		t.Errorf("Expected other clients to be unaffected, got %v", err)
+	}
+}
+
+func TestResolver_PasswordResetExpires(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	token, err := auth.NewOpaqueToken()
+	if err != nil {
+		t.Fatalf("NewOpaqueToken() error = %v", err)
+	}
+	_, err = f.store.PasswordResets().Create(ctx, &models.PasswordReset{
+		UserID:    f.alice.ID,
+		TokenHash: auth.HashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, token, "NewSecurePass456!"); !errors.Is(err, repository.ErrPasswordResetInvalid) {
+		t.Errorf("Expected an expired link to be rejected, got %v", err)
+	}
+	if _, err := f.resolver.Mutation().ResetPassword(ctx, "not-a-token", "NewSecurePass456!"); !errors.Is(err, repository.ErrPasswordResetInvalid) {
+		t.Errorf("Expected an unknown token to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package tests
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"taskboard/graph"
+	"taskboard/internal/auth"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+	"taskboard/internal/tasks"
+)
+
+// testDB returns a pool on a new schema of the database at TEST_DATABASE_URL,
+// with every migration applied. The schema is dropped when the test ends.
+// Tests needing Postgres are skipped when TEST_DATABASE_URL is not set.
+func testDB(t *testing.T) *pgxpool.Pool {
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+	ctx := context.Background()
+
+	suffix := make([]byte, 6)
+	if _, err := rand.Read(suffix); err != nil {
+		t.Fatalf("rand.Read() error = %v", err)
+	}
+	schema := "test_" + hex.EncodeToString(suffix)
+
+	admin, err := pgx.Connect(ctx, url)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := admin.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("CREATE SCHEMA error = %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(context.Background(), "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close(context.Background())
+	})
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	// Extensions such as pg_trgm stay in public. Days are UTC days.
+	config.ConnConfig.RuntimeParams["search_path"] = schema + ",public"
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("NewWithConfig() error = %v", err)
+	}
+	t.Cleanup(pool.Close)
+
+	migrations, err := filepath.Glob(filepath.Join("..", "internal", "database", "migrations", "*.sql"))
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("Expected migrations, got %v, %v", migrations, err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		sql, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		if _, err := pool.Exec(ctx, string(sql)); err != nil {
+			t.Fatalf("%s: %v", filepath.Base(migration), err)
+		}
+	}
+
+	return pool
+}
+
+// postgresFixture wires a resolver to the Postgres repositories of a test
+// database with two users. It has no cache.
+type postgresFixture struct {
+	resolver *graph.Resolver
+	db       *pgxpool.Pool
+	alice    *models.User
+	bob      *models.User
+}
+
+func newPostgresFixture(t *testing.T) *postgresFixture {
+	db := testDB(t)
+	userRepo, taskRepo := repository.NewUserRepository(db), repository.NewTaskRepository(db)
+	mentionRepo, transactor := repository.NewMentionRepository(db), repository.NewTransactor(db)
+	f := &postgresFixture{
+		resolver: graph.NewResolver(graph.Dependencies{
+			UserRepo:      userRepo,
+			TaskRepo:      taskRepo,
+			TimeEntryRepo: repository.NewTimeEntryRepository(db),
+			SprintRepo:    repository.NewSprintRepository(db),
+			AnalyticsRepo: repository.NewAnalyticsRepository(db),
+			ViewRepo:      repository.NewViewRepository(db),
+			WatcherRepo:   repository.NewWatcherRepository(db),
+			MentionRepo:   mentionRepo,
+			TokenRepo:     repository.NewRefreshTokenRepository(db),
+			SessionRepo:   repository.NewSessionRepository(db),
+			Transactor:    transactor,
+			Tasks:         tasks.NewService(userRepo, taskRepo, mentionRepo, transactor),
+			JWTManager:    auth.NewJWTManager("test-secret-key", "test-refresh-key"),
+		}),
+		db: db,
+	}
+	f.alice = f.createUser(t, "alice")
+	f.bob = f.createUser(t, "bob")
+	return f
+}
+
+func (f *postgresFixture) createUser(t *testing.T, name string) *models.User {
+	user, err := repository.NewUserRepository(f.db).Create(context.Background(), &models.User{Email: name + "@example.com", Name: name})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	return user
+}
+
+func (f *postgresFixture) createTask(t *testing.T, user *models.User, title string) *models.Task {
+	task, err := repository.NewTaskRepository(f.db).Create(context.Background(), &models.Task{Title: title, Status: "TODO", Priority: "MEDIUM", CreatedByID: user.ID})
+	if err != nil {
+		t.Fatalf("Failed to create task: %v", err)
+	}
+	return task
+}
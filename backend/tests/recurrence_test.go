@@ -0,0 +1,168 @@
+package tests
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"taskboard/internal/jobs"
+	"taskboard/internal/models"
+	"taskboard/internal/recurrence"
+	"taskboard/internal/repository/memory"
+)
+
+func TestRecurrence_Parse(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{"Daily", "FREQ=DAILY", "FREQ=DAILY", false},
+		{"Prefix and lowercase", "RRULE:freq=weekly;byday=fr,mo", "FREQ=WEEKLY;BYDAY=MO,FR", false},
+		{"Monthly with count", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1;COUNT=6", "FREQ=MONTHLY;INTERVAL=2;BYMONTHDAY=-1;COUNT=6", false},
+		{"Until date", "FREQ=DAILY;UNTIL=20260131", "FREQ=DAILY;UNTIL=20260131T235959Z", false},
+		{"Missing FREQ", "INTERVAL=2", "", true},
+		{"Unsupported FREQ", "FREQ=YEARLY", "", true},
+		{"Count and until", "FREQ=DAILY;COUNT=2;UNTIL=20260131", "", true},
+		{"BYDAY on monthly", "FREQ=MONTHLY;BYDAY=MO", "", true},
+		{"Zero interval", "FREQ=DAILY;INTERVAL=0", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := recurrence.Normalize(tt.rule)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Normalize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	// Wednesday
+	start := time.Date(2026, time.January, 7, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		rule       string
+		prev       time.Time
+		occurrence int
+		want       time.Time
+		wantOK     bool
+	}{
+		{"Every other day", "FREQ=DAILY;INTERVAL=2", start, 1, start.AddDate(0, 0, 2), true},
+		{"Weekly same weekday", "FREQ=WEEKLY", start, 1, start.AddDate(0, 0, 7), true},
+		{"Weekly later day same week", "FREQ=WEEKLY;BYDAY=MO,FR", start, 1, start.AddDate(0, 0, 2), true},
+		{"Biweekly wraps to first day", "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,WE", start, 1, start.AddDate(0, 0, 12), true},
+		{"Monthly on day", "FREQ=MONTHLY;BYMONTHDAY=20", start, 1, time.Date(2026, time.January, 20, 9, 0, 0, 0, time.UTC), true},
+		{"Monthly skips short months", "FREQ=MONTHLY;BYMONTHDAY=31", time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC), 1,
+			time.Date(2026, time.March, 31, 9, 0, 0, 0, time.UTC), true},
+		{"Monthly last day", "FREQ=MONTHLY;BYMONTHDAY=-1", time.Date(2026, time.January, 31, 9, 0, 0, 0, time.UTC), 1,
+			time.Date(2026, time.February, 28, 9, 0, 0, 0, time.UTC), true},
+		{"Count reached", "FREQ=DAILY;COUNT=3", start, 3, time.Time{}, false},
+		{"Until passed", "FREQ=DAILY;UNTIL=20260107", start, 1, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, ok := rule.Next(tt.prev, tt.occurrence)
+			if ok != tt.wantOK {
+				t.Fatalf("Next() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("Next() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecurrence_NextAfter(t *testing.T) {
+	start := time.Date(2026, time.January, 7, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		rule           string
+		after          time.Time
+		want           time.Time
+		wantOccurrence int
+		wantOK         bool
+	}{
+		{"Not behind", "FREQ=DAILY", start.Add(-time.Hour), start.AddDate(0, 0, 1), 2, true},
+		{"Skips missed days", "FREQ=DAILY", start.AddDate(0, 0, 30), start.AddDate(0, 0, 31), 32, true},
+		{"Occurrence at after is missed", "FREQ=WEEKLY", start.AddDate(0, 0, 14), start.AddDate(0, 0, 21), 4, true},
+		{"Series ends first", "FREQ=DAILY;COUNT=5", start.AddDate(0, 0, 30), time.Time{}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := recurrence.Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, occurrence, ok := rule.NextAfter(start, 1, tt.after)
+			if ok != tt.wantOK {
+				t.Fatalf("NextAfter() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !got.Equal(tt.want) || occurrence != tt.wantOccurrence {
+				t.Errorf("NextAfter() = %v, %d, want %v, %d", got, occurrence, tt.want, tt.wantOccurrence)
+			}
+		})
+	}
+}
+
+func TestJobs_SpawnRecurringTasksSkipsMissedOccurrences(t *testing.T) {
+	store := memory.New()
+	ctx := context.Background()
+	user, err := store.Users().Create(ctx, &models.User{Email: "alice@example.com", Name: "alice"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	// A daily series 30 days behind gets one new occurrence, in the future
+	due := time.Now().AddDate(0, 0, -30)
+	rule := "FREQ=DAILY"
+	task, err := store.Tasks().Create(ctx, &models.Task{Title: "Standup notes", Status: "TODO", Priority: "LOW", CreatedByID: user.ID, DueDate: &due, RecurrenceRule: &rule})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	spawn := jobs.SpawnRecurringTasks(store.Tasks(), nil)
+	for i := 0; i < 3; i++ {
+		if err := spawn(ctx); err != nil {
+			t.Fatalf("SpawnRecurringTasks() error = %v", err)
+		}
+	}
+
+	series, err := store.Tasks().List(ctx, map[string]interface{}{"created_by_id": user.ID})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(series) != 2 {
+		t.Fatalf("Expected one new occurrence, got %d tasks", len(series))
+	}
+	next := series[0]
+	if next.ID == task.ID {
+		next = series[1]
+	}
+	if !next.DueDate.After(time.Now()) || next.DueDate.After(time.Now().AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next occurrence within a day, got %v", next.DueDate)
+	}
+	if next.Occurrence != 32 {
+		t.Errorf("Expected occurrence 32, got %d", next.Occurrence)
+	}
+
+	// Completing the overdue task does not continue the series a second time
+	if spawned, err := store.Tasks().SpawnNextOccurrence(ctx, task); err != nil || spawned != nil {
+		t.Errorf("SpawnNextOccurrence() = %v, %v, want nothing", spawned, err)
+	}
+}
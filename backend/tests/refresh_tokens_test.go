@@ -0,0 +1,112 @@
+package tests
+
+import (
+	"context"
+	"testing"
+
+	"taskboard/graph/model"
+	"taskboard/internal/auth"
+)
+
+func TestResolver_RefreshTokenRotation(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	registered, err := f.resolver.Mutation().Register(ctx, model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	refreshed, err := f.resolver.Mutation().RefreshToken(ctx, registered.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if refreshed.RefreshToken == registered.RefreshToken {
+		t.Fatal("Expected a new refresh token")
+	}
+
+	// The first token was exchanged, so presenting it again means it leaked
+	if _, err := f.resolver.Mutation().RefreshToken(ctx, registered.RefreshToken); err == nil {
+		t.Fatal("Expected a used refresh token to be rejected")
+	}
+	if _, err := f.resolver.Mutation().RefreshToken(ctx, refreshed.RefreshToken); err == nil {
+		t.Error("Expected reuse to revoke the rest of the family")
+	}
+
+	// Other logins are unaffected
+	login, err := f.resolver.Mutation().Login(ctx, model.LoginInput{Email: "carol@example.com", Password: "SecurePass123!"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().RefreshToken(ctx, login.RefreshToken); err != nil {
+		t.Errorf("Expected a separate login to keep working, got %v", err)
+	}
+}
+
+func TestResolver_RefreshTokenRejectsUnknownTokens(t *testing.T) {
+	f := newResolverFixture(t)
+
+	// Correctly signed, but never issued through the resolver
+	token, err := auth.NewJWTManager("test-secret-key", "test-refresh-key").GenerateRefreshToken(f.alice.ID, f.alice.Email)
+	if err != nil {
+		t.Fatalf("GenerateRefreshToken() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().RefreshToken(context.Background(), token); err == nil {
+		t.Error("Expected a refresh token that was not stored to be rejected")
+	}
+}
+
+func TestResolver_Logout(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+
+	if _, err := f.resolver.Mutation().Register(ctx, model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	login := func() *model.AuthPayload {
+		payload, err := f.resolver.Mutation().Login(ctx, model.LoginInput{Email: "carol@example.com", Password: "SecurePass123!"})
+		if err != nil {
+			t.Fatalf("Login() error = %v", err)
+		}
+		return payload
+	}
+
+	laptop, phone := login(), login()
+	rotated, err := f.resolver.Mutation().RefreshToken(ctx, laptop.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	// Logging out with a token from earlier in the family ends the login
+	if ok, err := f.resolver.Mutation().Logout(ctx, laptop.RefreshToken); err != nil || !ok {
+		t.Fatalf("Logout() = %v, %v", ok, err)
+	}
+	if _, err := f.resolver.Mutation().RefreshToken(ctx, rotated.RefreshToken); err == nil {
+		t.Error("Expected logout to revoke the rotated token")
+	}
+	if _, err := f.resolver.Mutation().Logout(ctx, "not-a-token"); err == nil {
+		t.Error("Expected logout with an unknown token to fail")
+	}
+
+	phone, err = f.resolver.Mutation().RefreshToken(ctx, phone.RefreshToken)
+	if err != nil {
+		t.Fatalf("Expected the other login to survive, got %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().LogoutAllDevices(ctx); err == nil {
+		t.Error("Expected logoutAllDevices to require authentication")
+	}
+	tablet := login()
+	carol, err := f.store.Users().GetByEmail(ctx, "carol@example.com")
+	if err != nil {
+		t.Fatalf("GetByEmail() error = %v", err)
+	}
+	if ok, err := f.resolver.Mutation().LogoutAllDevices(as(carol)); err != nil || !ok {
+		t.Fatalf("LogoutAllDevices() = %v, %v", ok, err)
+	}
+	for _, token := range []string{phone.RefreshToken, tablet.RefreshToken} {
+		if _, err := f.resolver.Mutation().RefreshToken(ctx, token); err == nil {
+			t.Error("Expected logoutAllDevices to revoke every login")
+		}
+	}
+}
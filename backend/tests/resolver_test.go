@@ -0,0 +1,468 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/graph"
+	"taskboard/graph/model"
+	"taskboard/internal/auth"
+	"taskboard/internal/cache"
+	"taskboard/internal/mail"
+	"taskboard/internal/models"
+	"taskboard/internal/passwordreset"
+	"taskboard/internal/repository"
+	"taskboard/internal/repository/memory"
+	"taskboard/internal/tasks"
+	"taskboard/internal/verification"
+	"taskboard/internal/views"
+)
+
+// resolverFixture wires a resolver to in-memory stores with two users
+type resolverFixture struct {
+	resolver       *graph.Resolver
+	store          *memory.Store
+	cache          *cache.MemoryCache
+	passwordResets *passwordreset.Service
+	mailDir        string
+	alice          *models.User
+	bob            *models.User
+}
+
+func newResolverFixture(t *testing.T) *resolverFixture {
+	return newResolverFixtureWith(t, false)
+}
+
+// newResolverFixtureWith wires a fixture whose users must verify their email
+// address before changing tasks when requireVerified is set. Mail is written
+// to mailDir.
+func newResolverFixtureWith(t *testing.T, requireVerified bool) *resolverFixture {
+	store := memory.New()
+	memCache := cache.NewMemoryCache()
+	mailDir := t.TempDir()
+	mailer, err := mail.NewFileSender(mailDir)
+	if err != nil {
+		t.Fatalf("NewFileSender() error = %v", err)
+	}
+	verifications := verification.NewService("test-verification-key", time.Hour, mailer, "http://app.test", requireVerified)
+	passwordResets := passwordreset.NewService(store.PasswordResets(), mailer, memCache, "http://app.test", 30*time.Minute)
+	return &resolverFixture{
+		resolver: graph.NewResolver(graph.Dependencies{
+			UserRepo:       store.Users(),
+			TaskRepo:       store.Tasks(),
+			ViewRepo:       store.Views(),
+			WatcherRepo:    store.Watchers(),
+			MentionRepo:    store.Mentions(),
+			TokenRepo:      store.RefreshTokens(),
+			SessionRepo:    store.Sessions(),
+			Transactor:     store,
+			Tasks:          tasks.NewService(store.Users(), store.Tasks(), store.Mentions(), store),
+			Verifications:  verifications,
+			PasswordResets: passwordResets,
+			Cache:          memCache,
+			JWTManager:     auth.NewJWTManager("test-secret-key", "test-refresh-key"),
+		}),
+		store:          store,
+		cache:          memCache,
+		passwordResets: passwordResets,
+		mailDir:        mailDir,
+		alice:          createTestUser(t, store, "alice"),
+		bob:            createTestUser(t, store, "bob"),
+	}
+}
+
+func createTestUser(t *testing.T, store *memory.Store, name string) *models.User {
+	user, err := store.Users().Create(context.Background(), &models.User{Email: name + "@example.com", Name: name})
+	if err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+	return user
+}
+
+// as returns a context authenticated as user
+func as(user *models.User) context.Context {
+	return context.WithValue(context.Background(), auth.UserContextKey, &auth.Claims{UserID: user.ID, Email: user.Email})
+}
+
+func (f *resolverFixture) createTask(t *testing.T, user *models.User, input model.CreateTaskInput) *model.Task {
+	task, err := f.resolver.Mutation().CreateTask(as(user), input)
+	if err != nil {
+		t.Fatalf("CreateTask() error = %v", err)
+	}
+	return task
+}
+
+func TestResolver_CreateAndUpdateTask(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := as(f.alice)
+
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Write report", AssignedToID: &f.bob.ID})
+	if task.Status != model.TaskStatusTodo || task.Priority != model.PriorityMedium {
+		t.Errorf("Expected default status and priority, got %s and %s", task.Status, task.Priority)
+	}
+	if task.CreatedBy.ID != f.alice.ID || task.AssignedTo == nil || task.AssignedTo.ID != f.bob.ID {
+		t.Errorf("Expected creator and assignee to be resolved, got %+v", task)
+	}
+
+	done := model.TaskStatusDone
+	updated, err := f.resolver.Mutation().UpdateTask(ctx, task.ID, model.UpdateTaskInput{Status: &done})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if updated.Status != model.TaskStatusDone || updated.CompletedAt == nil {
+		t.Errorf("Expected completed task, got status %s and completedAt %v", updated.Status, updated.CompletedAt)
+	}
+
+	todo := model.TaskStatusTodo
+	reopened, err := f.resolver.Mutation().UpdateTask(ctx, task.ID, model.UpdateTaskInput{Status: &todo})
+	if err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if reopened.CompletedAt != nil {
+		t.Errorf("Expected completedAt to be cleared on reopen, got %v", reopened.CompletedAt)
+	}
+}
+
+func TestResolver_Errors(t *testing.T) {
+	f := newResolverFixture(t)
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Alice's task"})
+
+	if _, err := f.resolver.Mutation().CreateTask(context.Background(), model.CreateTaskInput{Title: "Anonymous"}); err == nil || err.Error() != "unauthorized" {
+		t.Errorf("Expected unauthorized error, got %v", err)
+	}
+
+	title := "Taken over"
+	_, err := f.resolver.Mutation().UpdateTask(as(f.bob), task.ID, model.UpdateTaskInput{Title: &title})
+	if err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+		t.Errorf("Expected ownership error, got %v", err)
+	}
+
+	if _, err := f.resolver.Query().Task(as(f.alice), "00000000-0000-0000-0000-000000000000"); err == nil || err.Error() != "task not found" {
+		t.Errorf("Expected task not found, got %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().CreateTask(as(f.alice), model.CreateTaskInput{Title: "Recurring", Recurrence: strPtr("FREQ=DAILY")}); err == nil {
+		t.Error("Expected a recurring task without a due date to be rejected")
+	}
+}
+
+func TestResolver_TasksFilteringAndOrdering(t *testing.T) {
+	f := newResolverFixture(t)
+	high := model.PriorityHigh
+
+	first := f.createTask(t, f.alice, model.CreateTaskInput{Title: "First", Priority: &high})
+	second := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Second"})
+	third := f.createTask(t, f.bob, model.CreateTaskInput{Title: "Third", Priority: &high, AssignedToID: &f.alice.ID})
+
+	tests := []struct {
+		name   string
+		filter *model.TaskFilterInput
+		want   []string
+	}{
+		{"No filter", nil, []string{third.ID, second.ID, first.ID}},
+		{"Priority", &model.TaskFilterInput{Priority: &high}, []string{third.ID, first.ID}},
+		{"Creator", &model.TaskFilterInput{CreatedByID: &f.alice.ID}, []string{second.ID, first.ID}},
+		{"Assignee", &model.TaskFilterInput{AssignedToID: &f.alice.ID}, []string{third.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := f.resolver.Query().Tasks(as(f.alice), tt.filter)
+			if err != nil {
+				t.Fatalf("Tasks() error = %v", err)
+			}
+			if got := taskIDs(tasks); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected tasks %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	mine, err := f.resolver.Query().MyTasks(as(f.bob))
+	if err != nil {
+		t.Fatalf("MyTasks() error = %v", err)
+	}
+	if got := taskIDs(mine); len(got) != 1 || got[0] != third.ID {
+		t.Errorf("Expected only Bob's task, got %v", got)
+	}
+}
+
+func TestResolver_TasksRichFilters(t *testing.T) {
+	f := newResolverFixture(t)
+	start := time.Now().Add(-time.Second)
+	yesterday, nextWeek := time.Now().Add(-24*time.Hour), time.Now().Add(7*24*time.Hour)
+	high, done, inProgress := model.PriorityHigh, model.TaskStatusDone, model.TaskStatusInProgress
+	yes, no := true, false
+
+	overdue := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Fix login bug", DueDate: &yesterday, AssignedToID: &f.bob.ID})
+	finished := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Write 100%_report", DueDate: &yesterday, Status: &done, AssignedToID: &f.alice.ID})
+	upcoming := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Ship release", DueDate: &nextWeek, Status: &inProgress, Priority: &high})
+	undated := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Plan sprint"})
+	now, later := time.Now(), time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		filter *model.TaskFilterInput
+		want   []string
+	}{
+		{"Status in", &model.TaskFilterInput{StatusIn: []model.TaskStatus{model.TaskStatusTodo, inProgress}}, []string{undated.ID, upcoming.ID, overdue.ID}},
+		{"Priority in", &model.TaskFilterInput{PriorityIn: []model.Priority{high, model.PriorityLow}}, []string{upcoming.ID}},
+		{"Due before", &model.TaskFilterInput{DueBefore: &now}, []string{finished.ID, overdue.ID}},
+		{"Due after", &model.TaskFilterInput{DueAfter: &now}, []string{upcoming.ID}},
+		{"Overdue", &model.TaskFilterInput{Overdue: &yes}, []string{overdue.ID}},
+		{"Not overdue", &model.TaskFilterInput{Overdue: &no}, []string{undated.ID, upcoming.ID, finished.ID}},
+		{"Unassigned", &model.TaskFilterInput{Unassigned: &yes}, []string{undated.ID, upcoming.ID}},
+		{"Assigned", &model.TaskFilterInput{Unassigned: &no}, []string{finished.ID, overdue.ID}},
+		{"Created after", &model.TaskFilterInput{CreatedAfter: &later}, []string{}},
+		{"Updated since", &model.TaskFilterInput{UpdatedSince: &start}, []string{undated.ID, upcoming.ID, finished.ID, overdue.ID}},
+		{"Title ignores case", &model.TaskFilterInput{TitleContains: strPtr("LOGIN")}, []string{overdue.ID}},
+		{"Title wildcards are literal", &model.TaskFilterInput{TitleContains: strPtr("%_")}, []string{finished.ID}},
+		{"Or", &model.TaskFilterInput{Or: []*model.TaskFilterInput{
+			{Status: &done},
+			{Priority: &high, Unassigned: &yes},
+		}}, []string{upcoming.ID, finished.ID}},
+		{"Not matches missing due dates", &model.TaskFilterInput{Not: &model.TaskFilterInput{DueBefore: &now}}, []string{undated.ID, upcoming.ID}},
+		{"Nested", &model.TaskFilterInput{And: []*model.TaskFilterInput{
+			{StatusIn: []model.TaskStatus{model.TaskStatusTodo}},
+			{Not: &model.TaskFilterInput{Unassigned: &yes}},
+		}}, []string{overdue.ID}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := f.resolver.Query().Tasks(as(f.alice), tt.filter)
+			if err != nil {
+				t.Fatalf("Tasks() error = %v", err)
+			}
+			if got := taskIDs(tasks); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Expected tasks %v, got %v", tt.want, got)
+			}
+		})
+	}
+
+	if _, err := f.resolver.Query().Tasks(as(f.alice), &model.TaskFilterInput{StatusIn: []model.TaskStatus{}}); err == nil {
+		t.Error("Expected an empty statusIn to be rejected")
+	}
+	deep := &model.TaskFilterInput{}
+	for i := 0; i <= views.MaxDepth; i++ {
+		deep = &model.TaskFilterInput{Not: deep}
+	}
+	if _, err := f.resolver.Query().Tasks(as(f.alice), deep); err == nil {
+		t.Error("Expected a deeply nested filter to be rejected")
+	}
+}
+
+func TestResolver_TrashAndArchive(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := as(f.alice)
+	kept := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Kept"})
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Binned"})
+
+	if _, err := f.resolver.Mutation().DeleteTask(ctx, task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := f.resolver.Query().Task(ctx, task.ID); err == nil {
+		t.Error("Expected deleted task to be hidden")
+	}
+	trash, err := f.resolver.Query().Trash(ctx)
+	if err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+	if got := taskIDs(trash); len(got) != 1 || got[0] != task.ID {
+		t.Errorf("Expected deleted task in trash, got %v", got)
+	}
+
+	restored, err := f.resolver.Mutation().RestoreTask(ctx, task.ID)
+	if err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("Expected deletedAt to be cleared, got %v", restored.DeletedAt)
+	}
+
+	if _, err := f.resolver.Mutation().ArchiveTask(ctx, task.ID); err != nil {
+		t.Fatalf("ArchiveTask() error = %v", err)
+	}
+	tasks, _ := f.resolver.Query().Tasks(ctx, nil)
+	if got := taskIDs(tasks); len(got) != 1 || got[0] != kept.ID {
+		t.Errorf("Expected archived task to be hidden, got %v", got)
+	}
+	includeArchived := true
+	tasks, _ = f.resolver.Query().Tasks(ctx, &model.TaskFilterInput{IncludeArchived: &includeArchived})
+	if len(tasks) != 2 {
+		t.Errorf("Expected archived task with includeArchived, got %v", taskIDs(tasks))
+	}
+}
+
+func TestResolver_Watchers(t *testing.T) {
+	f := newResolverFixture(t)
+	carol := createTestUser(t, f.store, "carol")
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Watched"})
+	other := f.createTask(t, f.bob, model.CreateTaskInput{Title: "Other"})
+
+	watcherIDs := func() string {
+		ids, err := f.store.Watchers().WatcherIDs(context.Background(), task.ID)
+		if err != nil {
+			t.Fatalf("WatcherIDs() error = %v", err)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	if got := watcherIDs(); got != f.alice.ID {
+		t.Errorf("Expected the creator to watch, got %s", got)
+	}
+	if _, err := f.resolver.Mutation().AssignTask(as(f.alice), task.ID, f.bob.ID); err != nil {
+		t.Fatalf("AssignTask() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().WatchTask(as(carol), task.ID); err != nil {
+		t.Fatalf("WatchTask() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().WatchTask(as(carol), task.ID); err != nil {
+		t.Errorf("Expected watching twice to succeed, got %v", err)
+	}
+	if got, want := watcherIDs(), strings.Join([]string{f.alice.ID, f.bob.ID, carol.ID}, ","); got != want {
+		t.Errorf("Expected watchers %s, got %s", want, got)
+	}
+
+	watchers, err := f.resolver.Task().Watchers(as(f.alice), task)
+	if err != nil || len(watchers) != 3 || watchers[2].Name != "carol" {
+		t.Errorf("Expected three watchers ending with Carol, got %d (%v)", len(watchers), err)
+	}
+
+	watched, err := f.resolver.Query().WatchedTasks(as(f.bob))
+	if err != nil {
+		t.Fatalf("WatchedTasks() error = %v", err)
+	}
+	if got := taskIDs(watched); strings.Join(got, ",") != other.ID+","+task.ID {
+		t.Errorf("Expected Bob to watch both tasks, got %v", got)
+	}
+
+	// Creators can stop watching, and stay unwatched while the assignee is unchanged
+	if _, err := f.resolver.Mutation().UnwatchTask(as(f.alice), task.ID); err != nil {
+		t.Fatalf("UnwatchTask() error = %v", err)
+	}
+	high := model.PriorityHigh
+	if _, err := f.resolver.Mutation().UpdateTask(as(f.alice), task.ID, model.UpdateTaskInput{Priority: &high, AssignedToID: &f.bob.ID}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if watched, _ := f.resolver.Query().WatchedTasks(as(f.alice)); len(watched) != 0 {
+		t.Errorf("Expected Alice to watch nothing, got %v", taskIDs(watched))
+	}
+
+	if _, err := f.resolver.Mutation().DeleteTask(as(f.alice), task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if watched, _ := f.resolver.Query().WatchedTasks(as(carol)); len(watched) != 0 {
+		t.Errorf("Expected deleted tasks to be left out, got %v", taskIDs(watched))
+	}
+	if _, err := f.resolver.Mutation().WatchTask(as(carol), task.ID); err == nil || err.Error() != "task not found" {
+		t.Errorf("Expected task not found, got %v", err)
+	}
+}
+
+func TestResolver_BulkUpdateAtomic(t *testing.T) {
+	f := newResolverFixture(t)
+	mine := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Mine"})
+	theirs := f.createTask(t, f.bob, model.CreateTaskInput{Title: "Theirs"})
+	done := model.TaskStatusDone
+
+	payload, err := f.resolver.Mutation().BulkUpdateTasks(as(f.alice), []string{mine.ID, theirs.ID}, model.UpdateTaskInput{Status: &done}, true)
+	if err != nil {
+		t.Fatalf("BulkUpdateTasks() error = %v", err)
+	}
+	if payload.Succeeded != 0 || payload.Failed != 2 {
+		t.Errorf("Expected the whole batch to fail, got %d succeeded and %d failed", payload.Succeeded, payload.Failed)
+	}
+	if payload.Results[0].Error == nil || *payload.Results[0].Error != repository.ErrBulkRolledBack.Error() {
+		t.Errorf("Expected the first task to be rolled back, got %v", payload.Results[0].Error)
+	}
+	if task, _ := f.store.Tasks().GetByID(context.Background(), mine.ID); task.Status != "TODO" {
+		t.Errorf("Expected rolled back task to keep its status, got %s", task.Status)
+	}
+
+	payload, err = f.resolver.Mutation().BulkUpdateTasks(as(f.alice), []string{mine.ID, theirs.ID}, model.UpdateTaskInput{Status: &done}, false)
+	if err != nil {
+		t.Fatalf("BulkUpdateTasks() error = %v", err)
+	}
+	if payload.Succeeded != 1 || payload.Failed != 1 {
+		t.Errorf("Expected one success and one failure, got %d and %d", payload.Succeeded, payload.Failed)
+	}
+}
+
+func TestResolver_RecurringTaskCompletion(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := context.Background()
+	due := time.Date(2030, 1, 1, 9, 0, 0, 0, time.UTC)
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Daily", DueDate: &due, Recurrence: strPtr("FREQ=DAILY")})
+
+	// Changes made in a failed transaction are undone
+	stored, _ := f.store.Tasks().GetByID(ctx, task.ID)
+	err := f.store.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := f.store.Tasks().Update(ctx, task.ID, map[string]interface{}{"recurrence_rule": "not a rule"}); err != nil {
+			return err
+		}
+		return errors.New("rolled back")
+	})
+	if err == nil {
+		t.Fatal("Expected WithTx to return fn's error")
+	}
+	if after, _ := f.store.Tasks().GetByID(ctx, task.ID); *after.RecurrenceRule != *stored.RecurrenceRule {
+		t.Errorf("Expected WithTx to undo the update, got rule %q", *after.RecurrenceRule)
+	}
+
+	done := model.TaskStatusDone
+	if _, err := f.resolver.Mutation().UpdateTask(as(f.alice), task.ID, model.UpdateTaskInput{Status: &done}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	series, _ := f.store.Tasks().List(ctx, map[string]interface{}{"created_by_id": f.alice.ID})
+	if len(series) != 2 || series[0].Occurrence != 2 || !series[0].DueDate.Equal(due.AddDate(0, 0, 1)) {
+		t.Errorf("Expected the next occurrence to be scheduled, got %d tasks", len(series))
+	}
+}
+
+func TestResolver_CacheInvalidation(t *testing.T) {
+	f := newResolverFixture(t)
+	ctx := as(f.alice)
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Cached"})
+
+	seed := func() {
+		for _, key := range []string{cache.TaskKey(task.ID), cache.TasksKey(), cache.StatsKey("summary"), cache.UserKey(f.alice.ID)} {
+			f.cache.Set(context.Background(), key, "stale", time.Minute)
+		}
+	}
+
+	seed()
+	title := "Renamed"
+	if _, err := f.resolver.Mutation().UpdateTask(ctx, task.ID, model.UpdateTaskInput{Title: &title}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if keys := f.cache.Keys(); len(keys) != 1 || keys[0] != cache.UserKey(f.alice.ID) {
+		t.Errorf("Expected only the user key to survive an update, got %v", keys)
+	}
+
+	seed()
+	if _, err := f.resolver.Mutation().DeleteTask(ctx, task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if keys := f.cache.Keys(); len(keys) != 1 || keys[0] != cache.UserKey(f.alice.ID) {
+		t.Errorf("Expected only the user key to survive a delete, got %v", keys)
+	}
+}
+
+func taskIDs(tasks []*model.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -0,0 +1,142 @@
+package tests
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"taskboard/graph/model"
+	"taskboard/internal/auth"
+	"taskboard/internal/cache"
+)
+
+// authenticated returns a context for requests made with payload's access
+// token, as the auth middleware would set it up
+func authenticated(t *testing.T, payload *model.AuthPayload) context.Context {
+	claims, err := auth.NewJWTManager("test-secret-key", "test-refresh-key").ValidateAccessToken(payload.Token)
+	if err != nil {
+		t.Fatalf("ValidateAccessToken() error = %v", err)
+	}
+	return context.WithValue(context.Background(), auth.UserContextKey, claims)
+}
+
+func TestResolver_Sessions(t *testing.T) {
+	f := newResolverFixture(t)
+	laptop := auth.WithClient(context.Background(), auth.Client{UserAgent: "Firefox", IP: "192.0.2.1"})
+	phone := auth.WithClient(context.Background(), auth.Client{UserAgent: "Safari", IP: "198.51.100.7"})
+
+	first, err := f.resolver.Mutation().Register(laptop, model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	second, err := f.resolver.Mutation().Login(phone, model.LoginInput{Email: "carol@example.com", Password: "SecurePass123!"})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	// Refreshing moves the session to the front and records where from
+	moved := auth.WithClient(context.Background(), auth.Client{UserAgent: "Firefox", IP: "203.0.113.9"})
+	first, err = f.resolver.Mutation().RefreshToken(moved, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	sessions, err := f.resolver.Query().MySessions(authenticated(t, second))
+	if err != nil {
+		t.Fatalf("MySessions() error = %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	if sessions[0].IPAddress != "203.0.113.9" || sessions[0].UserAgent != "Firefox" || sessions[0].Current {
+		t.Errorf("Expected the refreshed laptop session first, got %+v", sessions[0])
+	}
+	if sessions[1].IPAddress != "198.51.100.7" || sessions[1].UserAgent != "Safari" || !sessions[1].Current {
+		t.Errorf("Expected the current phone session second, got %+v", sessions[1])
+	}
+
+	// Sessions of other users cannot be revoked
+	if _, err := f.resolver.Mutation().RevokeSession(as(f.alice), sessions[0].ID); err == nil {
+		t.Error("Expected revoking another user's session to fail")
+	}
+
+	if ok, err := f.resolver.Mutation().RevokeSession(authenticated(t, second), sessions[0].ID); err != nil || !ok {
+		t.Fatalf("RevokeSession() = %v, %v", ok, err)
+	}
+	if _, err := f.resolver.Mutation().RefreshToken(moved, first.RefreshToken); err == nil {
+		t.Error("Expected the revoked session's refresh token to be rejected")
+	}
+
+	sessions, err = f.resolver.Query().MySessions(authenticated(t, second))
+	if err != nil {
+		t.Fatalf("MySessions() error = %v", err)
+	}
+	if len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("Expected only the current session to remain, got %+v", sessions)
+	}
+}
+
+func TestAuthMiddleware_RejectsRevokedSessions(t *testing.T) {
+	f := newResolverFixture(t)
+
+	payload, err := f.resolver.Mutation().Register(context.Background(), model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	middleware := auth.NewAuthMiddleware(auth.NewJWTManager("test-secret-key", "test-refresh-key"), auth.NewSessionChecker(f.store.Sessions(), f.cache), false)
+	handler := middleware.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	request := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.Header.Set("Authorization", "Bearer "+payload.Token)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	if code := request(); code != http.StatusNoContent {
+		t.Fatalf("Expected an active session to be accepted, got %d", code)
+	}
+	claims := authenticated(t, payload).Value(auth.UserContextKey).(*auth.Claims)
+	var active bool
+	if err := f.cache.Get(context.Background(), cache.SessionKey(claims.SessionID), &active); err != nil || !active {
+		t.Errorf("Expected the lookup to be cached, got %v, %v", active, err)
+	}
+
+	if _, err := f.resolver.Mutation().RevokeSession(authenticated(t, payload), claims.SessionID); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if code := request(); code != http.StatusUnauthorized {
+		t.Errorf("Expected a revoked session to be rejected, got %d", code)
+	}
+}
+
+func TestAuthMiddleware_ClientAddress(t *testing.T) {
+	manager := auth.NewJWTManager("test-secret-key", "test-refresh-key")
+
+	for _, tc := range []struct {
+		trustProxy bool
+		want       string
+	}{
+		{false, "192.0.2.1"},
+		{true, "203.0.113.9"},
+	} {
+		var got auth.Client
+		handler := auth.NewAuthMiddleware(manager, nil, tc.trustProxy).Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = auth.ClientFromContext(r.Context())
+		}))
+
+		req := httptest.NewRequest(http.MethodPost, "/query", nil)
+		req.RemoteAddr = "192.0.2.1:52100"
+		req.Header.Set("User-Agent", "Firefox")
+		req.Header.Set("X-Forwarded-For", "10.0.0.1, 203.0.113.9")
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+
+		if got.IP != tc.want || got.UserAgent != "Firefox" {
+			t.Errorf("trustProxy %v: expected %s, got %+v", tc.trustProxy, tc.want, got)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskboard/graph"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+func TestSprintRepository_ScopeAndBurndown(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewSprintRepository(f.db)
+	ctx := context.Background()
+
+	day := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -3)
+	sprint, err := repo.Create(ctx, &models.Sprint{Name: "Sprint 1", StartDate: day, EndDate: day.AddDate(0, 0, 5), CreatedByID: f.alice.ID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	estimate := func(title string, points int, hours float64) *models.Task {
+		task := f.createTask(t, f.alice, title)
+		_, err := f.db.Exec(ctx, "UPDATE tasks SET story_points = $2, estimated_hours = $3 WHERE id = $1", task.ID, points, hours)
+		if err != nil {
+			t.Fatalf("Failed to estimate task: %v", err)
+		}
+		return task
+	}
+	a := estimate("A", 5, 2)
+	b := estimate("B", 3, 1)
+	c := estimate("C", 2, 0)
+
+	// Scope changes and completion are moved onto the sprint's days
+	for _, task := range []*models.Task{a, b, c} {
+		if err := repo.AddTask(ctx, sprint.ID, task.ID); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if err := repo.AddTask(ctx, sprint.ID, a.ID); !errors.Is(err, repository.ErrTaskAlreadyInSprint) {
+		t.Errorf("Expected a task to be in the sprint once, got %v", err)
+	}
+	if err := repo.RemoveTask(ctx, sprint.ID, b.ID); err != nil {
+		t.Fatalf("RemoveTask() error = %v", err)
+	}
+	if err := repo.RemoveTask(ctx, sprint.ID, b.ID); !errors.Is(err, repository.ErrTaskNotInSprint) {
+		t.Errorf("Expected removing a task twice to fail, got %v", err)
+	}
+	for _, stmt := range []struct {
+		sql  string
+		args []interface{}
+	}{
+		{"UPDATE sprint_tasks SET added_at = $2 WHERE task_id = $1", []interface{}{a.ID, day.Add(1 * time.Hour)}},
+		{"UPDATE sprint_tasks SET added_at = $2, removed_at = $3 WHERE task_id = $1", []interface{}{b.ID, day.Add(2 * time.Hour), day.AddDate(0, 0, 2).Add(time.Hour)}},
+		{"UPDATE sprint_tasks SET added_at = $2 WHERE task_id = $1", []interface{}{c.ID, day.AddDate(0, 0, 1).Add(5 * time.Hour)}},
+		{"UPDATE tasks SET status = 'DONE' WHERE id = $1", []interface{}{a.ID}},
+		{"UPDATE tasks SET completed_at = $2 WHERE id = $1", []interface{}{a.ID, day.AddDate(0, 0, 1).Add(2 * time.Hour)}},
+	} {
+		if _, err := f.db.Exec(ctx, stmt.sql, stmt.args...); err != nil {
+			t.Fatalf("%s: %v", stmt.sql, err)
+		}
+	}
+
+	tasks, err := repo.ListTasks(ctx, sprint.ID)
+	if err != nil || len(tasks) != 2 {
+		t.Fatalf("Expected A and C in the sprint, got %d tasks, %v", len(tasks), err)
+	}
+
+	totals, err := repo.Totals(ctx, sprint.ID)
+	if err != nil {
+		t.Fatalf("Totals() error = %v", err)
+	}
+	if want := (models.SprintTotals{ScopePoints: 7, CompletedPoints: 5, ScopeHours: 2, CompletedHours: 2}); *totals != want {
+		t.Errorf("Totals() = %+v, want %+v", *totals, want)
+	}
+
+	changes, err := repo.ScopeChanges(ctx, sprint.ID)
+	if err != nil {
+		t.Fatalf("ScopeChanges() error = %v", err)
+	}
+	wantChanges := []struct{ taskID, change string }{{a.ID, "ADDED"}, {b.ID, "ADDED"}, {c.ID, "ADDED"}, {b.ID, "REMOVED"}}
+	if len(changes) != len(wantChanges) {
+		t.Fatalf("Expected %d scope changes, got %d", len(wantChanges), len(changes))
+	}
+	for i, change := range changes {
+		if change.TaskID != wantChanges[i].taskID || change.Change != wantChanges[i].change {
+			t.Errorf("Change %d = %s %s, want %s %s", i, change.TaskID, change.Change, wantChanges[i].taskID, wantChanges[i].change)
+		}
+	}
+
+	points, err := repo.Burndown(ctx, sprint.ID)
+	if err != nil {
+		t.Fatalf("Burndown() error = %v", err)
+	}
+	want := []models.BurndownPoint{
+		{ScopePoints: 8, CompletedPoints: 0, ScopeHours: 3, CompletedHours: 0},
+		{ScopePoints: 10, CompletedPoints: 5, ScopeHours: 3, CompletedHours: 2},
+		{ScopePoints: 7, CompletedPoints: 5, ScopeHours: 2, CompletedHours: 2},
+		{ScopePoints: 7, CompletedPoints: 5, ScopeHours: 2, CompletedHours: 2},
+	}
+	if len(points) != len(want) {
+		t.Fatalf("Expected a point for each day until today, got %d", len(points))
+	}
+	for i, point := range points {
+		want[i].Date = day.AddDate(0, 0, i)
+		if !point.Date.Equal(want[i].Date) {
+			t.Errorf("Point %d is for %v, want %v", i, point.Date, want[i].Date)
+		}
+		point.Date = want[i].Date
+		if *point != want[i] {
+			t.Errorf("Point %d = %+v, want %+v", i, *point, want[i])
+		}
+	}
+}
+
+func TestResolver_SprintScopeRequiresManager(t *testing.T) {
+	f := newPostgresFixture(t)
+	task := f.createTask(t, f.bob, "Review")
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+
+	sprint, err := repository.NewSprintRepository(f.db).Create(context.Background(), &models.Sprint{Name: "Sprint 1", StartDate: day, EndDate: day.AddDate(0, 0, 14), CreatedByID: f.alice.ID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().AddTaskToSprint(as(f.bob), sprint.ID, task.ID); err == nil {
+		t.Error("Expected only the sprint's creator to change its scope")
+	}
+	updated, err := f.resolver.Mutation().AddTaskToSprint(as(f.alice), sprint.ID, task.ID)
+	if err != nil {
+		t.Fatalf("AddTaskToSprint() error = %v", err)
+	}
+
+	sprints := f.resolver.Sprint()
+	if points, err := sprints.ScopePoints(context.Background(), updated); err != nil || points != 0 {
+		t.Errorf("ScopePoints() = %d, %v", points, err)
+	}
+	if tasks, err := sprints.Tasks(context.Background(), updated); err != nil || len(tasks) != 1 {
+		t.Errorf("Expected the task in the sprint, got %d, %v", len(tasks), err)
+	}
+}
+
+func TestResolver_SprintTotalsQueriedOncePerOperation(t *testing.T) {
+	f := newPostgresFixture(t)
+	ctx := context.Background()
+	task := f.createTask(t, f.alice, "Design")
+	day := time.Now().UTC().Truncate(24 * time.Hour)
+
+	repo := repository.NewSprintRepository(f.db)
+	sprint, err := repo.Create(ctx, &models.Sprint{Name: "Sprint 1", StartDate: day, EndDate: day.AddDate(0, 0, 14), CreatedByID: f.alice.ID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := repo.AddTask(ctx, sprint.ID, task.ID); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	estimate := func(points int) {
+		if _, err := f.db.Exec(ctx, "UPDATE tasks SET story_points = $2, estimated_hours = $2 WHERE id = $1", task.ID, points); err != nil {
+			t.Fatalf("Failed to estimate task: %v", err)
+		}
+	}
+	estimate(3)
+
+	// Within an operation every totals field comes from the first query, so a
+	// change in between does not show
+	sprints := f.resolver.Sprint()
+	operation := graph.WithMemo(ctx)
+	if points, err := sprints.ScopePoints(operation, sprint); err != nil || points != 3 {
+		t.Fatalf("ScopePoints() = %d, %v", points, err)
+	}
+	estimate(8)
+	if hours, err := sprints.ScopeHours(operation, sprint); err != nil || hours != 3 {
+		t.Errorf("Expected ScopeHours() to share the first query, got %v, %v", hours, err)
+	}
+
+	if hours, err := sprints.ScopeHours(graph.WithMemo(ctx), sprint); err != nil || hours != 8 {
+		t.Errorf("Expected a new operation to query again, got %v, %v", hours, err)
+	}
+}
@@ -0,0 +1,176 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"taskboard/internal/storage"
+)
+
+// fakeS3 is a minimal in-memory stand-in for an S3-compatible object store
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	authz   []string
+	chunked bool
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.authz = append(f.authz, r.Header.Get("Authorization"))
+	if r.Header.Get("X-Amz-Date") == "" || r.Header.Get("X-Amz-Content-Sha256") != "UNSIGNED-PAYLOAD" {
+		http.Error(w, "missing signature headers", http.StatusForbidden)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		if len(r.TransferEncoding) > 0 {
+			f.chunked = true
+		}
+		body, _ := io.ReadAll(r.Body)
+		f.objects[r.URL.Path] = body
+	case http.MethodGet:
+		body, ok := f.objects[r.URL.Path]
+		if !ok {
+			http.Error(w, "<Error><Code>NoSuchKey</Code></Error>", http.StatusNotFound)
+			return
+		}
+		w.Write(body)
+	case http.MethodDelete:
+		delete(f.objects, r.URL.Path)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func testBlobRoundTrip(t *testing.T, blob storage.Blob) {
+	ctx := context.Background()
+	content := "screenshot bytes"
+
+	if err := blob.Put(ctx, "attachments/t1/a1", strings.NewReader(content), int64(len(content)), "image/png"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+
+	r, err := blob.Open(ctx, "attachments/t1/a1")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	got, _ := io.ReadAll(r)
+	r.Close()
+	if string(got) != content {
+		t.Errorf("Open() = %q, want %q", got, content)
+	}
+
+	if err := blob.Delete(ctx, "attachments/t1/a1"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := blob.Open(ctx, "attachments/t1/a1"); !errors.Is(err, storage.ErrNotFound) {
+		t.Errorf("Expected ErrNotFound after delete, got %v", err)
+	}
+	if err := blob.Delete(ctx, "attachments/t1/a1"); err != nil {
+		t.Errorf("Deleting a missing blob should succeed, got %v", err)
+	}
+}
+
+func TestStorage_LocalBlob(t *testing.T) {
+	blob, err := storage.NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+
+	testBlobRoundTrip(t, blob)
+
+	if err := blob.Put(context.Background(), "../escape", strings.NewReader("x"), 1, "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if _, err := blob.Open(context.Background(), "escape"); err != nil {
+		t.Errorf("Expected key to stay inside the root, got %v", err)
+	}
+}
+
+func TestStorage_S3Blob(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	blob, err := storage.NewS3Blob(server.URL, "us-east-1", "taskboard", "AKID", "secret")
+	if err != nil {
+		t.Fatalf("NewS3Blob() error = %v", err)
+	}
+
+	testBlobRoundTrip(t, blob)
+
+	for _, authz := range fake.authz {
+		if !strings.HasPrefix(authz, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+			!strings.Contains(authz, "/us-east-1/s3/aws4_request") ||
+			!strings.Contains(authz, "SignedHeaders=") || !strings.Contains(authz, "Signature=") {
+			t.Errorf("Unexpected Authorization header %q", authz)
+		}
+	}
+	if _, ok := fake.objects["/taskboard/attachments/t1/a1"]; ok {
+		t.Error("Expected object to be deleted from the bucket")
+	}
+}
+
+func TestStorage_S3BlobEmptyObject(t *testing.T) {
+	fake := &fakeS3{objects: map[string][]byte{}}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	blob, err := storage.NewS3Blob(server.URL, "us-east-1", "taskboard", "AKID", "secret")
+	if err != nil {
+		t.Fatalf("NewS3Blob() error = %v", err)
+	}
+
+	if err := blob.Put(context.Background(), "attachments/t1/empty", io.LimitReader(strings.NewReader(""), 1), 0, "text/plain"); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if fake.chunked {
+		t.Error("Expected an empty object to be sent with a Content-Length, not chunked")
+	}
+	if body, ok := fake.objects["/taskboard/attachments/t1/empty"]; !ok || len(body) != 0 {
+		t.Errorf("Expected an empty object, got %q, %v", body, ok)
+	}
+}
+
+func TestStorage_URLSigner(t *testing.T) {
+	signer := storage.NewURLSigner("secret", 15*time.Minute)
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	signed := signer.Sign("/attachments/a1", now)
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("Sign() returned invalid URL %q", signed)
+	}
+
+	if err := signer.Verify("/attachments/a1", u.Query(), now.Add(time.Minute)); err != nil {
+		t.Errorf("Verify() error = %v", err)
+	}
+	if err := signer.Verify("/attachments/a2", u.Query(), now); !errors.Is(err, storage.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for another path, got %v", err)
+	}
+	if err := signer.Verify("/attachments/a1", u.Query(), now.Add(time.Hour)); !errors.Is(err, storage.ErrURLExpired) {
+		t.Errorf("Expected ErrURLExpired, got %v", err)
+	}
+
+	tampered := u.Query()
+	tampered.Set("expires", "9999999999")
+	if err := signer.Verify("/attachments/a1", tampered, now); !errors.Is(err, storage.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for extended expiry, got %v", err)
+	}
+
+	other := storage.NewURLSigner("other", 15*time.Minute)
+	if err := other.Verify("/attachments/a1", u.Query(), now); !errors.Is(err, storage.ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature with another secret, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+	"taskboard/internal/repository/memory"
+)
+
+// storeImpl is one implementation of the stores the resolvers depend on
+type storeImpl struct {
+	users repository.UserStore
+	tasks repository.TaskStore
+}
+
+// forEachStore runs fn against new in-memory stores and, when a test database
+// is configured, against the Postgres repositories, so tests relying on the
+// memory store stay representative
+func forEachStore(t *testing.T, fn func(t *testing.T, s storeImpl)) {
+	t.Run("memory", func(t *testing.T) {
+		store := memory.New()
+		fn(t, storeImpl{users: store.Users(), tasks: store.Tasks()})
+	})
+	t.Run("postgres", func(t *testing.T) {
+		db := testDB(t)
+		fn(t, storeImpl{users: repository.NewUserRepository(db), tasks: repository.NewTaskRepository(db)})
+	})
+}
+
+func TestStores_SharedSemantics(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		run  func(t *testing.T, s storeImpl, user *models.User)
+	}{
+		{"unknown user is not found", func(t *testing.T, s storeImpl, user *models.User) {
+			for _, id := range []string{"missing", "00000000-0000-0000-0000-000000000000"} {
+				if _, err := s.users.GetByID(ctx, id); err == nil || err.Error() != "user not found" {
+					t.Errorf("GetByID(%q) error = %v, want user not found", id, err)
+				}
+			}
+		}},
+		{"unknown task is not found", func(t *testing.T, s storeImpl, user *models.User) {
+			for _, id := range []string{"missing", "00000000-0000-0000-0000-000000000000"} {
+				if _, err := s.tasks.GetByID(ctx, id); err == nil || err.Error() != "task not found" {
+					t.Errorf("GetByID(%q) error = %v, want task not found", id, err)
+				}
+				if _, err := s.tasks.GetDeleted(ctx, id); err == nil || err.Error() != "task not found" {
+					t.Errorf("GetDeleted(%q) error = %v, want task not found", id, err)
+				}
+			}
+		}},
+		{"duplicate email is rejected", func(t *testing.T, s storeImpl, user *models.User) {
+			if _, err := s.users.Create(ctx, &models.User{Email: user.Email, Name: "Other"}); err == nil {
+				t.Error("Expected a duplicate email to be rejected")
+			}
+		}},
+		{"invalid status is rejected", func(t *testing.T, s storeImpl, user *models.User) {
+			if _, err := s.tasks.Create(ctx, &models.Task{Title: "Bad", Status: "BLOCKED", Priority: "LOW", CreatedByID: user.ID}); err == nil {
+				t.Error("Expected an invalid status to be rejected")
+			}
+		}},
+		{"returned tasks are copies", func(t *testing.T, s storeImpl, user *models.User) {
+			task, err := s.tasks.Create(ctx, &models.Task{Title: "Task", Status: "TODO", Priority: "LOW", CreatedByID: user.ID})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			task.Title = "Changed"
+			if stored, _ := s.tasks.GetByID(ctx, task.ID); stored.Title != "Task" {
+				t.Errorf("Expected stored task to be unaffected, got %q", stored.Title)
+			}
+		}},
+		{"unknown assignee is rejected", func(t *testing.T, s storeImpl, user *models.User) {
+			task, err := s.tasks.Create(ctx, &models.Task{Title: "Task", Status: "TODO", Priority: "LOW", CreatedByID: user.ID})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			for _, id := range []string{"missing", "00000000-0000-0000-0000-000000000000"} {
+				if _, err := s.tasks.Update(ctx, task.ID, map[string]interface{}{"assigned_to_id": strPtr(id)}); err == nil {
+					t.Errorf("Expected assignee %q to be rejected", id)
+				}
+			}
+		}},
+		{"count and paging", func(t *testing.T, s storeImpl, user *models.User) {
+			for _, status := range []string{"TODO", "DONE"} {
+				if _, err := s.tasks.Create(ctx, &models.Task{Title: status, Status: status, Priority: "LOW", CreatedByID: user.ID}); err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+			}
+			count, err := s.tasks.Count(ctx, map[string]interface{}{"status": "TODO", "limit": 0})
+			if err != nil || count != 1 {
+				t.Errorf("Count() = %d, %v, want 1", count, err)
+			}
+			page, err := s.tasks.List(ctx, map[string]interface{}{"offset": 2})
+			if err != nil || len(page) != 0 {
+				t.Errorf("Expected an empty page, got %d tasks, %v", len(page), err)
+			}
+		}},
+		{"user count and paging", func(t *testing.T, s storeImpl, user *models.User) {
+			if _, err := s.users.Create(ctx, &models.User{Email: "dave@example.com", Name: "Dave"}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			count, err := s.users.Count(ctx)
+			if err != nil || count != 2 {
+				t.Errorf("Count() = %d, %v, want 2", count, err)
+			}
+			page, err := s.users.ListPage(ctx, 1, 1)
+			if err != nil || len(page) != 1 {
+				t.Fatalf("ListPage() = %d users, %v, want 1", len(page), err)
+			}
+			if page, err := s.users.ListPage(ctx, 10, 2); err != nil || len(page) != 0 {
+				t.Errorf("Expected an empty page, got %d users, %v", len(page), err)
+			}
+		}},
+		{"user IDs by lowercased email", func(t *testing.T, s storeImpl, user *models.User) {
+			ids, err := s.users.IDsByEmail(ctx, []string{"carol@example.com", "nobody@example.com"})
+			if err != nil {
+				t.Fatalf("IDsByEmail() error = %v", err)
+			}
+			if len(ids) != 1 || ids["carol@example.com"] != user.ID {
+				t.Errorf("Expected only Carol's ID, got %v", ids)
+			}
+		}},
+		{"calendar token lookup", func(t *testing.T, s storeImpl, user *models.User) {
+			if err := s.users.SetCalendarTokenHash(ctx, user.ID, "hash"); err != nil {
+				t.Fatalf("SetCalendarTokenHash() error = %v", err)
+			}
+			if owner, err := s.users.GetByCalendarTokenHash(ctx, "hash"); err != nil || owner == nil || owner.ID != user.ID {
+				t.Errorf("GetByCalendarTokenHash() = %v, %v, want Carol", owner, err)
+			}
+			if owner, err := s.users.GetByCalendarTokenHash(ctx, "other"); err != nil || owner != nil {
+				t.Errorf("Expected no owner for an unknown token, got %v, %v", owner, err)
+			}
+		}},
+		{"completed tasks are archived", func(t *testing.T, s storeImpl, user *models.User) {
+			done, err := s.tasks.Create(ctx, &models.Task{Title: "Done", Status: "DONE", Priority: "LOW", CreatedByID: user.ID})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			if _, err := s.tasks.Create(ctx, &models.Task{Title: "Open", Status: "TODO", Priority: "LOW", CreatedByID: user.ID}); err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+			archived, err := s.tasks.ArchiveCompleted(ctx, time.Now().Add(time.Minute))
+			if err != nil || archived != 1 {
+				t.Fatalf("ArchiveCompleted() = %d, %v, want 1", archived, err)
+			}
+			if task, _ := s.tasks.GetByID(ctx, done.ID); task.ArchivedAt == nil {
+				t.Error("Expected the completed task to be archived")
+			}
+			if archived, _ := s.tasks.ArchiveCompleted(ctx, time.Now().Add(time.Minute)); archived != 0 {
+				t.Errorf("Expected archived tasks to be skipped, got %d", archived)
+			}
+		}},
+		{"bulk created tasks", func(t *testing.T, s storeImpl, user *models.User) {
+			tasks := []*models.Task{
+				{Title: "One", Status: "TODO", Priority: "LOW", CreatedByID: user.ID},
+				{Title: "Two", Status: "TODO", Priority: "LOW", CreatedByID: user.ID},
+			}
+			created, err := s.tasks.BulkCreate(ctx, tasks)
+			if err != nil || created != 2 {
+				t.Fatalf("BulkCreate() = %d, %v, want 2", created, err)
+			}
+			if task, err := s.tasks.GetByID(ctx, tasks[1].ID); err != nil || task.Title != "Two" {
+				t.Errorf("Expected the second task to be stored, got %v, %v", task, err)
+			}
+			bad := []*models.Task{
+				{Title: "Three", Status: "TODO", Priority: "LOW", CreatedByID: user.ID},
+				{Title: "Bad", Status: "BLOCKED", Priority: "LOW", CreatedByID: user.ID},
+			}
+			if _, err := s.tasks.BulkCreate(ctx, bad); err == nil {
+				t.Error("Expected an invalid status to be rejected")
+			}
+			if count, _ := s.tasks.Count(ctx, map[string]interface{}{}); count != 2 {
+				t.Errorf("Expected a failed bulk insert to store nothing, got %d tasks", count)
+			}
+		}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			forEachStore(t, func(t *testing.T, s storeImpl) {
+				user, err := s.users.Create(ctx, &models.User{Email: "carol@example.com", Name: "Carol"})
+				if err != nil {
+					t.Fatalf("Create() error = %v", err)
+				}
+				tc.run(t, s, user)
+			})
+		})
+	}
+}
+
+// The memory store breaks created_at ties by insertion order, so only
+// Postgres shows whether pages of tasks created together are stable
+func TestTaskRepository_DefaultOrderPagesStably(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTaskRepository(f.db)
+	ctx := context.Background()
+
+	for i := 0; i < 20; i++ {
+		f.createTask(t, f.alice, fmt.Sprintf("Imported %d", i))
+	}
+	f.exec(t, `UPDATE tasks SET created_at = '2024-03-01T09:00:00Z'`)
+
+	seen := make(map[string]bool)
+	previous := ""
+	for offset := 0; offset < 20; offset += 3 {
+		page, err := repo.List(ctx, map[string]interface{}{"limit": 3, "offset": offset})
+		if err != nil {
+			t.Fatalf("List() error = %v", err)
+		}
+		for _, task := range page {
+			if seen[task.ID] {
+				t.Errorf("Task %s appeared on two pages", task.ID)
+			}
+			if previous != "" && task.ID > previous {
+				t.Errorf("Expected ties in descending ID order, got %s after %s", task.ID, previous)
+			}
+			seen[task.ID] = true
+			previous = task.ID
+		}
+	}
+	if len(seen) != 20 {
+		t.Errorf("Expected every task once, got %d", len(seen))
+	}
+}
@@ -0,0 +1,100 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/graph/model"
+	"taskboard/internal/api"
+	"taskboard/internal/tasks"
+)
+
+// The REST API and the resolvers create and update tasks through the same
+// service, so they reject the same input with the same message
+func TestTasks_RESTAndGraphQLAgree(t *testing.T) {
+	f := newResolverFixture(t)
+	service := tasks.NewService(f.store.Users(), f.store.Tasks(), f.store.Mentions(), f.store)
+	handler := api.NewHandler(f.store.Users(), f.store.Tasks(), service, nil, nil, false)
+
+	send := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body)).WithContext(as(f.alice))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		return w
+	}
+	restError := func(w *httptest.ResponseRecorder) string {
+		if w.Code != http.StatusUnprocessableEntity {
+			t.Errorf("Expected status 422, got %d: %s", w.Code, w.Body)
+		}
+		var body api.Error
+		json.Unmarshal(w.Body.Bytes(), &body)
+		return body.Error
+	}
+
+	due := time.Now().Add(24 * time.Hour)
+	negative := -1
+	blank := " "
+	missing := "00000000-0000-0000-0000-000000000000"
+	daily := "FREQ=DAILY"
+	bogus := "FREQ=SOMETIMES"
+
+	createTests := []struct {
+		name  string
+		body  string
+		input model.CreateTaskInput
+		want  string
+	}{
+		{"blank title", `{"title":" "}`, model.CreateTaskInput{Title: " "}, "title is required"},
+		{"negative story points", `{"title":"T","story_points":-1}`, model.CreateTaskInput{Title: "T", StoryPoints: &negative}, "story points cannot be negative"},
+		{"unknown assignee", `{"title":"T","assigned_to_id":"` + missing + `"}`, model.CreateTaskInput{Title: "T", AssignedToID: &missing}, "assignee not found"},
+		{"recurrence without due date", `{"title":"T","recurrence":"FREQ=DAILY"}`, model.CreateTaskInput{Title: "T", Recurrence: &daily}, "a recurring task needs a due date"},
+	}
+	for _, tt := range createTests {
+		t.Run("create with "+tt.name, func(t *testing.T) {
+			got := restError(send(http.MethodPost, "/api/v1/tasks", tt.body))
+			_, err := f.resolver.Mutation().CreateTask(as(f.alice), tt.input)
+			if got != tt.want || err == nil || err.Error() != tt.want {
+				t.Errorf("Expected both APIs to reject with %q, got %q over REST and %v over GraphQL", tt.want, got, err)
+			}
+		})
+	}
+
+	task := f.createTask(t, f.alice, model.CreateTaskInput{Title: "Task", DueDate: &due})
+
+	updateTests := []struct {
+		name  string
+		body  string
+		input model.UpdateTaskInput
+	}{
+		{"blank title", `{"title":" "}`, model.UpdateTaskInput{Title: &blank}},
+		{"negative story points", `{"story_points":-1}`, model.UpdateTaskInput{StoryPoints: &negative}},
+		{"unknown assignee", `{"assigned_to_id":"` + missing + `"}`, model.UpdateTaskInput{AssignedToID: &missing}},
+		{"invalid recurrence", `{"recurrence":"FREQ=SOMETIMES"}`, model.UpdateTaskInput{Recurrence: &bogus}},
+	}
+	for _, tt := range updateTests {
+		t.Run("update with "+tt.name, func(t *testing.T) {
+			got := restError(send(http.MethodPatch, "/api/v1/tasks/"+task.ID, tt.body))
+			_, err := f.resolver.Mutation().UpdateTask(as(f.alice), task.ID, tt.input)
+			if got == "" || err == nil || err.Error() != got {
+				t.Errorf("Expected both APIs to reject with the same message, got %q over REST and %v over GraphQL", got, err)
+			}
+		})
+	}
+
+	// Completing an occurrence over REST schedules the next one, as it does
+	// over GraphQL
+	if w := send(http.MethodPatch, "/api/v1/tasks/"+task.ID, `{"recurrence":"FREQ=DAILY","status":"DONE","description":"@bob done"}`); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	if count, _ := f.store.Tasks().Count(as(f.alice), map[string]interface{}{"status": "TODO"}); count != 1 {
+		t.Errorf("Expected the next occurrence to be scheduled, got %d open tasks", count)
+	}
+	if mentioned, _ := f.resolver.Query().MentionedTasks(as(f.bob)); len(mentioned) != 1 {
+		t.Errorf("Expected Bob to be mentioned, got %v", taskIDs(mentioned))
+	}
+}
@@ -0,0 +1,250 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"taskboard/graph/model"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+)
+
+func TestTimeEntryRepository_OneRunningTimerPerUser(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTimeEntryRepository(f.db)
+	ctx := context.Background()
+	task := f.createTask(t, f.alice, "Write report")
+	started := time.Now().Add(-time.Hour).Truncate(time.Second)
+
+	if _, err := repo.Create(ctx, &models.TimeEntry{TaskID: task.ID, UserID: f.alice.ID, StartedAt: started}); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if _, err := repo.Create(ctx, &models.TimeEntry{TaskID: task.ID, UserID: f.alice.ID, StartedAt: started}); !errors.Is(err, repository.ErrTimerRunning) {
+		t.Errorf("Expected a second running timer to be rejected, got %v", err)
+	}
+	if _, err := repo.Create(ctx, &models.TimeEntry{TaskID: task.ID, UserID: f.bob.ID, StartedAt: started}); err != nil {
+		t.Errorf("Expected other users to run a timer of their own, got %v", err)
+	}
+	ended := started.Add(-time.Minute)
+	if _, err := repo.Create(ctx, &models.TimeEntry{TaskID: task.ID, UserID: f.alice.ID, StartedAt: started.Add(-time.Hour), EndedAt: &ended}); err != nil {
+		t.Errorf("Expected finished entries alongside a running timer, got %v", err)
+	}
+
+	// A stop time before the start is clamped to the start
+	stopped, err := repo.Stop(ctx, f.alice.ID, started.Add(-time.Minute))
+	if err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	if stopped.EndedAt == nil || !stopped.EndedAt.Equal(started) {
+		t.Errorf("Expected the timer to end at its start, got %v", stopped.EndedAt)
+	}
+	if _, err := repo.Stop(ctx, f.alice.ID, time.Now()); !errors.Is(err, repository.ErrNoRunningTimer) {
+		t.Errorf("Expected no running timer after stopping, got %v", err)
+	}
+	if running, err := repo.GetRunning(ctx, f.alice.ID); err != nil || running != nil {
+		t.Errorf("GetRunning() = %v, %v, want none", running, err)
+	}
+	if running, err := repo.GetRunning(ctx, f.bob.ID); err != nil || running == nil {
+		t.Errorf("Expected bob's timer to keep running, got %v, %v", running, err)
+	}
+}
+
+func TestTimeEntryRepository_Report(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTimeEntryRepository(f.db)
+	ctx := context.Background()
+	design := f.createTask(t, f.alice, "Design")
+	review := f.createTask(t, f.alice, "Review")
+	deploy := f.createTask(t, f.bob, "Deploy")
+	trashed := f.createTask(t, f.alice, "Trashed")
+
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2024, time.March, day, hour, minute, 0, 0, time.UTC)
+	}
+	for _, entry := range []struct {
+		user           *models.User
+		task           *models.Task
+		started, ended time.Time
+	}{
+		{f.alice, design, at(1, 9, 0), at(1, 10, 0)},
+		{f.alice, review, at(1, 23, 30), at(2, 0, 30)},
+		{f.alice, design, at(2, 10, 0), at(2, 10, 30)},
+		// Starts before the range, so only the hour inside it counts
+		{f.alice, design, at(0, 23, 0), at(1, 1, 0)},
+		// Ends after the range
+		{f.alice, review, at(3, 23, 0), at(4, 2, 0)},
+		{f.bob, design, at(1, 12, 0), at(1, 13, 0)},
+		// On bob's own task, so hidden from alice
+		{f.bob, deploy, at(2, 12, 0), at(2, 14, 0)},
+		// On a task in the trash, so hidden from everyone
+		{f.alice, trashed, at(2, 15, 0), at(2, 16, 0)},
+	} {
+		ended := entry.ended
+		if _, err := repo.Create(ctx, &models.TimeEntry{TaskID: entry.task.ID, UserID: entry.user.ID, StartedAt: entry.started, EndedAt: &ended}); err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+	}
+	if err := repository.NewTaskRepository(f.db).Delete(ctx, trashed.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	from, to := at(1, 0, 0), at(4, 0, 0)
+
+	tests := []struct {
+		name    string
+		viewer  *models.User
+		groupBy string
+		userID  *string
+		want    []models.TimeReportRow
+	}{
+		{"task", f.alice, repository.GroupByTask, &f.alice.ID, []models.TimeReportRow{
+			{Key: design.ID, Label: "Design", Seconds: 9000},
+			{Key: review.ID, Label: "Review", Seconds: 7200},
+		}},
+		{"user", f.alice, repository.GroupByUser, nil, []models.TimeReportRow{
+			{Key: f.alice.ID, Label: "alice", Seconds: 16200},
+			{Key: f.bob.ID, Label: "bob", Seconds: 3600},
+		}},
+		{"day", f.alice, repository.GroupByDay, &f.alice.ID, []models.TimeReportRow{
+			{Key: "2024-03-01", Label: "2024-03-01", Seconds: 7200},
+			{Key: "2024-02-29", Label: "2024-02-29", Seconds: 3600},
+			{Key: "2024-03-03", Label: "2024-03-03", Seconds: 3600},
+			{Key: "2024-03-02", Label: "2024-03-02", Seconds: 1800},
+		}},
+		{"other user", f.alice, repository.GroupByTask, &f.bob.ID, []models.TimeReportRow{
+			{Key: design.ID, Label: "Design", Seconds: 3600},
+		}},
+		{"own time on other tasks", f.bob, repository.GroupByTask, nil, []models.TimeReportRow{
+			{Key: deploy.ID, Label: "Deploy", Seconds: 7200},
+			{Key: design.ID, Label: "Design", Seconds: 3600},
+		}},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := repo.Report(ctx, tc.viewer.ID, tc.userID, from, to, tc.groupBy)
+			if err != nil {
+				t.Fatalf("Report() error = %v", err)
+			}
+			if len(report) != len(tc.want) {
+				t.Fatalf("Expected %d rows, got %d", len(tc.want), len(report))
+			}
+			for i, row := range report {
+				if *row != tc.want[i] {
+					t.Errorf("Row %d = %+v, want %+v", i, *row, tc.want[i])
+				}
+			}
+		})
+	}
+
+	if _, err := repo.Report(ctx, f.alice.ID, nil, from, to, "WEEK"); err == nil {
+		t.Error("Expected an unsupported grouping to be rejected")
+	}
+}
+
+func TestResolver_TimeTracking(t *testing.T) {
+	f := newPostgresFixture(t)
+	task := f.createTask(t, f.alice, "Write report")
+
+	if _, err := f.resolver.Mutation().StartTimer(as(f.alice), task.ID); err != nil {
+		t.Fatalf("StartTimer() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().StartTimer(as(f.alice), task.ID); err == nil {
+		t.Error("Expected a second timer to be rejected")
+	}
+	running, err := f.resolver.Query().RunningTimer(as(f.alice))
+	if err != nil || running == nil || running.TaskID != task.ID {
+		t.Fatalf("RunningTimer() = %v, %v", running, err)
+	}
+	if _, err := f.resolver.Mutation().StopTimer(as(f.alice)); err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	own := f.createTask(t, f.bob, "Bob's own")
+	for _, entry := range []struct {
+		user *models.User
+		task *models.Task
+	}{{f.alice, task}, {f.bob, task}, {f.bob, own}} {
+		if _, err := f.resolver.Mutation().AddTimeEntry(as(entry.user), model.AddTimeEntryInput{TaskID: entry.task.ID, StartedAt: start, EndedAt: start.Add(time.Hour)}); err != nil {
+			t.Fatalf("AddTimeEntry() error = %v", err)
+		}
+	}
+
+	// The creator of a task sees everyone's time on it, but not on other tasks
+	from, to := start.Add(-time.Hour), time.Now().Add(time.Minute)
+	report, err := f.resolver.Query().TimeReport(as(f.alice), nil, from, to, model.TimeReportGroupingUser)
+	if err != nil {
+		t.Fatalf("TimeReport() error = %v", err)
+	}
+	if len(report) != 2 {
+		t.Fatalf("Expected alice's and bob's time, got %+v", report)
+	}
+	for _, row := range report {
+		if row.Key == f.bob.ID && row.Seconds != 3600 {
+			t.Errorf("Expected only bob's hour on alice's task, got %+v", row)
+		}
+	}
+
+	report, err = f.resolver.Query().TimeReport(as(f.alice), &f.bob.ID, from, to, model.TimeReportGroupingTask)
+	if err != nil {
+		t.Fatalf("TimeReport() error = %v", err)
+	}
+	if len(report) != 1 || report[0].Key != task.ID {
+		t.Errorf("Expected bob's time on alice's task only, got %+v", report)
+	}
+}
+
+func TestResolver_TaskTimeEntriesVisibility(t *testing.T) {
+	f := newPostgresFixture(t)
+	carol := f.createUser(t, "carol")
+	task := f.createTask(t, f.alice, "Write report")
+
+	start := time.Now().Add(-2 * time.Hour).Truncate(time.Second)
+	note := "private note"
+	for _, user := range []*models.User{f.alice, f.bob} {
+		if _, err := f.resolver.Mutation().AddTimeEntry(as(user), model.AddTimeEntryInput{TaskID: task.ID, StartedAt: start, EndedAt: start.Add(time.Hour), Note: &note}); err != nil {
+			t.Fatalf("AddTimeEntry() error = %v", err)
+		}
+	}
+
+	// The creator sees everyone's time, others only their own
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		entries int
+		total   int
+	}{
+		{"creator", as(f.alice), 2, 7200},
+		{"tracker", as(f.bob), 1, 3600},
+		{"third user", as(carol), 0, 0},
+		{"anonymous", context.Background(), 0, 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := f.resolver.Task().TimeEntries(tc.ctx, &model.Task{ID: task.ID})
+			if err != nil {
+				t.Fatalf("TimeEntries() error = %v", err)
+			}
+			if len(entries) != tc.entries {
+				t.Errorf("Expected %d entries, got %d", tc.entries, len(entries))
+			}
+			total, err := f.resolver.Task().TotalTimeSpent(tc.ctx, &model.Task{ID: task.ID})
+			if err != nil {
+				t.Fatalf("TotalTimeSpent() error = %v", err)
+			}
+			if total != tc.total {
+				t.Errorf("TotalTimeSpent() = %d, want %d", total, tc.total)
+			}
+		})
+	}
+}
+
+func TestResolver_TimeReportRequiresAuth(t *testing.T) {
+	f := newResolverFixture(t)
+	from := time.Now().Add(-24 * time.Hour)
+
+	if _, err := f.resolver.Query().TimeReport(context.Background(), nil, from, time.Now(), model.TimeReportGroupingTask); err == nil {
+		t.Error("Expected timeReport to require authentication")
+	}
+}
@@ -0,0 +1,193 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"taskboard/internal/attachments"
+	"taskboard/internal/jobs"
+	"taskboard/internal/models"
+	"taskboard/internal/repository"
+	"taskboard/internal/storage"
+)
+
+func storedTaskIDs(tasks []*models.Task) []string {
+	ids := make([]string, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	return ids
+}
+
+func TestTaskRepository_DeletedTasksAreHidden(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTaskRepository(f.db)
+	ctx := context.Background()
+	kept := f.createTask(t, f.alice, "Kept")
+	binned := f.createTask(t, f.alice, "Binned")
+	f.createTask(t, f.bob, "Bob's")
+
+	if err := repo.Delete(ctx, binned.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := repo.Delete(ctx, binned.ID); err == nil {
+		t.Error("Expected deleting a deleted task to fail")
+	}
+
+	if _, err := repo.GetByID(ctx, binned.ID); err == nil {
+		t.Error("Expected GetByID() to hide deleted tasks")
+	}
+	if _, err := repo.Update(ctx, binned.ID, map[string]interface{}{"title": "Changed"}); err == nil {
+		t.Error("Expected deleted tasks not to be updated")
+	}
+	tasks, err := repo.List(ctx, map[string]interface{}{"created_by_id": f.alice.ID})
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if got := storedTaskIDs(tasks); len(got) != 1 || got[0] != kept.ID {
+		t.Errorf("Expected List() to hide deleted tasks, got %v", got)
+	}
+	if count, err := repo.Count(ctx, map[string]interface{}{"created_by_id": f.alice.ID}); err != nil || count != 1 {
+		t.Errorf("Count() = %d, %v, want 1", count, err)
+	}
+
+	deleted, err := repo.GetDeleted(ctx, binned.ID)
+	if err != nil || deleted.DeletedAt == nil {
+		t.Fatalf("GetDeleted() = %v, %v", deleted, err)
+	}
+	if _, err := repo.GetDeleted(ctx, kept.ID); err == nil {
+		t.Error("Expected GetDeleted() to skip tasks that are not deleted")
+	}
+
+	// The trash holds only the caller's own tasks
+	trash, err := repo.ListDeleted(ctx, f.alice.ID)
+	if err != nil {
+		t.Fatalf("ListDeleted() error = %v", err)
+	}
+	if got := storedTaskIDs(trash); len(got) != 1 || got[0] != binned.ID {
+		t.Errorf("Expected the deleted task in alice's trash, got %v", got)
+	}
+	if trash, err := repo.ListDeleted(ctx, f.bob.ID); err != nil || len(trash) != 0 {
+		t.Errorf("Expected bob's trash to be empty, got %v, %v", storedTaskIDs(trash), err)
+	}
+
+	restored, err := repo.Restore(ctx, binned.ID)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if restored.DeletedAt != nil {
+		t.Errorf("Expected deletedAt to be cleared, got %v", restored.DeletedAt)
+	}
+	if _, err := repo.Restore(ctx, binned.ID); err == nil {
+		t.Error("Expected restoring a task that is not deleted to fail")
+	}
+	if _, err := repo.GetByID(ctx, binned.ID); err != nil {
+		t.Errorf("Expected the restored task to be back, got %v", err)
+	}
+}
+
+func TestResolver_RestoreTask(t *testing.T) {
+	f := newPostgresFixture(t)
+	task := f.createTask(t, f.alice, "Binned")
+
+	if _, err := f.resolver.Mutation().DeleteTask(as(f.alice), task.ID); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	trash, err := f.resolver.Query().Trash(as(f.alice))
+	if err != nil {
+		t.Fatalf("Trash() error = %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != task.ID || trash[0].DeletedAt == nil {
+		t.Errorf("Expected the deleted task in the trash, got %v", taskIDs(trash))
+	}
+
+	if _, err := f.resolver.Mutation().RestoreTask(as(f.bob), task.ID); err == nil {
+		t.Error("Expected other users not to restore the task")
+	}
+	if _, err := f.resolver.Mutation().RestoreTask(as(f.alice), task.ID); err != nil {
+		t.Fatalf("RestoreTask() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().RestoreTask(as(f.alice), task.ID); err == nil {
+		t.Error("Expected restoring twice to fail")
+	}
+	if _, err := f.resolver.Query().Task(as(f.alice), task.ID); err != nil {
+		t.Errorf("Expected the restored task to be visible, got %v", err)
+	}
+}
+
+func TestJobs_PurgeDeletedTasks(t *testing.T) {
+	f := newPostgresFixture(t)
+	repo := repository.NewTaskRepository(f.db)
+	ctx := context.Background()
+	blob, err := storage.NewLocalBlob(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewLocalBlob() error = %v", err)
+	}
+	service := attachments.NewService(repository.NewAttachmentRepository(f.db), blob, storage.NewURLSigner("test-secret", time.Minute),
+		attachments.Limits{MaxBytes: 1 << 20, AllowedTypes: []string{"text/plain"}})
+
+	old := f.createTask(t, f.alice, "Deleted long ago")
+	recent := f.createTask(t, f.alice, "Deleted recently")
+	live := f.createTask(t, f.alice, "Not deleted")
+
+	upload := func(taskID string) string {
+		content := "meeting notes"
+		attachment, err := service.Upload(ctx, taskID, f.alice.ID, "notes.txt", strings.NewReader(content), int64(len(content)))
+		if err != nil {
+			t.Fatalf("Upload() error = %v", err)
+		}
+		return attachment.StorageKey
+	}
+	oldKeys := []string{upload(old.ID), upload(old.ID)}
+	recentKey := upload(recent.ID)
+	liveKey := upload(live.ID)
+
+	for _, id := range []string{old.ID, recent.ID} {
+		if err := repo.Delete(ctx, id); err != nil {
+			t.Fatalf("Delete() error = %v", err)
+		}
+	}
+	f.exec(t, `UPDATE tasks SET deleted_at = NOW() - INTERVAL '40 days' WHERE id = $1`, old.ID)
+
+	if err := jobs.PurgeDeletedTasks(repo, service, 30*24*time.Hour)(ctx); err != nil {
+		t.Fatalf("PurgeDeletedTasks() error = %v", err)
+	}
+
+	if _, err := repo.GetDeleted(ctx, old.ID); err == nil {
+		t.Error("Expected the task to be purged")
+	}
+	if _, err := repo.GetDeleted(ctx, recent.ID); err != nil {
+		t.Errorf("Expected a recently deleted task to stay in the trash, got %v", err)
+	}
+
+	exists := func(key string) bool {
+		content, err := blob.Open(ctx, key)
+		if errors.Is(err, storage.ErrNotFound) {
+			return false
+		}
+		if err != nil {
+			t.Fatalf("Open() error = %v", err)
+		}
+		content.Close()
+		return true
+	}
+	for _, key := range oldKeys {
+		if exists(key) {
+			t.Errorf("Expected %s to be deleted with its task", key)
+		}
+	}
+	for _, key := range []string{recentKey, liveKey} {
+		if !exists(key) {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+
+	// Nothing left to purge
+	purged, keys, err := repo.PurgeDeleted(ctx, time.Now().Add(-30*24*time.Hour))
+	if err != nil || purged != 0 || len(keys) != 0 {
+		t.Errorf("PurgeDeleted() = %d, %v, %v, want nothing", purged, keys, err)
+	}
+}
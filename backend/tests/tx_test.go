@@ -0,0 +1,80 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"taskboard/internal/repository"
+)
+
+func TestTransactor_SavepointNesting(t *testing.T) {
+	f := newPostgresFixture(t)
+	tx := repository.NewTransactor(f.db)
+	repo := repository.NewTaskRepository(f.db)
+	ctx := context.Background()
+	task := f.createTask(t, f.alice, "Original")
+	errInner := errors.New("inner failed")
+
+	title := func() string {
+		stored, err := repo.GetByID(ctx, task.ID)
+		if err != nil {
+			t.Fatalf("GetByID() error = %v", err)
+		}
+		return stored.Title
+	}
+
+	err := tx.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := repo.Update(ctx, task.ID, map[string]interface{}{"title": "Outer"}); err != nil {
+			return err
+		}
+		// Reads inside the transaction see its writes
+		if stored, err := repo.GetByID(ctx, task.ID); err != nil || stored.Title != "Outer" {
+			t.Errorf("GetByID() in the transaction = %v, %v, want the update", stored, err)
+		}
+
+		// A failing nested call only undoes its own work
+		err := tx.WithTx(ctx, func(ctx context.Context) error {
+			if _, err := repo.Update(ctx, task.ID, map[string]interface{}{"title": "Inner"}); err != nil {
+				return err
+			}
+			return errInner
+		})
+		if !errors.Is(err, errInner) {
+			t.Errorf("Expected the inner error, got %v", err)
+		}
+		if stored, err := repo.GetByID(ctx, task.ID); err != nil || stored.Title != "Outer" {
+			t.Errorf("Expected the savepoint to be rolled back, got %v, %v", stored, err)
+		}
+
+		// Other connections do not see uncommitted writes
+		if got := title(); got != "Original" {
+			t.Errorf("Expected the update to be invisible outside the transaction, got %q", got)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTx() error = %v", err)
+	}
+	if got := title(); got != "Outer" {
+		t.Errorf("Expected the outer update to be committed, got %q", got)
+	}
+
+	// A failing outer call undoes nested work that succeeded
+	err = tx.WithTx(ctx, func(ctx context.Context) error {
+		err := tx.WithTx(ctx, func(ctx context.Context) error {
+			_, err := repo.Update(ctx, task.ID, map[string]interface{}{"title": "Nested"})
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		return errInner
+	})
+	if !errors.Is(err, errInner) {
+		t.Errorf("Expected the outer error, got %v", err)
+	}
+	if got := title(); got != "Outer" {
+		t.Errorf("Expected the nested update to be rolled back, got %q", got)
+	}
+}
@@ -0,0 +1,235 @@
+package tests
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"taskboard/graph/model"
+	"taskboard/internal/mail"
+	"taskboard/internal/models"
+	"taskboard/internal/verification"
+)
+
+var mailRecipient = regexp.MustCompile(`(?m)^To: (.*)\r$`)
+
+// sentLink is a link carrying a token in a mail written to the mail directory
+type sentLink struct {
+	To    string
+	Token string
+}
+
+// sentLinks returns the links to page on the app in every mail written to
+// dir, skipping mails without one
+func sentLinks(t *testing.T, dir, page string) []sentLink {
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if err != nil {
+		t.Fatalf("Glob() error = %v", err)
+	}
+	pattern := regexp.MustCompile(`http://app\.test/` + regexp.QuoteMeta(page) + `\?token=(\S+)`)
+
+	var links []sentLink
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() error = %v", err)
+		}
+		match := pattern.FindSubmatch(data)
+		if match == nil {
+			continue
+		}
+		token, err := url.QueryUnescape(string(match[1]))
+		if err != nil {
+			t.Fatalf("QueryUnescape() error = %v", err)
+		}
+		link := sentLink{Token: token}
+		if to := mailRecipient.FindSubmatch(data); to != nil {
+			link.To = string(to[1])
+		}
+		links = append(links, link)
+	}
+	return links
+}
+
+func TestVerificationService_Check(t *testing.T) {
+	service := verification.NewService("secret", time.Hour, nil, "http://app.test", false)
+	now := time.Now()
+
+	token := service.Token("user-1", "carol@example.com", now)
+	userID, email, err := service.Check(token, now)
+	if err != nil || userID != "user-1" || email != "carol@example.com" {
+		t.Errorf("Check() = %q, %q, %v", userID, email, err)
+	}
+
+	if _, _, err := service.Check(token, now.Add(2*time.Hour)); !errors.Is(err, verification.ErrExpiredToken) {
+		t.Errorf("Expected an expired token, got %v", err)
+	}
+
+	other := verification.NewService("other-secret", time.Hour, nil, "http://app.test", false)
+	if _, _, err := other.Check(token, now); !errors.Is(err, verification.ErrInvalidToken) {
+		t.Errorf("Expected a token signed with another key to be rejected, got %v", err)
+	}
+
+	forged := service.Token("user-2", "carol@example.com", now)
+	_, signature, _ := strings.Cut(forged, ".")
+	payload, _, _ := strings.Cut(token, ".")
+	for _, bad := range []string{"", "garbage", payload + "." + signature, payload} {
+		if _, _, err := service.Check(bad, now); !errors.Is(err, verification.ErrInvalidToken) {
+			t.Errorf("Check(%q) error = %v, want ErrInvalidToken", bad, err)
+		}
+	}
+}
+
+func TestResolver_EmailVerification(t *testing.T) {
+	f := newResolverFixture(t)
+
+	payload, err := f.resolver.Mutation().Register(context.Background(), model.RegisterInput{Email: "carol@example.com", Password: "SecurePass123!", Name: "carol"})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if payload.User.EmailVerified {
+		t.Error("Expected a new account to be unverified")
+	}
+	links := sentLinks(t, f.mailDir, "verify-email")
+	if len(links) != 1 {
+		t.Fatalf("Expected one verification mail, got %d", len(links))
+	}
+	if links[0].To != "carol@example.com" {
+		t.Errorf("Expected the mail to go to the registered address, got %q", links[0].To)
+	}
+	token := links[0].Token
+
+	if ok, err := f.resolver.Mutation().VerifyEmail(context.Background(), token); err != nil || !ok {
+		t.Fatalf("VerifyEmail() = %v, %v", ok, err)
+	}
+	me, err := f.resolver.Query().Me(authenticated(t, payload))
+	if err != nil {
+		t.Fatalf("Me() error = %v", err)
+	}
+	if !me.EmailVerified {
+		t.Error("Expected the address to be verified")
+	}
+
+	if _, err := f.resolver.Mutation().VerifyEmail(context.Background(), token); err == nil {
+		t.Error("Expected a verification link to work once")
+	}
+	if _, err := f.resolver.Mutation().ResendVerification(authenticated(t, payload)); err == nil {
+		t.Error("Expected no new link for a verified address")
+	}
+	if _, err := f.resolver.Mutation().ResendVerification(context.Background()); err == nil {
+		t.Error("Expected resendVerification to require authentication")
+	}
+}
+
+func TestResolver_RequireVerifiedEmail(t *testing.T) {
+	f := newResolverFixtureWith(t, true)
+	ctx := as(f.alice)
+
+	if _, err := f.resolver.Mutation().CreateTask(ctx, model.CreateTaskInput{Title: "Write report"}); !errors.Is(err, verification.ErrNotVerified) {
+		t.Fatalf("Expected unverified users to be kept from creating tasks, got %v", err)
+	}
+
+	// Nor can they change what hangs off existing tasks
+	task, err := f.store.Tasks().Create(context.Background(), &models.Task{Title: "Existing", Status: "TODO", Priority: "LOW", CreatedByID: f.alice.ID})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	now := time.Now()
+	mutations := map[string]func() error{
+		"watchTask": func() error {
+			_, err := f.resolver.Mutation().WatchTask(ctx, task.ID)
+			return err
+		},
+		"unwatchTask": func() error {
+			_, err := f.resolver.Mutation().UnwatchTask(ctx, task.ID)
+			return err
+		},
+		"startTimer": func() error {
+			_, err := f.resolver.Mutation().StartTimer(ctx, task.ID)
+			return err
+		},
+		"stopTimer": func() error {
+			_, err := f.resolver.Mutation().StopTimer(ctx)
+			return err
+		},
+		"addTimeEntry": func() error {
+			_, err := f.resolver.Mutation().AddTimeEntry(ctx, model.AddTimeEntryInput{TaskID: task.ID, StartedAt: now.Add(-time.Hour), EndedAt: now})
+			return err
+		},
+		"deleteTimeEntry": func() error {
+			_, err := f.resolver.Mutation().DeleteTimeEntry(ctx, "entry")
+			return err
+		},
+		"createSprint": func() error {
+			_, err := f.resolver.Mutation().CreateSprint(ctx, model.CreateSprintInput{Name: "Sprint 1", StartDate: now, EndDate: now.AddDate(0, 0, 14)})
+			return err
+		},
+		"addTaskToSprint": func() error {
+			_, err := f.resolver.Mutation().AddTaskToSprint(ctx, "sprint", task.ID)
+			return err
+		},
+		"removeTaskFromSprint": func() error {
+			_, err := f.resolver.Mutation().RemoveTaskFromSprint(ctx, "sprint", task.ID)
+			return err
+		},
+		"uploadAttachment": func() error {
+			_, err := f.resolver.Mutation().UploadAttachment(ctx, task.ID, graphql.Upload{File: strings.NewReader("notes"), Filename: "notes.txt", Size: 5})
+			return err
+		},
+		"deleteAttachment": func() error {
+			_, err := f.resolver.Mutation().DeleteAttachment(ctx, "attachment")
+			return err
+		},
+	}
+	for name, mutate := range mutations {
+		if err := mutate(); !errors.Is(err, verification.ErrNotVerified) {
+			t.Errorf("Expected unverified users to be kept from %s, got %v", name, err)
+		}
+	}
+
+	if ok, err := f.resolver.Mutation().ResendVerification(ctx); err != nil || !ok {
+		t.Fatalf("ResendVerification() = %v, %v", ok, err)
+	}
+	files, _ := filepath.Glob(filepath.Join(f.mailDir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected a new link to be mailed, got %v", files)
+	}
+
+	if _, err := f.store.Users().MarkEmailVerified(context.Background(), f.alice.ID, f.alice.Email); err != nil {
+		t.Fatalf("MarkEmailVerified() error = %v", err)
+	}
+	if _, err := f.resolver.Mutation().CreateTask(ctx, model.CreateTaskInput{Title: "Write report"}); err != nil {
+		t.Errorf("Expected verified users to create tasks, got %v", err)
+	}
+}
+
+func TestFileSender(t *testing.T) {
+	dir := t.TempDir()
+	sender, err := mail.NewFileSender(dir)
+	if err != nil {
+		t.Fatalf("NewFileSender() error = %v", err)
+	}
+
+	if err := sender.Send(context.Background(), mail.Message{To: "carol@example.com\r\nBcc: eve@example.com", Subject: "Hello", Body: "Line one\nLine two"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.eml"))
+	if len(files) != 1 {
+		t.Fatalf("Expected one file, got %v", files)
+	}
+	data, _ := os.ReadFile(files[0])
+	if strings.Contains(string(data), "\r\nBcc:") {
+		t.Errorf("Expected header injection to be stripped, got %q", data)
+	}
+	if !strings.HasSuffix(string(data), "\r\n\r\nLine one\r\nLine two") {
+		t.Errorf("Expected the body after the headers, got %q", data)
+	}
+}
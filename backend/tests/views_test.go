@@ -0,0 +1,194 @@
+package tests
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"taskboard/graph/model"
+	"taskboard/internal/repository"
+	"taskboard/internal/views"
+)
+
+func TestViews_EncodeDecode(t *testing.T) {
+	query := views.Query{
+		Filter: views.Filter{
+			Status:          "TODO",
+			AssignedToID:    "user-1",
+			IncludeArchived: true,
+			Or:              []views.Filter{{PriorityIn: []string{"HIGH", "URGENT"}}, {Not: &views.Filter{TitleContains: "draft"}}},
+		},
+		Sort: &views.Sort{Field: "due_date", Descending: true},
+	}
+
+	data, err := views.Encode(query)
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+
+	decoded, err := views.Decode(data)
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if decoded.Version != views.Version || !reflect.DeepEqual(decoded.Filter, query.Filter) || *decoded.Sort != *query.Sort {
+		t.Errorf("Expected %+v to round-trip, got %+v", query, decoded)
+	}
+
+	filter := decoded.TaskFilter()
+	if filter["status"] != "TODO" || filter["include_archived"] != true || filter["sort"] != "due_date" || filter["sort_desc"] != true {
+		t.Errorf("Unexpected repository filter %v", filter)
+	}
+	if _, ok := filter["priority"]; ok {
+		t.Error("Expected empty fields to be left out of the repository filter")
+	}
+	or, _ := filter["or"].([]map[string]interface{})
+	if len(or) != 2 || or[1]["not"] == nil {
+		t.Errorf("Expected nested filters in the repository filter, got %v", filter["or"])
+	}
+
+	if _, err := views.Encode(views.Query{Sort: &views.Sort{Field: "password_hash"}}); err == nil {
+		t.Error("Expected an unknown sort field to be rejected")
+	}
+}
+
+func TestViews_DecodeVersions(t *testing.T) {
+	tests := []struct {
+		name     string
+		document string
+		wantErr  error
+	}{
+		{"Current version", `{"version":1,"filter":{"priority":"HIGH"}}`, nil},
+		{"Fields from a later addition", `{"version":1,"filter":{"priority":"HIGH","labels":["bug"]}}`, nil},
+		{"Missing version", `{"filter":{"priority":"HIGH"}}`, views.ErrUnsupportedVersion},
+		{"Newer version", `{"version":99,"filter":{}}`, views.ErrUnsupportedVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := views.Decode([]byte(tt.document))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && query.Filter.Priority != "HIGH" {
+				t.Errorf("Expected priority filter to survive, got %+v", query.Filter)
+			}
+		})
+	}
+}
+
+func TestResolver_SavedViews(t *testing.T) {
+	f := newResolverFixture(t)
+	high := model.PriorityHigh
+
+	shared, err := f.resolver.Mutation().SaveView(as(f.alice), model.SaveViewInput{
+		Name:   "Urgent work",
+		Filter: &model.TaskFilterInput{Priority: &high},
+		Sort:   &model.TaskSortInput{Field: model.TaskSortFieldTitle, Direction: model.SortDirectionAsc},
+		Shared: true,
+	})
+	if err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+	private, err := f.resolver.Mutation().SaveView(as(f.alice), model.SaveViewInput{Name: "Mine", Pinned: true})
+	if err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+
+	if _, err := f.resolver.Mutation().SaveView(as(f.alice), model.SaveViewInput{Name: "urgent WORK"}); !errors.Is(err, repository.ErrViewNameTaken) {
+		t.Errorf("Expected duplicate name to be rejected, got %v", err)
+	}
+
+	aliceViews, _ := f.resolver.Query().Views(as(f.alice))
+	if len(aliceViews) != 2 || aliceViews[0].ID != private.ID {
+		t.Errorf("Expected pinned view first, got %d views", len(aliceViews))
+	}
+	bobViews, _ := f.resolver.Query().Views(as(f.bob))
+	if len(bobViews) != 1 || bobViews[0].ID != shared.ID {
+		t.Errorf("Expected Bob to see only the shared view, got %d views", len(bobViews))
+	}
+
+	if _, err := f.resolver.Query().TasksForView(as(f.bob), private.ID, nil, nil); err == nil || err.Error() != "view not found" {
+		t.Errorf("Expected private view to be hidden from Bob, got %v", err)
+	}
+	name := "Hijacked"
+	if _, err := f.resolver.Mutation().UpdateView(as(f.bob), shared.ID, model.UpdateViewInput{Name: &name}); err == nil || !strings.HasPrefix(err.Error(), "unauthorized") {
+		t.Errorf("Expected Bob not to change a shared view, got %v", err)
+	}
+
+	sort, err := f.resolver.SavedView().Sort(as(f.alice), shared)
+	if err != nil || sort == nil || sort.Field != model.TaskSortFieldTitle || sort.Direction != model.SortDirectionAsc {
+		t.Errorf("Expected title sort, got %+v (%v)", sort, err)
+	}
+	filter, err := f.resolver.SavedView().Filter(as(f.alice), shared)
+	if err != nil || filter.Priority == nil || *filter.Priority != high {
+		t.Errorf("Expected priority filter, got %+v (%v)", filter, err)
+	}
+
+	updated, err := f.resolver.Mutation().UpdateView(as(f.alice), shared.ID, model.UpdateViewInput{
+		Sort: &model.TaskSortInput{Field: model.TaskSortFieldTitle, Direction: model.SortDirectionDesc},
+	})
+	if err != nil {
+		t.Fatalf("UpdateView() error = %v", err)
+	}
+	filter, _ = f.resolver.SavedView().Filter(as(f.alice), updated)
+	if filter.Priority == nil || *filter.Priority != high {
+		t.Error("Expected the filter to be kept when only the sort changes")
+	}
+
+	if ok, err := f.resolver.Mutation().DeleteView(as(f.alice), private.ID); !ok || err != nil {
+		t.Errorf("DeleteView() = %v, %v", ok, err)
+	}
+}
+
+func TestResolver_TasksForViewPagination(t *testing.T) {
+	f := newResolverFixture(t)
+	high := model.PriorityHigh
+
+	for _, title := range []string{"bravo", "Delta", "alpha", "charlie"} {
+		f.createTask(t, f.alice, model.CreateTaskInput{Title: title, Priority: &high})
+	}
+	f.createTask(t, f.alice, model.CreateTaskInput{Title: "aardvark"})
+
+	view, err := f.resolver.Mutation().SaveView(as(f.alice), model.SaveViewInput{
+		Name:   "By title",
+		Filter: &model.TaskFilterInput{Priority: &high},
+		Sort:   &model.TaskSortInput{Field: model.TaskSortFieldTitle, Direction: model.SortDirectionAsc},
+	})
+	if err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+
+	var titles []string
+	var after *string
+	for page := 0; page < 3; page++ {
+		first := 3
+		connection, err := f.resolver.Query().TasksForView(as(f.alice), view.ID, &first, after)
+		if err != nil {
+			t.Fatalf("TasksForView() error = %v", err)
+		}
+		if connection.TotalCount != 4 {
+			t.Errorf("Expected total count 4, got %d", connection.TotalCount)
+		}
+		for _, edge := range connection.Edges {
+			titles = append(titles, edge.Node.Title)
+		}
+		if !connection.PageInfo.HasNextPage {
+			break
+		}
+		after = connection.PageInfo.EndCursor
+	}
+
+	if got := strings.Join(titles, ","); got != "alpha,bravo,charlie,Delta" {
+		t.Errorf("Expected tasks in title order across pages, got %s", got)
+	}
+
+	invalid := "not-a-cursor"
+	if _, err := f.resolver.Query().TasksForView(as(f.alice), view.ID, nil, &invalid); err == nil {
+		t.Error("Expected an invalid cursor to be rejected")
+	}
+	tooMany := 1000
+	if _, err := f.resolver.Query().TasksForView(as(f.alice), view.ID, &tooMany, nil); err == nil {
+		t.Error("Expected first above the maximum to be rejected")
+	}
+}